@@ -0,0 +1,273 @@
+// Copyright (c) 2018 SpiralScout
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/spiral/roadrunner/cmd/util"
+	"github.com/spiral/roadrunner/service"
+)
+
+// includeSection contains list of the included config files.
+const includeSection = "include"
+
+var dumpJSON bool
+
+func init() {
+	CLI.AddCommand(&cobra.Command{
+		Use:   "config:validate",
+		Short: "Validate configuration, reports unknown config keys",
+		RunE:  validateHandler,
+	})
+
+	dumpCommand := &cobra.Command{
+		Use:   "config:dump",
+		Short: "Show effective configuration with defaults and sources of the values",
+		RunE:  dumpHandler,
+	}
+	dumpCommand.Flags().BoolVar(&dumpJSON, "json", false, "render configuration as JSON")
+	CLI.AddCommand(dumpCommand)
+
+	CLI.AddCommand(&cobra.Command{
+		Use:   "config:schema",
+		Short: "Export JSON schema of the configuration",
+		RunE:  schemaHandler,
+	})
+}
+
+func validateHandler(cmd *cobra.Command, args []string) error {
+	settings, err := loadedSettings()
+	if err != nil {
+		return err
+	}
+
+	sections := append(Container.List(), service.RestartSection, service.StartupSection, includeSection)
+
+	var issues []string
+	for _, name := range sortedSections(settings) {
+		switch name {
+		case includeSection:
+			continue
+		case service.RestartSection:
+			issues = append(issues, util.CheckConfig(name, map[string]*service.RestartPolicy{}, settings[name])...)
+			if policies, ok := settings[name].(map[string]interface{}); ok {
+				for svc := range policies {
+					if !Container.Has(svc) {
+						issues = append(issues, fmt.Sprintf("restart policy of unknown service `%s`", svc))
+					}
+				}
+			}
+		case service.StartupSection:
+			issues = append(issues, util.CheckConfig(name, &service.StartupConfig{}, settings[name])...)
+		default:
+			if !Container.Has(name) {
+				if s := util.Suggest(name, sections); s != "" {
+					issues = append(issues, fmt.Sprintf("unknown section `%s`, did you mean `%s`?", name, s))
+				} else {
+					issues = append(issues, fmt.Sprintf("unknown section `%s`", name))
+				}
+				continue
+			}
+
+			svc, _ := Container.Get(name)
+			cfg, err := service.NewConfig(svc)
+			if err != nil {
+				issues = append(issues, fmt.Sprintf("[%s]: %s", name, err))
+				continue
+			}
+
+			if cfg != nil {
+				issues = append(issues, util.CheckConfig(name, util.ConfigValue(cfg), settings[name])...)
+			}
+		}
+	}
+
+	if len(issues) == 0 {
+		util.Printf("<green+hb>Configuration is valid</reset>\n")
+		return nil
+	}
+
+	for _, issue := range issues {
+		util.Printf("<red>%s</reset>\n", issue)
+	}
+
+	return fmt.Errorf("configuration has %d issue(s)", len(issues))
+}
+
+func dumpHandler(cmd *cobra.Command, args []string) error {
+	settings, err := loadedSettings()
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]interface{})
+	for _, name := range Container.List() {
+		svc, st := Container.Get(name)
+		if _, ok := settings[name]; !ok && st < service.StatusOK {
+			continue
+		}
+
+		cfg, err := service.NewConfig(svc)
+		if err != nil {
+			return err
+		}
+
+		if cfg == nil {
+			merge(values, util.FlattenConfig(name, settings[name]))
+			continue
+		}
+
+		if section := config.Get(name); section != nil {
+			if err := cfg.Hydrate(section); err != nil {
+				return fmt.Errorf("[%s]: %s", name, err)
+			}
+		}
+
+		merge(values, util.FlattenConfig(name, util.ConfigValue(cfg)))
+	}
+
+	policies := make(map[string]*service.RestartPolicy)
+	if section := config.Get(service.RestartSection); section != nil {
+		if err := section.Unmarshal(&policies); err != nil {
+			return fmt.Errorf("[%s]: %s", service.RestartSection, err)
+		}
+	}
+	for name, p := range policies {
+		if p == nil {
+			p = &service.RestartPolicy{}
+			policies[name] = p
+		}
+		p.InitDefaults()
+	}
+	merge(values, util.FlattenConfig(service.RestartSection, policies))
+
+	startup := &service.StartupConfig{}
+	if section := config.Get(service.StartupSection); section != nil {
+		if err := section.Unmarshal(startup); err != nil {
+			return fmt.Errorf("[%s]: %s", service.StartupSection, err)
+		}
+	}
+	startup.InitDefaults()
+	merge(values, util.FlattenConfig(service.StartupSection, startup))
+
+	merge(values, util.FlattenConfig(includeSection, settings[includeSection]))
+
+	entries := make([]util.ConfigEntry, 0, len(values))
+	for key, value := range values {
+		// values which are not set by any of the config layers are defaults
+		source := config.Source(key)
+		if source == "" {
+			if util.IsEmpty(value) {
+				continue
+			}
+			source = "default"
+		}
+
+		entries = append(entries, util.ConfigEntry{Key: key, Value: value, Source: source})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	if dumpJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	util.ConfigTable(entries).Render()
+	return nil
+}
+
+func schemaHandler(cmd *cobra.Command, args []string) error {
+	properties := make(map[string]interface{})
+	for _, name := range Container.List() {
+		svc, _ := Container.Get(name)
+		cfg, err := service.NewConfig(svc)
+		if err != nil {
+			return err
+		}
+
+		if cfg == nil {
+			properties[name] = map[string]interface{}{"type": "object"}
+			continue
+		}
+
+		properties[name] = util.ConfigSchema(util.ConfigValue(cfg))
+	}
+
+	policy := &service.RestartPolicy{}
+	policy.InitDefaults()
+	properties[service.RestartSection] = map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": util.ConfigSchema(policy),
+	}
+
+	startup := &service.StartupConfig{}
+	startup.InitDefaults()
+	properties[service.StartupSection] = util.ConfigSchema(startup)
+
+	properties[includeSection] = map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"type": "string"},
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]interface{}{
+		"$schema":    "http://json-schema.org/draft-07/schema#",
+		"title":      "RoadRunner configuration",
+		"type":       "object",
+		"properties": properties,
+	})
+}
+
+// loadedSettings returns all values of the loaded configuration.
+func loadedSettings() (map[string]interface{}, error) {
+	if config == nil {
+		if configErr != nil {
+			return nil, configErr
+		}
+
+		return nil, errors.New("configuration is not loaded")
+	}
+
+	return config.Settings(), nil
+}
+
+func sortedSections(settings map[string]interface{}) []string {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func merge(to, from map[string]interface{}) {
+	for k, v := range from {
+		to[k] = v
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2018 SpiralScout
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// +build !windows
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/spiral/roadrunner/service"
+	rrutil "github.com/spiral/roadrunner/util"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// upgradeReadyEnv contains file descriptor used by the upgraded process to notify the parent about it's readiness.
+const upgradeReadyEnv = "RR_UPGRADE_READY"
+
+// notifyUpgrade relays upgrade requests (SIGUSR2) to the given channel.
+func notifyUpgrade(c chan os.Signal) {
+	signal.Notify(c, syscall.SIGUSR2)
+}
+
+// upgrade starts new server binary which inherits all active listeners and waits for it to become ready. Parent
+// process must stop once upgrade is complete. Worker relay connections are accepted by the new binary only while
+// upgrade is in progress.
+func upgrade() (err error) {
+	files, env, err := rrutil.ExportListeners()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+
+		if err != nil {
+			rrutil.ResumeListeners()
+		}
+	}()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	bin, err := os.Executable()
+	if err != nil {
+		_ = w.Close()
+		return err
+	}
+
+	cmd := exec.Command(bin, os.Args[1:]...)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	cmd.Env = append(os.Environ(), env, fmt.Sprintf("%s=%v", upgradeReadyEnv, 3+len(files)))
+	cmd.ExtraFiles = append(files, w)
+
+	err = cmd.Start()
+	_ = w.Close()
+	if err != nil {
+		return errors.Wrap(err, "unable to start new binary")
+	}
+
+	ready := make(chan error, 1)
+	go func() {
+		_, err := r.Read(make([]byte, 1))
+		ready <- err
+	}()
+
+	select {
+	case err = <-ready:
+		if err == io.EOF {
+			err = errors.New("new binary exited before becoming ready")
+		}
+	case <-time.After(upgradeTimeout):
+		err = fmt.Errorf("new binary is not ready after %s", upgradeTimeout)
+	}
+
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
+	}
+
+	// closing of the parent listeners must not affect the upgraded process
+	rrutil.DetachListeners()
+	return cmd.Process.Release()
+}
+
+// signalReady notifies the parent process about upgraded server readiness, does nothing if server
+// was not started by the upgrade.
+func signalReady(c service.Container) {
+	value := os.Getenv(upgradeReadyEnv)
+	if value == "" {
+		return
+	}
+	_ = os.Unsetenv(upgradeReadyEnv)
+
+	fd, err := strconv.Atoi(value)
+	if err != nil {
+		return
+	}
+
+	// must not be passed to the workers
+	syscall.CloseOnExec(fd)
+	f := os.NewFile(uintptr(fd), upgradeReadyEnv)
+
+	go func() {
+		defer f.Close()
+
+		if !waitReady(c, upgradeTimeout) {
+			Logger.Errorf("upgrade: server is not ready after %s", upgradeTimeout)
+			return
+		}
+
+		rrutil.CloseInherited()
+		if _, err := f.Write([]byte{1}); err != nil {
+			Logger.Errorf("upgrade: %s", err)
+		}
+	}()
+}
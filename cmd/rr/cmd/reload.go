@@ -0,0 +1,117 @@
+// Copyright (c) 2018 SpiralScout
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spiral/roadrunner/cmd/util"
+	"github.com/spiral/roadrunner/service"
+	"github.com/spiral/roadrunner/service/rpc"
+)
+
+func init() {
+	CLI.AddCommand(&cobra.Command{
+		Use:   "reload",
+		Short: "Reload RoadRunner config and apply it to the running services",
+		RunE:  reloadHandler,
+	})
+}
+
+func reloadHandler(cmd *cobra.Command, args []string) error {
+	client, err := util.RPCClient(Container)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	util.Printf("<green>Reloading RoadRunner config</reset>: ")
+
+	var r []rpc.ReloadResult
+	if err := client.Call("system.Reload", true, &r); err != nil {
+		return err
+	}
+
+	util.Printf("<green+hb>done</reset>\n")
+
+	var failed []string
+	for _, res := range r {
+		switch {
+		case res.Error != "":
+			util.Printf("<white+hb>%s</reset>: <red>%s</reset>\n", res.Service, res.Error)
+			failed = append(failed, res.Service)
+		case res.RestartRequired:
+			util.Printf("<white+hb>%s</reset>: <yellow>restart is required</reset>\n", res.Service)
+		default:
+			util.Printf("<white+hb>%s</reset>: <green>reconfigured</reset>\n", res.Service)
+		}
+	}
+
+	if len(failed) != 0 {
+		return fmt.Errorf("unable to reconfigure services: %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// reloadMu serializes reloads requested by the signal and by the rpc.
+var reloadMu sync.Mutex
+
+// reloadConfig loads configuration using the same file, profile and flags as at the server start and applies it
+// to the services which config has changed.
+func reloadConfig() ([]service.ReconfigureResult, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	if config == nil {
+		return nil, errors.New("server has been started without config")
+	}
+
+	file := config.File()
+	if file == "" {
+		file = cfgFile
+	}
+
+	cfg, err := util.LoadConfig(file, []string{"."}, ".rr", profile, override, mergeJson)
+	if err != nil {
+		return nil, err
+	}
+
+	// loading of the config file changes working directory
+	if workDir != "" {
+		if err := os.Chdir(workDir); err != nil {
+			return nil, err
+		}
+	}
+
+	results, err := Container.Reconfigure(cfg)
+	if err != nil {
+		return nil, err
+	}
+	config = cfg
+
+	return results, nil
+}
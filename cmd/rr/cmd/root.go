@@ -26,17 +26,24 @@ import (
 	"github.com/spiral/roadrunner/cmd/util"
 	"github.com/spiral/roadrunner/service"
 	"github.com/spiral/roadrunner/service/limit"
+	"github.com/spiral/roadrunner/service/rpc"
+	rrutil "github.com/spiral/roadrunner/util"
 	"log"
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"strings"
 )
 
 // Services bus for all the commands.
 var (
-	cfgFile, workDir, logFormat string
-	override                    []string
-	mergeJson                   string
+	cfgFile, workDir, logFormat, profile string
+	override                             []string
+	mergeJson                            string
+
+	// loaded configuration and it's loading error
+	config    *util.ConfigWrapper
+	configErr error
 
 	// Verbose enables verbosity mode (container specific).
 	Verbose bool
@@ -78,6 +85,13 @@ func init() {
 	CLI.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file (default is .rr.yaml)")
 	CLI.PersistentFlags().StringVarP(&workDir, "workDir", "w", "", "work directory")
 	CLI.PersistentFlags().StringVarP(&mergeJson, "jsonConfig", "j", "", "merge json configuration")
+	CLI.PersistentFlags().StringVarP(
+		&profile,
+		"profile",
+		"p",
+		os.Getenv("RR_PROFILE"),
+		"config profile, merges .rr.<profile>.yaml on top of the config (default is $RR_PROFILE)",
+	)
 
 	CLI.PersistentFlags().StringArrayVarP(
 		&override,
@@ -94,11 +108,18 @@ func init() {
 
 		configureLogger(logFormat)
 
-		cfg, err := util.LoadConfig(cfgFile, []string{"."}, ".rr", override, mergeJson)
+		cfg, err := util.LoadConfig(cfgFile, []string{"."}, ".rr", profile, override, mergeJson)
 		if err != nil {
+			if _, ok := err.(*util.InterpolationError); ok {
+				util.ExitWithError(err)
+			}
+
+			configErr = err
 			Logger.Warnf("config: %s", err)
 			return
 		}
+		config = cfg
+		Logger.Debugf("config: %s", strings.Join(cfg.Layers(), ", "))
 
 		if workDir != "" {
 			if err := os.Chdir(workDir); err != nil {
@@ -110,6 +131,11 @@ func init() {
 			util.ExitWithError(err)
 		}
 
+		// config reload requested over RPC
+		if svc, st := Container.Get(rpc.ID); st >= service.StatusOK {
+			svc.(*rpc.Service).OnReload(reloadConfig)
+		}
+
 		// global watcher config
 		if Verbose {
 			wcv, _ := Container.Get(limit.ID)
@@ -138,7 +164,12 @@ func runDebugServer() {
 		Handler: mux,
 	}
 
-	if err := srv.ListenAndServe(); err != nil {
+	ln, err := rrutil.Listen("tcp", srv.Addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := srv.Serve(ln); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -42,16 +42,47 @@ func serveHandler(cmd *cobra.Command, args []string) error {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 
+	// binary upgrade requests
+	u := make(chan os.Signal, 1)
+	notifyUpgrade(u)
+
+	// config reload requests
+	h := make(chan os.Signal, 1)
+	signal.Notify(h, syscall.SIGHUP)
+
 	wg := &sync.WaitGroup{}
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		// get the signal
-		<-c
-		Container.Stop()
+		for {
+			// get the signal
+			select {
+			case <-c:
+				Container.Stop()
+				return
+			case <-h:
+				Logger.Info("reloading config")
+				if _, err := reloadConfig(); err != nil {
+					Logger.Errorf("reload: %s", err)
+				}
+			case <-u:
+				Logger.Info("upgrading server binary")
+				if err := upgrade(); err != nil {
+					Logger.Errorf("upgrade: %s", err)
+					continue
+				}
+
+				// new binary accepts connections since this moment
+				Container.Stop()
+				return
+			}
+		}
 	}()
 
+	// notify parent process if server has been started by the binary upgrade
+	signalReady(Container)
+
 	// blocking operation
 	if err := Container.Serve(); err != nil {
 		return err
@@ -0,0 +1,90 @@
+// Copyright (c) 2018 SpiralScout
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spiral/roadrunner/cmd/util"
+	"github.com/spiral/roadrunner/service/rpc"
+)
+
+var statusJSON bool
+
+func init() {
+	statusCommand := &cobra.Command{
+		Use:   "status [service]",
+		Short: "Show status of RoadRunner services, fails if any service is unhealthy",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  statusHandler,
+	}
+
+	statusCommand.Flags().BoolVar(&statusJSON, "json", false, "render status as JSON")
+
+	CLI.AddCommand(statusCommand)
+}
+
+func statusHandler(cmd *cobra.Command, args []string) error {
+	client, err := util.RPCClient(Container)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var services []rpc.ServiceStatus
+	if len(args) == 0 {
+		if err := client.Call("system.Services", true, &services); err != nil {
+			return err
+		}
+	} else {
+		var s rpc.ServiceStatus
+		if err := client.Call("system.Status", args[0], &s); err != nil {
+			return err
+		}
+		services = append(services, s)
+	}
+
+	if statusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(services); err != nil {
+			return err
+		}
+	} else {
+		util.ServiceTable(services).Render()
+	}
+
+	var unhealthy []string
+	for _, s := range services {
+		if !s.Healthy {
+			unhealthy = append(unhealthy, s.Name)
+		}
+	}
+
+	if len(unhealthy) != 0 {
+		return fmt.Errorf("unhealthy services: %s", strings.Join(unhealthy, ", "))
+	}
+
+	return nil
+}
@@ -71,6 +71,19 @@ func (s *debugger) listener(event int, ctx interface{}) {
 				e.Error,
 			))
 		}
+
+	case rrhttp.EventTimeout:
+		e := ctx.(*rrhttp.ErrorEvent)
+		s.logger.Warning(util.Sprintf(
+			"<cyan+h>%s</reset> %s %s <white+hb>%s</reset> %s <yellow>%s (pool %s)</reset>",
+			addr(e.Request.RemoteAddr),
+			elapsed(e.Elapsed()),
+			statusColor(504),
+			e.Request.Method,
+			uri(e.Request),
+			e.Error,
+			e.Pool,
+		))
 	}
 }
 
@@ -27,6 +27,7 @@ func init() {
 			mtr.MustRegister(collector.requestCounter)
 			mtr.MustRegister(collector.requestDuration)
 			mtr.MustRegister(collector.workersMemory)
+			mtr.MustRegister(collector.requestTimeouts)
 
 			// collect events
 			ht.AddListener(collector.listener)
@@ -41,7 +42,8 @@ func init() {
 type metricCollector struct {
 	requestCounter  *prometheus.CounterVec
 	requestDuration *prometheus.HistogramVec
-	workersMemory   prometheus.Gauge
+	workersMemory   *prometheus.GaugeVec
+	requestTimeouts *prometheus.CounterVec
 }
 
 func newCollector() *metricCollector {
@@ -51,20 +53,28 @@ func newCollector() *metricCollector {
 				Name: "rr_http_request_total",
 				Help: "Total number of handled http requests after server restart.",
 			},
-			[]string{"status"},
+			[]string{"status", "pool"},
 		),
 		requestDuration: prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name: "rr_http_request_duration_seconds",
 				Help: "HTTP request duration.",
 			},
-			[]string{"status"},
+			[]string{"status", "pool"},
 		),
-		workersMemory: prometheus.NewGauge(
+		workersMemory: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "rr_http_workers_memory_bytes",
 				Help: "Memory usage by HTTP workers.",
 			},
+			[]string{"pool"},
+		),
+		requestTimeouts: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "rr_http_request_timeouts_total",
+				Help: "Total number of http requests which have not been served within the execution deadline.",
+			},
+			[]string{"pool"},
 		),
 	}
 }
@@ -78,26 +88,45 @@ func (c *metricCollector) listener(event int, ctx interface{}) {
 
 		c.requestCounter.With(prometheus.Labels{
 			"status": strconv.Itoa(e.Response.Status),
+			"pool":   e.Pool,
 		}).Inc()
 
 		c.requestDuration.With(prometheus.Labels{
 			"status": strconv.Itoa(e.Response.Status),
+			"pool":   e.Pool,
 		}).Observe(e.Elapsed().Seconds())
 
 	case rrhttp.EventError:
 		e := ctx.(*rrhttp.ErrorEvent)
 
 		c.requestCounter.With(prometheus.Labels{
-			"status": "500",
+			"status": strconv.Itoa(e.Status),
+			"pool":   e.Pool,
+		}).Inc()
+
+		c.requestDuration.With(prometheus.Labels{
+			"status": strconv.Itoa(e.Status),
+			"pool":   e.Pool,
+		}).Observe(e.Elapsed().Seconds())
+
+	case rrhttp.EventTimeout:
+		e := ctx.(*rrhttp.ErrorEvent)
+
+		c.requestTimeouts.With(prometheus.Labels{"pool": e.Pool}).Inc()
+
+		c.requestCounter.With(prometheus.Labels{
+			"status": strconv.Itoa(e.Status),
+			"pool":   e.Pool,
 		}).Inc()
 
 		c.requestDuration.With(prometheus.Labels{
-			"status": "500",
+			"status": strconv.Itoa(e.Status),
+			"pool":   e.Pool,
 		}).Observe(e.Elapsed().Seconds())
 	}
 }
 
-// collect memory usage by server workers
+// collect memory usage by server workers of each pool
 func (c *metricCollector) collectMemory(service *rrhttp.Service, tick time.Duration) {
 	started := false
 	for {
@@ -109,13 +138,15 @@ func (c *metricCollector) collectMemory(service *rrhttp.Service, tick time.Durat
 
 		started = true
 
-		if workers, err := util.ServerState(server); err == nil {
-			sum := 0.0
-			for _, w := range workers {
-				sum = sum + float64(w.MemoryUsage)
-			}
+		for _, pool := range service.Pools() {
+			if workers, err := util.ServerState(service.Pool(pool)); err == nil {
+				sum := 0.0
+				for _, w := range workers {
+					sum = sum + float64(w.MemoryUsage)
+				}
 
-			c.workersMemory.Set(sum)
+				c.workersMemory.With(prometheus.Labels{"pool": pool}).Set(sum)
+			}
 		}
 
 		time.Sleep(tick)
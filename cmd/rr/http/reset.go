@@ -26,12 +26,18 @@ import (
 	"github.com/spiral/roadrunner/cmd/util"
 )
 
+var resetPool string
+
 func init() {
-	rr.CLI.AddCommand(&cobra.Command{
+	resetCommand := &cobra.Command{
 		Use:   "http:reset",
 		Short: "Reload RoadRunner worker pool for the HTTP service",
 		RunE:  reloadHandler,
-	})
+	}
+
+	resetCommand.Flags().StringVar(&resetPool, "pool", "", "reset the named worker pool")
+
+	rr.CLI.AddCommand(resetCommand)
 }
 
 func reloadHandler(cmd *cobra.Command, args []string) error {
@@ -41,11 +47,17 @@ func reloadHandler(cmd *cobra.Command, args []string) error {
 	}
 	defer client.Close()
 
-	util.Printf("<green>Restarting http worker pool</reset>: ")
-
 	var r string
-	if err := client.Call("http.Reset", true, &r); err != nil {
-		return err
+	if resetPool != "" {
+		util.Printf("<green>Restarting http worker pool `%s`</reset>: ", resetPool)
+		if err := client.Call("http.ResetPool", resetPool, &r); err != nil {
+			return err
+		}
+	} else {
+		util.Printf("<green>Restarting http worker pool</reset>: ")
+		if err := client.Call("http.Reset", true, &r); err != nil {
+			return err
+		}
 	}
 
 	util.Printf("<green+hb>done</reset>\n")
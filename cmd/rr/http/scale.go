@@ -0,0 +1,61 @@
+// Copyright (c) 2018 SpiralScout
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package http
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	rr "github.com/spiral/roadrunner/cmd/rr/cmd"
+	"github.com/spiral/roadrunner/cmd/util"
+	"strconv"
+)
+
+func init() {
+	rr.CLI.AddCommand(&cobra.Command{
+		Use:   "http:scale [numWorkers]",
+		Short: "Change number of workers in RoadRunner worker pool for the HTTP service",
+		Args:  cobra.ExactArgs(1),
+		RunE:  scaleHandler,
+	})
+}
+
+func scaleHandler(cmd *cobra.Command, args []string) error {
+	numWorkers, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || numWorkers <= 0 {
+		return fmt.Errorf("invalid number of workers `%s`", args[0])
+	}
+
+	client, err := util.RPCClient(rr.Container)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	util.Printf("<green>Scaling http worker pool to %v workers</reset>: ", numWorkers)
+
+	var r string
+	if err := client.Call("http.Resize", numWorkers, &r); err != nil {
+		return err
+	}
+
+	util.Printf("<green+hb>done</reset>\n")
+	return nil
+}
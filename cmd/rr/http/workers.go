@@ -35,6 +35,7 @@ import (
 
 var (
 	interactive bool
+	workersPool string
 	stopSignal  = make(chan os.Signal, 1)
 )
 
@@ -53,6 +54,8 @@ func init() {
 		"render interactive workers table",
 	)
 
+	workersCommand.Flags().StringVar(&workersPool, "pool", "", "list workers of the named worker pool")
+
 	rr.CLI.AddCommand(workersCommand)
 
 	signal.Notify(stopSignal, syscall.SIGTERM)
@@ -92,7 +95,11 @@ func workersHandler(cmd *cobra.Command, args []string) (err error) {
 
 func showWorkers(client *rpc.Client) {
 	var r http.WorkerList
-	if err := client.Call("http.Workers", true, &r); err != nil {
+	if workersPool != "" {
+		if err := client.Call("http.PoolWorkers", workersPool, &r); err != nil {
+			panic(err)
+		}
+	} else if err := client.Call("http.Workers", true, &r); err != nil {
 		panic(err)
 	}
 
@@ -53,19 +53,30 @@ func (s *debugger) listener(event int, ctx interface{}) {
 	case limit.EventMaxMemory:
 		w := ctx.(roadrunner.WorkerError)
 		s.logger.Error(util.Sprintf(
-			"<white+hb>worker.%v</reset> <red>%s</reset>",
+			"<white+hb>worker.%v</reset> <red>%s</reset>%s",
 			*w.Worker.Pid,
 			w.Caused,
+			tag(w),
 		))
 		return
 
 	case limit.EventExecTTL:
 		w := ctx.(roadrunner.WorkerError)
 		s.logger.Error(util.Sprintf(
-			"<white+hb>worker.%v</reset> <red>%s</reset>",
+			"<white+hb>worker.%v</reset> <red>%s</reset>%s",
 			*w.Worker.Pid,
 			w.Caused,
+			tag(w),
 		))
 		return
 	}
 }
+
+// tag returns description of the request executed by the worker, if any.
+func tag(w roadrunner.WorkerError) string {
+	if w.Tag == "" {
+		return ""
+	}
+
+	return util.Sprintf(" request <white+hb>%s</reset>", w.Tag)
+}
@@ -26,6 +26,8 @@ import (
 	rr "github.com/spiral/roadrunner/cmd/rr/cmd"
 
 	// services (plugins)
+	"github.com/spiral/roadrunner/service/accesslog"
+	"github.com/spiral/roadrunner/service/cache"
 	"github.com/spiral/roadrunner/service/env"
 	"github.com/spiral/roadrunner/service/gzip"
 	"github.com/spiral/roadrunner/service/headers"
@@ -33,9 +35,12 @@ import (
 	"github.com/spiral/roadrunner/service/http"
 	"github.com/spiral/roadrunner/service/limit"
 	"github.com/spiral/roadrunner/service/metrics"
+	"github.com/spiral/roadrunner/service/plugins"
+	"github.com/spiral/roadrunner/service/ratelimit"
 	"github.com/spiral/roadrunner/service/reload"
 	"github.com/spiral/roadrunner/service/rpc"
 	"github.com/spiral/roadrunner/service/static"
+	"github.com/spiral/roadrunner/service/tracing"
 
 	// additional commands and debug handlers
 	_ "github.com/spiral/roadrunner/cmd/rr/http"
@@ -49,10 +54,15 @@ func main() {
 	rr.Container.Register(metrics.ID, &metrics.Service{})
 	rr.Container.Register(headers.ID, &headers.Service{})
 	rr.Container.Register(static.ID, &static.Service{})
+	rr.Container.Register(cache.ID, &cache.Service{})
+	rr.Container.Register(ratelimit.ID, &ratelimit.Service{})
 	rr.Container.Register(limit.ID, &limit.Service{})
 	rr.Container.Register(health.ID, &health.Service{})
 	rr.Container.Register(gzip.ID, &gzip.Service{})
 	rr.Container.Register(reload.ID, &reload.Service{})
+	rr.Container.Register(plugins.ID, &plugins.Service{})
+	rr.Container.Register(accesslog.ID, &accesslog.Service{})
+	rr.Container.Register(tracing.ID, &tracing.Service{})
 
 	// you can register additional commands using cmd.CLI
 	rr.Execute()
@@ -13,6 +13,15 @@ import (
 // ConfigWrapper provides interface bridge between v configs and service.Config.
 type ConfigWrapper struct {
 	v *viper.Viper
+
+	// origin of the config values by their keys
+	sources map[string]string
+
+	// names of the merged config layers
+	layers []string
+
+	// absolute path of the main config file, if any
+	file string
 }
 
 // Get nested config section (sub-map), returns nil if section not found.
@@ -22,7 +31,7 @@ func (w *ConfigWrapper) Get(key string) service.Config {
 		return nil
 	}
 
-	return &ConfigWrapper{sub}
+	return &ConfigWrapper{v: sub}
 }
 
 // Unmarshal unmarshal config data into given struct.
@@ -30,9 +39,32 @@ func (w *ConfigWrapper) Unmarshal(out interface{}) error {
 	return w.v.Unmarshal(out)
 }
 
-// LoadConfig config and merge it's values with set of flags.
-func LoadConfig(cfgFile string, path []string, name string, flags []string, jsonConfig string) (*ConfigWrapper, error) {
-	cfg := viper.New()
+// Settings returns all config values as nested map with lowercase keys.
+func (w *ConfigWrapper) Settings() map[string]interface{} {
+	return w.v.AllSettings()
+}
+
+// Source returns origin of the config value (file, include, env or flag) by it's lowercase key in dot notation.
+// Returns empty string if value has not been set.
+func (w *ConfigWrapper) Source(key string) string {
+	return w.sources[strings.ToLower(key)]
+}
+
+// Layers returns names of the config layers (files, includes, profile and flags) in order of merging.
+func (w *ConfigWrapper) Layers() []string {
+	return w.layers
+}
+
+// File returns absolute path of the main config file, empty if config has been loaded without file.
+func (w *ConfigWrapper) File() string {
+	return w.file
+}
+
+// LoadConfig config and merge it's values with set of flags. Values of the profile config (.rr.<profile>.yaml) are
+// merged on top of the main config when profile is not empty.
+func LoadConfig(cfgFile string, path []string, name string, profile string, flags []string, jsonConfig string) (*ConfigWrapper, error) {
+	base := viper.New()
+	l := &layers{settings: make(map[string]interface{}), sources: make(map[string]string)}
 
 	if cfgFile != "" {
 		if absPath, err := filepath.Abs(cfgFile); err == nil {
@@ -45,64 +77,72 @@ func LoadConfig(cfgFile string, path []string, name string, flags []string, json
 		}
 
 		// Use cfg file from the flag.
-		cfg.SetConfigFile(cfgFile)
-
-		if dir, err := filepath.Abs(cfgFile); err == nil {
-			// force working absPath related to config file
-			if err := os.Chdir(filepath.Dir(dir)); err != nil {
-				return nil, err
-			}
-		}
+		base.SetConfigFile(cfgFile)
 	} else {
 		// automatic location
 		for _, p := range path {
-			cfg.AddConfigPath(p)
+			base.AddConfigPath(p)
 		}
 
-		cfg.SetConfigName(name)
+		base.SetConfigName(name)
 	}
 
-	// read in environment variables that match
-	cfg.AutomaticEnv()
-	cfg.SetEnvPrefix("rr")
-	cfg.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-
 	// If a cfg file is found, read it in.
-	if err := cfg.ReadInConfig(); err != nil {
-		if len(flags) == 0 && jsonConfig == "" {
+	if err := base.ReadInConfig(); err != nil {
+		if len(flags) == 0 && jsonConfig == "" && profile == "" {
 			return nil, err
 		}
+	} else if err := l.merge(base, "file:"+filepath.Base(base.ConfigFileUsed())); err != nil {
+		return nil, err
 	}
 
-	// merge included configs
-	if include, ok := cfg.Get("include").([]interface{}); ok {
-		for _, file := range include {
-			filename, ok := file.(string)
-			if !ok {
-				continue
+	if profile != "" {
+		overlay := viper.New()
+		if base.ConfigFileUsed() != "" {
+			overlay.SetConfigFile(profileFile(base.ConfigFileUsed(), profile))
+		} else {
+			for _, p := range path {
+				overlay.AddConfigPath(p)
 			}
 
-			partial := viper.New()
-			partial.AutomaticEnv()
-			partial.SetEnvPrefix("rr")
-			partial.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-			partial.SetConfigFile(filename)
+			overlay.SetConfigName(name + "." + profile)
+		}
 
-			if err := partial.ReadInConfig(); err != nil {
-				return nil, err
-			}
+		if err := overlay.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("profile `%s`: %s", profile, err)
+		}
 
-			// merging
-			if err := cfg.MergeConfigMap(partial.AllSettings()); err != nil {
-				return nil, err
-			}
+		if err := l.merge(overlay, "profile:"+filepath.Base(overlay.ConfigFileUsed())); err != nil {
+			return nil, err
 		}
 	}
 
-	// automatically inject ENV variables using ${ENV} pattern
+	cfg := viper.New()
+
+	// read in environment variables that match
+	cfg.AutomaticEnv()
+	cfg.SetEnvPrefix("rr")
+	cfg.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	if err := cfg.MergeConfigMap(l.settings); err != nil {
+		return nil, err
+	}
+
+	sources := l.sources
+
+	// automatically inject ENV variables and files using ${ENV} expressions
 	for _, key := range cfg.AllKeys() {
 		val := cfg.Get(key)
-		cfg.Set(key, parseEnv(val))
+
+		iv, err := interpolateValue(val)
+		if err != nil {
+			return nil, &InterpolationError{Key: key, Err: err}
+		}
+		cfg.Set(key, iv)
+
+		if _, ok := os.LookupEnv("RR_" + strings.ToUpper(strings.Replace(key, ".", "_", -1))); ok || hasExpression(val) {
+			sources[key] = "env"
+		}
 	}
 
 	// merge with console flags
@@ -114,34 +154,66 @@ func LoadConfig(cfgFile string, path []string, name string, flags []string, json
 			}
 
 			cfg.Set(k, v)
+			trackSources(sources, []string{k}, "flag")
 		}
 	}
 
+	settings := cfg.AllSettings()
+
+	// merge with JSON config, values override console flags
 	if jsonConfig != "" {
 		jConfig := viper.New()
-		jConfig.AutomaticEnv()
-		jConfig.SetEnvPrefix("rr")
-		jConfig.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-
 		jConfig.SetConfigType("json")
 		if err := jConfig.ReadConfig(bytes.NewBufferString(jsonConfig)); err != nil {
 			return nil, err
 		}
 
-		// merging
-		if err := cfg.MergeConfigMap(jConfig.AllSettings()); err != nil {
+		values := jConfig.AllSettings()
+		expressions, err := interpolateSettings(values, "")
+		if err != nil {
 			return nil, err
 		}
+
+		l.names = append(l.names, "flag")
+		mergeSettings(settings, values, "", "flag", sources)
+		for _, key := range expressions {
+			sources[key] = "env"
+		}
 	}
 
 	merged := viper.New()
 
 	// we have to copy all the merged values into new config in order normalize it (viper bug?)
-	if err := merged.MergeConfigMap(cfg.AllSettings()); err != nil {
+	if err := merged.MergeConfigMap(settings); err != nil {
 		return nil, err
 	}
 
-	return &ConfigWrapper{merged}, nil
+	file := base.ConfigFileUsed()
+	if file != "" {
+		if absPath, err := filepath.Abs(file); err == nil {
+			file = absPath
+		}
+	}
+
+	return &ConfigWrapper{v: merged, sources: sources, layers: l.names, file: file}, nil
+}
+
+// trackSources assigns source to the given config keys, nested values of the keys are overwritten as well.
+func trackSources(sources map[string]string, keys []string, source string) {
+	for _, key := range keys {
+		clearSources(sources, key)
+		sources[strings.ToLower(key)] = source
+	}
+}
+
+// clearSources removes sources of the config key and it's nested values.
+func clearSources(sources map[string]string, key string) {
+	key = strings.ToLower(key)
+	for k := range sources {
+		if k == key || strings.HasPrefix(k, key+".") {
+			delete(sources, k)
+		}
+	}
 }
 
 func parseFlag(flag string) (string, string, error) {
@@ -164,18 +236,3 @@ func parseValue(value string) string {
 
 	return value
 }
-
-func parseEnv(value interface{}) interface{} {
-	str, ok := value.(string)
-	if !ok || len(str) <= 3 {
-		return value
-	}
-
-	if str[0:2] == "${" && str[len(str)-1:] == "}" {
-		if v, ok := os.LookupEnv(str[2 : len(str)-1]); ok {
-			return v
-		}
-	}
-
-	return str
-}
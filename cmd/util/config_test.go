@@ -0,0 +1,46 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LoadConfig_FlagsAndJSON(t *testing.T) {
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	dir, err := ioutil.TempDir("", "rr")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, ".rr.yaml")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("http:\n  address: :8080\n  maxRequestSize: 10\nrpc:\n  listen: tcp://:6001\n"), 0644))
+	assert.NoError(t, os.Setenv("RR_TEST_NAME", "rr"))
+	defer os.Unsetenv("RR_TEST_NAME")
+
+	cfg, err := LoadConfig(
+		file,
+		nil,
+		".rr",
+		"",
+		[]string{"http.address=:80", "rpc.listen=tcp://:6002"},
+		`{"http":{"address":":81"},"rpc":{"name":"${RR_TEST_NAME}"}}`,
+	)
+	assert.NoError(t, err)
+
+	// json config is merged after the flags
+	settings := cfg.Settings()
+	assert.Equal(t, ":81", settings["http"].(map[string]interface{})["address"])
+	assert.Equal(t, 10, settings["http"].(map[string]interface{})["maxrequestsize"])
+	assert.Equal(t, "tcp://:6002", settings["rpc"].(map[string]interface{})["listen"])
+	assert.Equal(t, "rr", settings["rpc"].(map[string]interface{})["name"])
+
+	assert.Equal(t, "flag", cfg.Source("http.address"))
+	assert.Equal(t, "file:.rr.yaml", cfg.Source("http.maxrequestsize"))
+	assert.Equal(t, "flag", cfg.Source("rpc.listen"))
+	assert.Equal(t, "env", cfg.Source("rpc.name"))
+}
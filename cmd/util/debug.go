@@ -24,6 +24,37 @@ func LogEvent(logger *logrus.Logger, event int, ctx interface{}) bool {
 			err.Caused,
 		))
 		return true
+	case roadrunner.EventWorkerDead:
+		w := ctx.(*roadrunner.Worker)
+		exit := w.Exit()
+		if exit == nil || exit.Cause == roadrunner.ExitClean {
+			return false
+		}
+
+		logger.Warning(Sprintf(
+			"<white+hb>worker.%v</reset> <yellow>died (%s)</reset>, code: %v, signal: %v, execs: %v, runtime: %s",
+			exit.Pid,
+			exit.Cause,
+			exit.ExitCode,
+			int(exit.Signal),
+			exit.NumExecs,
+			exit.Runtime,
+		))
+		return true
+	case roadrunner.EventWorkerStrayOutput:
+		err := ctx.(roadrunner.StrayOutputError)
+		logger.Error(Sprintf(
+			"<white+hb>worker.%v</reset> <red>%s</reset>",
+			err.Pid,
+			err,
+		))
+
+		for _, line := range strings.Split(err.Dump(), "\n") {
+			if line != "" {
+				logger.Debug(line)
+			}
+		}
+		return true
 	}
 
 	// outputs
@@ -55,6 +86,23 @@ func LogEvent(logger *logrus.Logger, event int, ctx interface{}) bool {
 	case roadrunner.EventPoolError:
 		logger.Error(Sprintf("<red>%s</reset>", ctx))
 		return true
+	case roadrunner.EventPoolResize:
+		logger.Info(Sprintf("<cyan>pool resized</reset> to <white+hb>%v</reset> workers", ctx))
+		return true
+	case roadrunner.EventPoolDrain:
+		e := ctx.(roadrunner.DrainEvent)
+		if e.Aborted != 0 {
+			logger.Warning(Sprintf(
+				"<yellow>pool drain timeout</reset>, drained: %v, aborted: %v, elapsed: %s",
+				e.Drained,
+				e.Aborted,
+				e.Elapsed,
+			))
+			return true
+		}
+
+		logger.Debug(Sprintf("<cyan>pool drained</reset>, drained: %v, elapsed: %s", e.Drained, e.Elapsed))
+		return true
 	}
 
 	return false
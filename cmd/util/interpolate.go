@@ -0,0 +1,242 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// InterpolationError returned when config value can not be interpolated.
+type InterpolationError struct {
+	// Key of the config value.
+	Key string
+
+	// Err contains interpolation error.
+	Err error
+}
+
+// Error returns error message.
+func (e *InterpolationError) Error() string {
+	return fmt.Sprintf("config `%s`: %s", e.Key, e.Err)
+}
+
+// Interpolate replaces expressions in the given string:
+//
+//	${NAME}               value of the environment variable, empty string if variable is not set
+//	${NAME:-default}      default value is used if variable is not set or empty
+//	${NAME:?message}      error is returned if variable is not set or empty
+//	${file:/path/to/file} content of the file without trailing newline
+//	$$                    literal $
+//
+// Default values and error messages can contain nested expressions.
+func Interpolate(value string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+
+		switch value[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '{':
+			end := closingBrace(value, i+2)
+			if end == -1 {
+				return "", fmt.Errorf("unclosed expression `%s`", value[i:])
+			}
+
+			v, err := expand(value[i+2 : end])
+			if err != nil {
+				return "", err
+			}
+
+			b.WriteString(v)
+			i = end
+		default:
+			b.WriteByte('$')
+		}
+	}
+
+	return b.String(), nil
+}
+
+// interpolateValue interpolates all strings of the config value.
+func interpolateValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return Interpolate(v)
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			iv, err := interpolateValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = iv
+		}
+
+		return list, nil
+	case []string:
+		list := make([]string, len(v))
+		for i, item := range v {
+			iv, err := Interpolate(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = iv
+		}
+
+		return list, nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			iv, err := interpolateValue(item)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = iv
+		}
+
+		return m, nil
+	case map[interface{}]interface{}:
+		m := make(map[interface{}]interface{}, len(v))
+		for k, item := range v {
+			iv, err := interpolateValue(item)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = iv
+		}
+
+		return m, nil
+	}
+
+	return value, nil
+}
+
+// interpolateSettings interpolates nested config values in place, returns keys of the values containing
+// expressions.
+func interpolateSettings(settings map[string]interface{}, prefix string) ([]string, error) {
+	var expressions []string
+	for k, v := range settings {
+		key := strings.TrimRight(k, appendMarker+replaceMarker)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if m, ok := v.(map[string]interface{}); ok {
+			keys, err := interpolateSettings(m, key)
+			if err != nil {
+				return nil, err
+			}
+
+			expressions = append(expressions, keys...)
+			continue
+		}
+
+		iv, err := interpolateValue(v)
+		if err != nil {
+			return nil, &InterpolationError{Key: key, Err: err}
+		}
+
+		if hasExpression(v) {
+			expressions = append(expressions, key)
+		}
+		settings[k] = iv
+	}
+
+	return expressions, nil
+}
+
+// hasExpression returns true if config value contains interpolated expressions.
+func hasExpression(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.Contains(strings.Replace(v, "$$", "", -1), "${")
+	case []interface{}:
+		for _, item := range v {
+			if hasExpression(item) {
+				return true
+			}
+		}
+	case []string:
+		for _, item := range v {
+			if hasExpression(item) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// expand evaluates single expression without the enclosing braces.
+func expand(expr string) (string, error) {
+	if strings.HasPrefix(expr, "file:") {
+		filename, err := Interpolate(expr[len("file:"):])
+		if err != nil {
+			return "", err
+		}
+
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	name, op, arg := expr, "", ""
+	if i := strings.Index(expr, ":"); i != -1 && i+1 < len(expr) && (expr[i+1] == '-' || expr[i+1] == '?') {
+		name, op, arg = expr[:i], expr[i:i+2], expr[i+2:]
+	}
+
+	if name == "" {
+		return "", fmt.Errorf("invalid expression `${%s}`", expr)
+	}
+
+	if v := os.Getenv(name); v != "" || op == "" {
+		return v, nil
+	}
+
+	arg, err := Interpolate(arg)
+	if err != nil {
+		return "", err
+	}
+
+	if op == ":?" {
+		if arg == "" {
+			arg = "variable is not set"
+		}
+
+		return "", errors.New(name + ": " + arg)
+	}
+
+	return arg, nil
+}
+
+// closingBrace returns position of the brace closing expression started at the given position, nested
+// expressions are skipped. Returns -1 if expression is not closed.
+func closingBrace(value string, start int) int {
+	depth := 0
+	for i := start; i < len(value); i++ {
+		switch {
+		case value[i] == '$' && i+1 < len(value) && value[i+1] == '$':
+			i++
+		case value[i] == '$' && i+1 < len(value) && value[i+1] == '{':
+			depth++
+			i++
+		case value[i] == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+
+	return -1
+}
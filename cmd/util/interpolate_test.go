@@ -0,0 +1,84 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Interpolate(t *testing.T) {
+	assert.NoError(t, os.Setenv("RR_TEST_HOST", "localhost"))
+	assert.NoError(t, os.Setenv("RR_TEST_PORT", "8080"))
+	assert.NoError(t, os.Setenv("RR_TEST_EMPTY", ""))
+	defer os.Unsetenv("RR_TEST_HOST")
+	defer os.Unsetenv("RR_TEST_PORT")
+	defer os.Unsetenv("RR_TEST_EMPTY")
+
+	cases := map[string]string{
+		"plain":                                  "plain",
+		"${RR_TEST_HOST}":                        "localhost",
+		"tcp://${RR_TEST_HOST}:${RR_TEST_PORT}":  "tcp://localhost:8080",
+		"${RR_TEST_MISSING}":                     "",
+		"${RR_TEST_MISSING:-9000}":               "9000",
+		"${RR_TEST_EMPTY:-9000}":                 "9000",
+		"${RR_TEST_PORT:-9000}":                  "8080",
+		"${RR_TEST_MISSING:-${RR_TEST_PORT}}":    "8080",
+		"${RR_TEST_MISSING:-${RR_TEST_NONE:-1}}": "1",
+		"$${RR_TEST_HOST}":                       "${RR_TEST_HOST}",
+		"${RR_TEST_MISSING:-$${x}}":              "${x}",
+		"cost: 5$":                               "cost: 5$",
+		"$HOME":                                  "$HOME",
+	}
+
+	for in, out := range cases {
+		v, err := Interpolate(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, out, v, in)
+	}
+}
+
+func Test_Interpolate_Errors(t *testing.T) {
+	_, err := Interpolate("${RR_TEST_MISSING:?port is required}")
+	assert.EqualError(t, err, "RR_TEST_MISSING: port is required")
+
+	_, err = Interpolate("${RR_TEST_MISSING:?}")
+	assert.EqualError(t, err, "RR_TEST_MISSING: variable is not set")
+
+	_, err = Interpolate("${RR_TEST_MISSING")
+	assert.Error(t, err)
+
+	_, err = Interpolate("${}")
+	assert.Error(t, err)
+
+	_, err = Interpolate("${file:/missing/secret}")
+	assert.Error(t, err)
+}
+
+func Test_Interpolate_File(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rr")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	secret := filepath.Join(dir, "secret")
+	assert.NoError(t, ioutil.WriteFile(secret, []byte("password\n"), 0600))
+
+	v, err := Interpolate("user:${file:" + secret + "}")
+	assert.NoError(t, err)
+	assert.Equal(t, "user:password", v)
+}
+
+func Test_InterpolateValue(t *testing.T) {
+	assert.NoError(t, os.Setenv("RR_TEST_EXT", ".php"))
+	defer os.Unsetenv("RR_TEST_EXT")
+
+	v, err := interpolateValue([]interface{}{"${RR_TEST_EXT}", 1, map[string]interface{}{"key": "${RR_TEST_EXT}"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{".php", 1, map[string]interface{}{"key": ".php"}}, v)
+
+	assert.True(t, hasExpression([]interface{}{"${RR_TEST_EXT}"}))
+	assert.False(t, hasExpression("$${RR_TEST_EXT}"))
+	assert.False(t, hasExpression(10))
+}
@@ -0,0 +1,155 @@
+package util
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// appendMarker suffix of the config key indicates that list values must be appended to the list defined
+	// by the previous layers.
+	appendMarker = "+"
+
+	// replaceMarker suffix of the config key indicates that value must replace the value defined by the previous
+	// layers without merging.
+	replaceMarker = "!"
+
+	// includeKey contains list of the config files (or glob patterns) included by the config layer.
+	includeKey = "include"
+)
+
+// layers merges config layers and tracks origin of the config values.
+type layers struct {
+	settings map[string]interface{}
+	sources  map[string]string
+	names    []string
+}
+
+// merge merges config layer and it's included files on top of the previous layers.
+func (l *layers) merge(v *viper.Viper, source string) error {
+	values := v.AllSettings()
+	l.names = append(l.names, source)
+	mergeSettings(l.settings, values, "", source, l.sources)
+
+	for _, pattern := range includes(values) {
+		pattern, err := Interpolate(pattern)
+		if err != nil {
+			return &InterpolationError{Key: includeKey, Err: err}
+		}
+
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return err
+		}
+
+		// explicitly included files must exist
+		if len(files) == 0 && !isGlob(pattern) {
+			files = []string{pattern}
+		}
+		sort.Strings(files)
+
+		for _, filename := range files {
+			partial := viper.New()
+			partial.SetConfigFile(filename)
+
+			if err := partial.ReadInConfig(); err != nil {
+				return err
+			}
+
+			name := "include:" + filename
+			l.names = append(l.names, name)
+			mergeSettings(l.settings, partial.AllSettings(), "", name, l.sources)
+		}
+	}
+
+	return nil
+}
+
+// mergeSettings deeply merges overlay values into settings. Lists and scalar values are replaced, keys suffixed
+// with "+" append list values and keys suffixed with "!" replace values without merging.
+func mergeSettings(settings, overlay map[string]interface{}, prefix, source string, sources map[string]string) {
+	for k, v := range overlay {
+		key, marker := k, ""
+		if strings.HasSuffix(k, appendMarker) || strings.HasSuffix(k, replaceMarker) {
+			key, marker = k[:len(k)-1], k[len(k)-1:]
+		}
+
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		if marker == appendMarker {
+			settings[key] = append(toList(settings[key]), toList(v)...)
+			trackSources(sources, []string{path}, source)
+			continue
+		}
+
+		om, ok := toMap(v)
+		if !ok {
+			settings[key] = v
+			trackSources(sources, []string{path}, source)
+			continue
+		}
+
+		sm, ok := toMap(settings[key])
+		if !ok || marker == replaceMarker {
+			// nested values are tracked on their own
+			sm = make(map[string]interface{})
+			clearSources(sources, path)
+		}
+
+		mergeSettings(sm, om, path, source, sources)
+		settings[key] = sm
+	}
+}
+
+// includes returns list of the files included by the config layer.
+func includes(values map[string]interface{}) []string {
+	var files []string
+	for k, v := range values {
+		if strings.TrimRight(k, appendMarker+replaceMarker) != includeKey {
+			continue
+		}
+
+		for _, f := range toList(v) {
+			if filename, ok := f.(string); ok {
+				files = append(files, filename)
+			}
+		}
+	}
+
+	return files
+}
+
+// profileFile returns name of the profile config located next to the main config file.
+func profileFile(filename, profile string) string {
+	ext := filepath.Ext(filename)
+	return fmt.Sprintf("%s.%s%s", strings.TrimSuffix(filename, ext), profile, ext)
+}
+
+func toList(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return append([]interface{}{}, v...)
+	case []string:
+		list := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			list = append(list, item)
+		}
+
+		return list
+	}
+
+	return []interface{}{value}
+}
+
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
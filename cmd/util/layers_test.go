@@ -0,0 +1,91 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MergeSettings(t *testing.T) {
+	settings := map[string]interface{}{
+		"http": map[string]interface{}{
+			"address": ":8080",
+			"uploads": map[string]interface{}{"forbid": []interface{}{".php"}},
+			"workers": map[string]interface{}{"command": "php worker.php", "pool": map[string]interface{}{"numworkers": 4}},
+		},
+		"static": map[string]interface{}{"forbid": []interface{}{".php"}},
+	}
+	sources := make(map[string]string)
+
+	mergeSettings(settings, map[string]interface{}{
+		"http": map[string]interface{}{
+			"address":  ":80",
+			"uploads":  map[string]interface{}{"forbid+": []interface{}{".exe"}},
+			"workers!": map[string]interface{}{"command": "php prod.php"},
+		},
+		"static": map[string]interface{}{"forbid": []interface{}{".htaccess"}},
+	}, "", "profile", sources)
+
+	assert.Equal(t, map[string]interface{}{
+		"http": map[string]interface{}{
+			"address": ":80",
+			"uploads": map[string]interface{}{"forbid": []interface{}{".php", ".exe"}},
+			"workers": map[string]interface{}{"command": "php prod.php"},
+		},
+		"static": map[string]interface{}{"forbid": []interface{}{".htaccess"}},
+	}, settings)
+
+	assert.Equal(t, map[string]string{
+		"http.address":         "profile",
+		"http.uploads.forbid":  "profile",
+		"http.workers.command": "profile",
+		"static.forbid":        "profile",
+	}, sources)
+}
+
+func Test_LoadConfig_Profile(t *testing.T) {
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	dir, err := ioutil.TempDir("", "rr")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		".rr.yaml":      "include: [\"conf.d/*.yaml\"]\nhttp:\n  address: :8080\n  uploads:\n    forbid: [\".php\"]\n",
+		".rr.prod.yaml": "http:\n  address: :80\n  uploads:\n    forbid+: [\".exe\"]\n",
+		"conf.d/a.yaml": "rpc:\n  listen: tcp://:6001\n",
+		"conf.d/b.yaml": "rpc:\n  listen: tcp://:6002\n  enable: true\n",
+	}
+	for name, content := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	cfg, err := LoadConfig(filepath.Join(dir, ".rr.yaml"), nil, ".rr", "prod", nil, "")
+	assert.NoError(t, err)
+
+	settings := cfg.Settings()
+	assert.Equal(t, ":80", settings["http"].(map[string]interface{})["address"])
+	assert.Equal(t, []interface{}{".php", ".exe"}, settings["http"].(map[string]interface{})["uploads"].(map[string]interface{})["forbid"])
+	assert.Equal(t, "tcp://:6002", settings["rpc"].(map[string]interface{})["listen"])
+
+	assert.Equal(t, "profile:.rr.prod.yaml", cfg.Source("http.address"))
+	assert.Equal(t, "include:conf.d/b.yaml", cfg.Source("rpc.listen"))
+
+	// sections and values which are not set have no source
+	assert.Equal(t, "", cfg.Source("http"))
+	assert.Equal(t, "", cfg.Source("http.workers.pool.numworkers"))
+	assert.Equal(t, []string{
+		"file:.rr.yaml",
+		"include:conf.d/a.yaml",
+		"include:conf.d/b.yaml",
+		"profile:.rr.prod.yaml",
+	}, cfg.Layers())
+
+	_, err = LoadConfig(filepath.Join(dir, ".rr.yaml"), nil, ".rr", "staging", nil, "")
+	assert.Error(t, err)
+}
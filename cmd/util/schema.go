@@ -0,0 +1,355 @@
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spiral/roadrunner/service"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// ConfigValue returns value service config section is unmarshalled into.
+func ConfigValue(cfg service.HydrateConfig) interface{} {
+	if sc, ok := cfg.(service.SectionConfig); ok {
+		return sc.Section()
+	}
+
+	return cfg
+}
+
+// CheckConfig returns list of config keys which are not recognized by the given config value, suggestions are
+// provided for the misspelled keys. Data must contain config section values as returned by viper.
+func CheckConfig(path string, cfg interface{}, data interface{}) []string {
+	return checkValue(path, reflect.TypeOf(cfg), data)
+}
+
+// ConfigSchema returns JSON schema of the given config value, non zero values of the config are used as defaults.
+func ConfigSchema(cfg interface{}) map[string]interface{} {
+	return valueSchema(reflect.ValueOf(cfg))
+}
+
+// FlattenConfig returns config values by their keys in dot notation, nil pointers, empty maps and empty slices
+// are omitted.
+func FlattenConfig(path string, cfg interface{}) map[string]interface{} {
+	values := make(map[string]interface{})
+	flattenValue(path, reflect.ValueOf(cfg), values)
+
+	return values
+}
+
+// Suggest returns the most similar option to the given key or empty string if none of the options is similar.
+func Suggest(key string, options []string) string {
+	suggestion, best := "", 0
+	for _, o := range options {
+		d := distance(strings.ToLower(key), strings.ToLower(o))
+		if d > 2 && d > len(key)/3 {
+			continue
+		}
+
+		if suggestion == "" || d < best {
+			suggestion, best = o, d
+		}
+	}
+
+	return suggestion
+}
+
+func checkValue(path string, t reflect.Type, data interface{}) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var issues []string
+	switch t.Kind() {
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			return nil
+		}
+
+		values, ok := toMap(data)
+		if !ok {
+			return nil
+		}
+
+		fields := configFields(t)
+		for _, key := range sortedKeys(values) {
+			f, ok := findField(fields, key)
+			if !ok {
+				issues = append(issues, unknownKey(path+"."+key, key, fields))
+				continue
+			}
+
+			issues = append(issues, checkValue(path+"."+key, f.Type, values[key])...)
+		}
+
+	case reflect.Map:
+		values, ok := toMap(data)
+		if !ok {
+			return nil
+		}
+
+		for _, key := range sortedKeys(values) {
+			issues = append(issues, checkValue(path+"."+key, t.Elem(), values[key])...)
+		}
+
+	case reflect.Slice, reflect.Array:
+		list, ok := data.([]interface{})
+		if !ok {
+			return nil
+		}
+
+		for i, v := range list {
+			issues = append(issues, checkValue(fmt.Sprintf("%s[%v]", path, i), t.Elem(), v)...)
+		}
+	}
+
+	return issues
+}
+
+func valueSchema(v reflect.Value) map[string]interface{} {
+	t := v.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		if v.IsValid() && !v.IsNil() {
+			v = v.Elem()
+		} else {
+			v = reflect.Value{}
+		}
+	}
+
+	schema := make(map[string]interface{})
+	switch t.Kind() {
+	case reflect.Bool:
+		schema["type"] = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if t == durationType {
+			// durations are defined in seconds or as duration strings
+			schema["type"] = []string{"integer", "string"}
+			return schema
+		}
+
+		schema["type"] = "integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema["type"] = "integer"
+		schema["minimum"] = 0
+	case reflect.Float32, reflect.Float64:
+		schema["type"] = "number"
+	case reflect.String:
+		schema["type"] = "string"
+	case reflect.Slice, reflect.Array:
+		schema["type"] = "array"
+		schema["items"] = valueSchema(reflect.Zero(t.Elem()))
+		return schema
+	case reflect.Map:
+		schema["type"] = "object"
+		schema["additionalProperties"] = valueSchema(reflect.Zero(t.Elem()))
+		return schema
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		for _, f := range configFields(t) {
+			fv := reflect.Zero(f.Type)
+			if v.IsValid() {
+				fv = v.FieldByIndex(f.Index)
+			}
+
+			properties[configKey(f)] = valueSchema(fv)
+		}
+
+		schema["type"] = "object"
+		schema["properties"] = properties
+		return schema
+	default:
+		return schema
+	}
+
+	if v.IsValid() && !isZero(v) {
+		schema["default"] = v.Interface()
+	}
+
+	return schema
+}
+
+func flattenValue(path string, v reflect.Value, values map[string]interface{}) {
+	if !v.IsValid() {
+		return
+	}
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for _, f := range configFields(v.Type()) {
+			flattenValue(path+"."+configKey(f), v.FieldByIndex(f.Index), values)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+
+		for _, k := range keys {
+			flattenValue(fmt.Sprintf("%s.%v", path, k.Interface()), v.MapIndex(k), values)
+		}
+	case reflect.Slice:
+		if v.Len() != 0 {
+			values[path] = v.Interface()
+		}
+	case reflect.Func, reflect.Chan:
+	default:
+		if v.Type() == durationType {
+			values[path] = v.Interface().(time.Duration).String()
+			return
+		}
+
+		values[path] = v.Interface()
+	}
+}
+
+// configFields returns exported struct fields which can be populated using config.
+func configFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Tag.Get("mapstructure") == "-" {
+			continue
+		}
+
+		switch f.Type.Kind() {
+		case reflect.Func, reflect.Chan:
+			continue
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields
+}
+
+// configKey returns config key of the struct field in camelCase.
+func configKey(f reflect.StructField) string {
+	if tag := strings.Split(f.Tag.Get("mapstructure"), ",")[0]; tag != "" {
+		return tag
+	}
+
+	name := []rune(f.Name)
+	for i := range name {
+		// lowercase leading acronym, keeping first letter of the next word
+		if i != 0 && i+1 < len(name) && unicode.IsLower(name[i+1]) {
+			break
+		}
+
+		if !unicode.IsUpper(name[i]) && !unicode.IsDigit(name[i]) {
+			break
+		}
+
+		name[i] = unicode.ToLower(name[i])
+	}
+
+	return string(name)
+}
+
+// findField finds struct field by config key, keys are case insensitive.
+func findField(fields []reflect.StructField, key string) (reflect.StructField, bool) {
+	for _, f := range fields {
+		if strings.EqualFold(configKey(f), key) || strings.EqualFold(f.Name, key) {
+			return f, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+func unknownKey(path, key string, fields []reflect.StructField) string {
+	options := make([]string, 0, len(fields))
+	for _, f := range fields {
+		options = append(options, configKey(f))
+	}
+
+	if s := Suggest(key, options); s != "" {
+		return fmt.Sprintf("unknown key `%s`, did you mean `%s`?", path, s)
+	}
+
+	return fmt.Sprintf("unknown key `%s`", path)
+}
+
+func toMap(data interface{}) (map[string]interface{}, bool) {
+	switch m := data.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		values := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			values[fmt.Sprint(k)] = v
+		}
+
+		return values, true
+	}
+
+	return nil, false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// IsEmpty returns true if value is nil or zero value of it's type.
+func IsEmpty(value interface{}) bool {
+	return value == nil || isZero(reflect.ValueOf(value))
+}
+
+func isZero(v reflect.Value) bool {
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
+// distance returns Levenshtein distance between two strings.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
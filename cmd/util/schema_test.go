@@ -0,0 +1,105 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPool struct {
+	NumWorkers int64
+	Timeout    time.Duration
+}
+
+type testConfig struct {
+	Address  string
+	TTL      int
+	H2C      bool
+	Pool     *testPool
+	Services map[string]*testPool
+	Forbid   []string
+	Options  map[string]interface{}
+
+	Producer func()
+	internal string
+}
+
+func Test_CheckConfig(t *testing.T) {
+	issues := CheckConfig("test", &testConfig{}, map[string]interface{}{
+		"address": "localhost",
+		"ttl":     1,
+		"pool":    map[string]interface{}{"numworker": 1, "timeout": 10},
+		"services": map[string]interface{}{
+			"http": map[interface{}]interface{}{"timeot": 1},
+		},
+		"forbid":   []interface{}{".php"},
+		"options":  map[string]interface{}{"any": "value"},
+		"internal": "value",
+		"xyz":      1,
+	})
+
+	assert.Equal(t, []string{
+		"unknown key `test.internal`",
+		"unknown key `test.pool.numworker`, did you mean `numWorkers`?",
+		"unknown key `test.services.http.timeot`, did you mean `timeout`?",
+		"unknown key `test.xyz`",
+	}, issues)
+
+	assert.Empty(t, CheckConfig("test", &testConfig{}, map[string]interface{}{"TTL": 1, "h2c": true}))
+}
+
+func Test_Suggest(t *testing.T) {
+	assert.Equal(t, "metrics", Suggest("metrcs", []string{"http", "metrics", "rpc"}))
+	assert.Equal(t, "maxMemory", Suggest("maxmemmory", []string{"maxMemory", "TTL"}))
+	assert.Equal(t, "", Suggest("unknown", []string{"http", "metrics", "rpc"}))
+}
+
+func Test_FlattenConfig(t *testing.T) {
+	values := FlattenConfig("test", &testConfig{
+		Address:  "localhost",
+		Pool:     &testPool{NumWorkers: 4, Timeout: time.Minute},
+		Services: map[string]*testPool{"http": {NumWorkers: 1}},
+		Options:  map[string]interface{}{"realm": "api"},
+		Producer: func() {},
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"test.address":                  "localhost",
+		"test.ttl":                      0,
+		"test.h2c":                      false,
+		"test.pool.numWorkers":          int64(4),
+		"test.pool.timeout":             "1m0s",
+		"test.services.http.numWorkers": int64(1),
+		"test.services.http.timeout":    "0s",
+		"test.options.realm":            "api",
+	}, values)
+
+	assert.Empty(t, FlattenConfig("test", nil))
+}
+
+func Test_ConfigSchema(t *testing.T) {
+	schema := ConfigSchema(&testConfig{Address: "localhost", Pool: &testPool{NumWorkers: 4}})
+	assert.Equal(t, "object", schema["type"])
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Len(t, properties, 7)
+	assert.Equal(t, map[string]interface{}{"type": "string", "default": "localhost"}, properties["address"])
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, properties["ttl"])
+	assert.Equal(t, map[string]interface{}{"type": "boolean"}, properties["h2c"])
+	assert.Equal(t, map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"type": "string"},
+	}, properties["forbid"])
+	assert.Equal(t, map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": map[string]interface{}{},
+	}, properties["options"])
+
+	pool := properties["pool"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "integer", "default": int64(4)}, pool["numWorkers"])
+	assert.Equal(t, map[string]interface{}{"type": []string{"integer", "string"}}, pool["timeout"])
+
+	services := properties["services"].(map[string]interface{})["additionalProperties"].(map[string]interface{})
+	assert.Equal(t, "object", services["type"])
+}
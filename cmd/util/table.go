@@ -1,8 +1,10 @@
 package util
 
 import (
+	"fmt"
 	"github.com/dustin/go-humanize"
 	"github.com/olekukonko/tablewriter"
+	rrpc "github.com/spiral/roadrunner/service/rpc"
 	rrutil "github.com/spiral/roadrunner/util"
 	"os"
 	"strconv"
@@ -58,3 +60,85 @@ func renderJobs(number int64) string {
 func renderAlive(t time.Time) string {
 	return humanize.RelTime(t, time.Now(), "ago", "")
 }
+
+// ServiceTable renders table with information about rr services.
+func ServiceTable(services []rrpc.ServiceStatus) *tablewriter.Table {
+	tw := tablewriter.NewWriter(os.Stdout)
+	tw.SetHeader([]string{"Service", "Status", "Uptime", "Restarts", "Error"})
+	tw.SetColMinWidth(0, 10)
+	tw.SetColMinWidth(1, 10)
+	tw.SetColMinWidth(2, 10)
+	tw.SetColMinWidth(3, 8)
+	tw.SetColWidth(60)
+
+	for _, s := range services {
+		tw.Append([]string{
+			s.Name,
+			renderServiceStatus(s.Status),
+			renderUptime(s.Uptime),
+			strconv.Itoa(s.Restarts),
+			s.Error,
+		})
+	}
+
+	return tw
+}
+
+func renderServiceStatus(status string) string {
+	switch status {
+	case "disabled":
+		return Sprintf("<yellow>disabled</reset>")
+	case "enabled":
+		return Sprintf("<cyan>enabled</reset>")
+	case "serving":
+		return Sprintf("<green>serving</reset>")
+	case "restarting":
+		return Sprintf("<yellow>restarting</reset>")
+	case "stopping", "stopped", "failed":
+		return Sprintf("<red>%s</reset>", status)
+	}
+
+	return status
+}
+
+func renderUptime(uptime int64) string {
+	if uptime == 0 {
+		return "-"
+	}
+
+	return (time.Duration(uptime) * time.Second).String()
+}
+
+// ConfigEntry describes single config value.
+type ConfigEntry struct {
+	// Key of the value in dot notation.
+	Key string `json:"key"`
+
+	// Value contains effective config value.
+	Value interface{} `json:"value"`
+
+	// Source of the value (file, include, env, flag or default).
+	Source string `json:"source"`
+}
+
+// ConfigTable renders table with effective config values.
+func ConfigTable(entries []ConfigEntry) *tablewriter.Table {
+	tw := tablewriter.NewWriter(os.Stdout)
+	tw.SetHeader([]string{"Key", "Value", "Source"})
+	tw.SetAutoWrapText(false)
+	tw.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	for _, e := range entries {
+		tw.Append([]string{e.Key, fmt.Sprint(e.Value), renderSource(e.Source)})
+	}
+
+	return tw
+}
+
+func renderSource(source string) string {
+	if source == "default" {
+		return Sprintf("<white+h>default</reset>")
+	}
+
+	return source
+}
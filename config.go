@@ -24,12 +24,18 @@ type Config struct {
 	// DestroyTimeout defines for how long pool should be waiting for worker to
 	// properly stop, if timeout reached worker will be killed.
 	DestroyTimeout time.Duration
+
+	// DrainTimeout defines for how long pool waits for active executions to complete
+	// while being destroyed, executions which did not complete in time are aborted and
+	// their workers killed. Set 0 to wait indefinitely.
+	DrainTimeout time.Duration
 }
 
 // InitDefaults allows to init blank config with pre-defined set of default values.
 func (cfg *Config) InitDefaults() error {
 	cfg.AllocateTimeout = time.Minute
 	cfg.DestroyTimeout = time.Minute
+	cfg.DrainTimeout = time.Minute
 	cfg.NumWorkers = int64(runtime.NumCPU())
 
 	return nil
@@ -13,4 +13,10 @@ type Controller interface {
 type Attacher interface {
 	// Attach attaches controller to the service.
 	Attach(c Controller)
-}
\ No newline at end of file
+}
+
+// PoolAttacher defines the ability to attach rr controller to the named worker pool of the service.
+type PoolAttacher interface {
+	// AttachPool attaches controller to the named worker pool of the service.
+	AttachPool(pool string, c Controller)
+}
@@ -1,18 +1,25 @@
 package roadrunner
 
 import (
+	"bytes"
 	"sync"
 	"time"
 )
 
 const (
 	// EventStderrOutput - is triggered when worker sends data into stderr. The context
-	// is error message ([]byte).
+	// is error message ([]byte), lines written while worker executes tagged payload are prefixed with "[tag] ".
 	EventStderrOutput = 1900
 
 	// WaitDuration - for how long error buffer should attempt to aggregate error messages
 	// before merging output together since lastError update (required to keep error update together).
 	WaitDuration = 100 * time.Millisecond
+
+	// ExitStderrLines - number of last stderr lines retained to describe worker termination.
+	ExitStderrLines = 10
+
+	// lines longer than given size are split
+	maxLineSize = 1024
 )
 
 // thread safe errBuffer
@@ -20,18 +27,27 @@ type errBuffer struct {
 	mu     sync.Mutex
 	buf    []byte
 	last   int
+	tail   []string
+	line   []byte
 	wait   *time.Timer
 	update chan interface{}
 	stop   chan interface{}
 	lsn    func(event int, ctx interface{})
+
+	// tag returns prefix of the written lines, if any
+	tag func() string
+
+	// indicates that next write starts new line
+	newLine bool
 }
 
 func newErrBuffer() *errBuffer {
 	eb := &errBuffer{
-		buf:    make([]byte, 0),
-		update: make(chan interface{}),
-		wait:   time.NewTimer(WaitDuration),
-		stop:   make(chan interface{}),
+		buf:     make([]byte, 0),
+		update:  make(chan interface{}),
+		wait:    time.NewTimer(WaitDuration),
+		stop:    make(chan interface{}),
+		newLine: true,
 	}
 
 	go func() {
@@ -91,7 +107,8 @@ func (eb *errBuffer) Len() int {
 // needed. The return value n is the length of pool; err is always nil.
 func (eb *errBuffer) Write(p []byte) (int, error) {
 	eb.mu.Lock()
-	eb.buf = append(eb.buf, p...)
+	eb.appendTagged(p)
+	eb.appendTail(p)
 	eb.mu.Unlock()
 	eb.update <- nil
 
@@ -106,8 +123,81 @@ func (eb *errBuffer) String() string {
 	return string(eb.buf)
 }
 
+// Tail returns last non empty lines written into the errBuffer (see ExitStderrLines), including
+// incomplete last line. Unlike String, tail is retained after passing output to the listener.
+func (eb *errBuffer) Tail() []string {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	tail := append([]string{}, eb.tail...)
+	if line := string(bytes.TrimSpace(eb.line)); line != "" {
+		tail = append(tail, line)
+	}
+
+	if len(tail) > ExitStderrLines {
+		tail = tail[len(tail)-ExitStderrLines:]
+	}
+
+	return tail
+}
+
 // Close aggregation timer.
 func (eb *errBuffer) Close() error {
 	close(eb.stop)
 	return nil
 }
+
+// appendTagged appends given output to the buffer, every line is prefixed with the tag of currently executed payload.
+func (eb *errBuffer) appendTagged(p []byte) {
+	tag := ""
+	if eb.tag != nil {
+		tag = eb.tag()
+	}
+
+	for len(p) != 0 {
+		if eb.newLine && tag != "" {
+			eb.buf = append(eb.buf, '[')
+			eb.buf = append(eb.buf, tag...)
+			eb.buf = append(eb.buf, "] "...)
+		}
+
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			eb.buf = append(eb.buf, p...)
+			eb.newLine = false
+			return
+		}
+
+		eb.buf = append(eb.buf, p[:i+1]...)
+		eb.newLine = true
+		p = p[i+1:]
+	}
+}
+
+// appendTail splits given output into lines and retains last of them.
+func (eb *errBuffer) appendTail(p []byte) {
+	eb.line = append(eb.line, p...)
+	for {
+		i := bytes.IndexByte(eb.line, '\n')
+		if i < 0 && len(eb.line) < maxLineSize {
+			return
+		}
+
+		if i < 0 || i > maxLineSize {
+			i = maxLineSize
+		}
+
+		if line := string(bytes.TrimSpace(eb.line[:i])); line != "" {
+			eb.tail = append(eb.tail, line)
+			if len(eb.tail) > ExitStderrLines {
+				eb.tail = eb.tail[1:]
+			}
+		}
+
+		if i < len(eb.line) && eb.line[i] == '\n' {
+			i++
+		}
+
+		eb.line = append(eb.line[:0], eb.line[i:]...)
+	}
+}
@@ -1,7 +1,9 @@
 package roadrunner
 
 import (
+	"fmt"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -129,3 +131,69 @@ func TestErrBuffer_Write_Remaining(t *testing.T) {
 	assert.Equal(t, 3, buf.Len())
 	assert.Equal(t, "hel", buf.String())
 }
+
+func TestErrBuffer_Tail(t *testing.T) {
+	buf := newErrBuffer()
+	defer func() {
+		err := buf.Close()
+		if err != nil {
+			t.Errorf("error during closing the buffer: error %v", err)
+		}
+	}()
+
+	for i := 0; i < ExitStderrLines+5; i++ {
+		_, err := buf.Write([]byte(fmt.Sprintf("line %v\n\n", i)))
+		if err != nil {
+			t.Errorf("fail to write: error %v", err)
+		}
+	}
+
+	_, err := buf.Write([]byte("last"))
+	if err != nil {
+		t.Errorf("fail to write: error %v", err)
+	}
+
+	tail := buf.Tail()
+	assert.Len(t, tail, ExitStderrLines)
+	assert.Equal(t, "line 6", tail[0])
+	assert.Equal(t, "last", tail[ExitStderrLines-1])
+}
+
+func TestErrBuffer_Tail_LongLine(t *testing.T) {
+	buf := newErrBuffer()
+	defer func() {
+		err := buf.Close()
+		if err != nil {
+			t.Errorf("error during closing the buffer: error %v", err)
+		}
+	}()
+
+	_, err := buf.Write([]byte(strings.Repeat("a", maxLineSize*2+10)))
+	if err != nil {
+		t.Errorf("fail to write: error %v", err)
+	}
+
+	tail := buf.Tail()
+	assert.Len(t, tail, 3)
+	assert.Len(t, tail[0], maxLineSize)
+	assert.Len(t, tail[2], 10)
+}
+
+func TestErrBuffer_Write_Tagged(t *testing.T) {
+	buf := newErrBuffer()
+	defer func() {
+		assert.NoError(t, buf.Close())
+	}()
+
+	tag := "req-1"
+	buf.tag = func() string { return tag }
+
+	_, _ = buf.Write([]byte("first line\nsecond "))
+	_, _ = buf.Write([]byte("part\n"))
+
+	tag = ""
+	_, _ = buf.Write([]byte("untagged\n"))
+
+	assert.Equal(t, "[req-1] first line\n[req-1] second part\nuntagged\n", buf.String())
+	assert.Equal(t, []string{"first line", "second part", "untagged"}, buf.Tail())
+}
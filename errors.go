@@ -1,5 +1,34 @@
 package roadrunner
 
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrPoolStopped is returned when pool is destroyed while execution waits for a free worker.
+	ErrPoolStopped = errors.New("pool has been stopped")
+
+	// ErrExecAborted is returned when execution did not complete within pool drain timeout.
+	ErrExecAborted = errors.New("execution has been aborted, pool is destroyed")
+
+	// ErrMalformedResponse is returned when worker response does not follow the protocol.
+	ErrMalformedResponse = errors.New("malformed worker response")
+)
+
+// AllocateTimeoutError is returned when no worker has been freed within pool allocate timeout.
+type AllocateTimeoutError struct {
+	// Timeout contains pool allocate timeout.
+	Timeout time.Duration
+}
+
+// Error converts error context to string
+func (e AllocateTimeoutError) Error() string {
+	return fmt.Sprintf("worker timeout (%s)", e.Timeout)
+}
+
 // JobError is job level error (no worker halt), wraps at top
 // of error context
 type JobError []byte
@@ -16,9 +45,35 @@ type WorkerError struct {
 
 	// Caused error
 	Caused error
+
+	// Tag of the payload executed by the worker when error occurred, if any.
+	Tag string
 }
 
 // Error converts error context to string
 func (e WorkerError) Error() string {
 	return e.Caused.Error()
 }
+
+// StrayOutputError is returned when worker writes non protocol data into the relay (echo, var_dump
+// or notices printed to STDOUT), such worker is always replaced.
+type StrayOutputError struct {
+	// Worker which produced the output.
+	Worker *Worker
+
+	// Pid of the worker process.
+	Pid int
+
+	// Output contains captured data, limited by StrayOutputLimit.
+	Output []byte
+}
+
+// Error returns error message with output preview.
+func (e StrayOutputError) Error() string {
+	return fmt.Sprintf("worker %v: invalid data found in the output (possible echo): %s", e.Pid, preview(e.Output))
+}
+
+// Dump returns hexdump of captured output.
+func (e StrayOutputError) Dump() string {
+	return hex.Dump(e.Output)
+}
@@ -15,3 +15,9 @@ func Test_WorkerError_Error(t *testing.T) {
 	e := WorkerError{Worker: nil, Caused: errors.New("error")}
 	assert.Equal(t, "error", e.Error())
 }
+
+func Test_StrayOutputError_Error(t *testing.T) {
+	e := StrayOutputError{Pid: 10, Output: []byte("hello")}
+	assert.Equal(t, `worker 10: invalid data found in the output (possible echo): "hello"`, e.Error())
+	assert.Contains(t, e.Dump(), "68 65 6c 6c 6f")
+}
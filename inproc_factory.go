@@ -0,0 +1,216 @@
+package roadrunner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"sync"
+	"syscall"
+
+	json "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/spiral/goridge/v2"
+)
+
+// WorkerFunc handles payloads inside in-process workers. Returned error is passed to the server as JobError,
+// panic is treated as worker failure (message and stack are written to the worker stderr).
+type WorkerFunc func(rqs *Payload) (rsp *Payload, err error)
+
+// InProcessFactory runs workers as goroutines of the server process. Workers communicate
+// with the server over in-memory pipes using the same goridge protocol as PHP workers,
+// which makes it possible to test pools and services without PHP installed.
+type InProcessFactory struct {
+	handler WorkerFunc
+}
+
+// NewInProcessFactory returns new factory which handles all payloads using given function.
+func NewInProcessFactory(handler WorkerFunc) *InProcessFactory {
+	return &InProcessFactory{handler: handler}
+}
+
+// SpawnWorker creates new in-process worker and connects it to the goridge relay. Given command
+// is not started and only used to describe the worker.
+func (f *InProcessFactory) SpawnWorker(cmd *exec.Cmd) (w *Worker, err error) {
+	if w, err = newWorker(cmd); err != nil {
+		return nil, err
+	}
+
+	// server -> worker and worker -> server streams
+	wIn, sOut := io.Pipe()
+	sIn, wOut := io.Pipe()
+
+	w.proc = &inProcess{
+		handler: f.handler,
+		in:      wIn,
+		out:     wOut,
+		rl:      goridge.NewPipeRelay(wIn, wOut),
+		stderr:  w.err,
+		done:    make(chan interface{}),
+	}
+	w.rl = newPipeRelay(sIn, sOut)
+
+	if err := w.start(); err != nil {
+		return nil, errors.Wrap(err, "process error")
+	}
+
+	if pid, err := fetchPID(w.rl); pid != *w.Pid {
+		go func(w *Worker) {
+			// in-process workers can always be killed
+			_ = w.Kill()
+		}(w)
+
+		if wErr := w.Wait(); wErr != nil && err == nil {
+			err = wErr
+		}
+
+		if err == nil {
+			err = fmt.Errorf("pid mismatch")
+		}
+
+		return nil, errors.Wrap(err, "unable to connect to worker")
+	}
+
+	w.state.set(StateReady)
+	return w, nil
+}
+
+// Close the factory.
+func (f *InProcessFactory) Close() error {
+	return nil
+}
+
+// inProcess serves worker protocol using goroutine instead of system process. All workers
+// share PID of the server process.
+type inProcess struct {
+	handler WorkerFunc
+	in      *io.PipeReader
+	out     *io.PipeWriter
+	rl      goridge.Relay
+	stderr  io.Writer
+
+	once sync.Once
+	done chan interface{}
+	err  error
+}
+
+func (p *inProcess) start() error {
+	go p.serve()
+	return nil
+}
+
+func (p *inProcess) pid() int {
+	return os.Getpid()
+}
+
+func (p *inProcess) kill() error {
+	p.exit(&exitError{signal: syscall.SIGKILL})
+	return nil
+}
+
+func (p *inProcess) wait() error {
+	<-p.done
+	return p.err
+}
+
+// exit closes worker streams and releases wait, only first exit error is retained.
+func (p *inProcess) exit(err error) {
+	p.once.Do(func() {
+		p.err = err
+		_ = p.in.Close()
+		_ = p.out.Close()
+		close(p.done)
+	})
+}
+
+// serve reads incoming frames until stop command, failure or kill.
+func (p *inProcess) serve() {
+	defer func() {
+		if r := recover(); r != nil {
+			_, _ = fmt.Fprintf(p.stderr, "panic: %v\n\n%s", r, debug.Stack())
+			p.exit(&exitError{code: 2})
+		}
+	}()
+
+	for {
+		data, pr, err := p.rl.Receive()
+		if err != nil {
+			p.exit(err)
+			return
+		}
+
+		if !pr.HasFlag(goridge.PayloadControl) {
+			// context is always expected prior to the body
+			continue
+		}
+
+		if !pr.HasFlag(goridge.PayloadRaw) {
+			stop, err := p.control(data)
+			if err != nil {
+				p.exit(err)
+				return
+			}
+
+			if stop {
+				p.exit(nil)
+				return
+			}
+
+			continue
+		}
+
+		rqs := &Payload{Context: data}
+		if rqs.Body, _, err = p.rl.Receive(); err != nil {
+			p.exit(err)
+			return
+		}
+
+		if err := p.respond(rqs); err != nil {
+			p.exit(err)
+			return
+		}
+	}
+}
+
+// control handles PID negotiation and stop commands, returns true when worker must stop.
+func (p *inProcess) control(data []byte) (bool, error) {
+	cmd := struct {
+		Pid  int  `json:"pid"`
+		Stop bool `json:"stop"`
+	}{}
+
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return false, errors.Wrap(err, "invalid control command")
+	}
+
+	if cmd.Pid != 0 {
+		if err := sendControl(p.rl, pidCommand{Pid: p.pid()}); err != nil {
+			return false, err
+		}
+	}
+
+	return cmd.Stop, nil
+}
+
+// respond executes worker function and sends result or job error back to the server.
+func (p *inProcess) respond(rqs *Payload) error {
+	rsp, err := p.handler(rqs)
+	if err != nil {
+		return p.rl.Send([]byte(err.Error()), goridge.PayloadControl|goridge.PayloadRaw|goridge.PayloadError)
+	}
+
+	if rsp == nil {
+		rsp = &Payload{}
+	}
+
+	if rsp.Context == nil {
+		if err := p.rl.Send(nil, goridge.PayloadControl|goridge.PayloadEmpty); err != nil {
+			return err
+		}
+	} else if err := sendControl(p.rl, rsp.Context); err != nil {
+		return err
+	}
+
+	return p.rl.Send(rsp.Body, goridge.PayloadRaw)
+}
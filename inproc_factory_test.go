@@ -0,0 +1,180 @@
+package roadrunner
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func echoFunc(rqs *Payload) (*Payload, error) {
+	return &Payload{Context: rqs.Context, Body: rqs.Body}, nil
+}
+
+func Test_InProcess_Start(t *testing.T) {
+	w, err := NewInProcessFactory(echoFunc).SpawnWorker(exec.Command("go", "worker"))
+	assert.NoError(t, err)
+	assert.NotNil(t, w)
+
+	assert.Equal(t, os.Getpid(), *w.Pid)
+	assert.Equal(t, StateReady, w.State().Value())
+
+	go func() {
+		assert.NoError(t, w.Wait())
+	}()
+
+	assert.NoError(t, w.Stop())
+}
+
+func Test_InProcess_Echo(t *testing.T) {
+	w, _ := NewInProcessFactory(echoFunc).SpawnWorker(exec.Command("go", "worker"))
+	go func() {
+		assert.NoError(t, w.Wait())
+	}()
+	defer func() {
+		err := w.Stop()
+		if err != nil {
+			t.Errorf("error stopping the worker: error %v", err)
+		}
+	}()
+
+	res, err := w.Exec(&Payload{Context: []byte("context"), Body: []byte("hello")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "context", string(res.Context))
+	assert.Equal(t, "hello", res.String())
+	assert.Equal(t, int64(1), w.State().NumExecs())
+}
+
+func Test_InProcess_EmptyResponse(t *testing.T) {
+	w, _ := NewInProcessFactory(func(rqs *Payload) (*Payload, error) {
+		return nil, nil
+	}).SpawnWorker(exec.Command("go", "worker"))
+	go func() {
+		assert.NoError(t, w.Wait())
+	}()
+	defer func() {
+		err := w.Stop()
+		if err != nil {
+			t.Errorf("error stopping the worker: error %v", err)
+		}
+	}()
+
+	res, err := w.Exec(&Payload{Body: []byte("hello")})
+
+	assert.NoError(t, err)
+	assert.Nil(t, res.Context)
+	assert.Nil(t, res.Body)
+}
+
+func Test_InProcess_JobError(t *testing.T) {
+	w, _ := NewInProcessFactory(func(rqs *Payload) (*Payload, error) {
+		return nil, errors.New("hello")
+	}).SpawnWorker(exec.Command("go", "worker"))
+	go func() {
+		assert.NoError(t, w.Wait())
+	}()
+	defer func() {
+		err := w.Stop()
+		if err != nil {
+			t.Errorf("error stopping the worker: error %v", err)
+		}
+	}()
+
+	res, err := w.Exec(&Payload{Body: []byte("hello")})
+
+	assert.Error(t, err)
+	assert.Nil(t, res)
+	assert.IsType(t, JobError{}, err)
+	assert.Equal(t, "hello", err.Error())
+	assert.Equal(t, StateReady, w.State().Value())
+}
+
+func Test_InProcess_Panic(t *testing.T) {
+	w, _ := NewInProcessFactory(func(rqs *Payload) (*Payload, error) {
+		panic("broken worker")
+	}).SpawnWorker(exec.Command("go", "worker"))
+
+	res, err := w.Exec(&Payload{Body: []byte("hello")})
+	assert.Error(t, err)
+	assert.Nil(t, res)
+
+	err = w.Wait()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "panic: broken worker")
+	assert.Equal(t, StateErrored, w.State().Value())
+}
+
+func Test_InProcess_Tag(t *testing.T) {
+	w, _ := NewInProcessFactory(func(rqs *Payload) (*Payload, error) {
+		panic("broken worker")
+	}).SpawnWorker(exec.Command("go", "worker"))
+
+	out := make(chan string, 1)
+	w.err.Listen(func(event int, ctx interface{}) {
+		if event == EventStderrOutput {
+			out <- string(ctx.([]byte))
+		}
+	})
+
+	_, err := w.Exec(&Payload{Body: []byte("hello"), Tag: "request-1"})
+	assert.Error(t, err)
+	assert.Equal(t, "", w.Tag())
+
+	assert.Error(t, w.Wait())
+	assert.True(t, strings.HasPrefix(<-out, "[request-1] panic: broken worker\n"))
+}
+
+func Test_InProcess_Kill(t *testing.T) {
+	w, _ := NewInProcessFactory(echoFunc).SpawnWorker(exec.Command("go", "worker"))
+
+	go func() {
+		assert.NoError(t, w.Kill())
+	}()
+
+	err := w.Wait()
+	assert.Error(t, err)
+	assert.Equal(t, StateStopped, w.State().Value())
+}
+
+func Test_InProcess_Pool(t *testing.T) {
+	p, err := NewPool(
+		func() *exec.Cmd { return exec.Command("go", "worker") },
+		NewInProcessFactory(echoFunc),
+		cfg,
+	)
+	assert.NoError(t, err)
+	defer p.Destroy()
+
+	assert.Len(t, p.Workers(), int(cfg.NumWorkers))
+
+	res, err := p.Exec(&Payload{Body: []byte("hello")})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", res.String())
+}
+
+func Test_InProcess_Pool_StopRequest(t *testing.T) {
+	calls := make(chan interface{}, 1)
+	p, err := NewPool(
+		func() *exec.Cmd { return exec.Command("go", "worker") },
+		NewInProcessFactory(func(rqs *Payload) (*Payload, error) {
+			select {
+			case calls <- nil:
+				return &Payload{Context: []byte(StopRequest)}, nil
+			default:
+				return echoFunc(rqs)
+			}
+		}),
+		Config{NumWorkers: 1, AllocateTimeout: cfg.AllocateTimeout, DestroyTimeout: cfg.DestroyTimeout},
+	)
+	assert.NoError(t, err)
+	defer p.Destroy()
+
+	res, err := p.Exec(&Payload{Body: []byte("hello")})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", res.String())
+	assert.Len(t, calls, 1)
+}
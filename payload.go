@@ -1,5 +1,7 @@
 package roadrunner
 
+import "time"
+
 // Payload carries binary header and body to workers and
 // back to the server.
 type Payload struct {
@@ -8,9 +10,40 @@ type Payload struct {
 
 	// body contains binary payload to be processed by worker.
 	Body []byte
+
+	// Tag identifies origin of the payload (for example request id), stderr output produced by the worker while
+	// executing the payload is prefixed with the tag. Tag is not sent to the worker.
+	Tag string
+
+	// Timings, when set, receive stages of the payload execution from the pool, timings must not be read until
+	// execution completes. Timings are not sent to the worker.
+	Timings *Timings
+
+	// pid of the worker which has produced the payload.
+	pid int
+}
+
+// Pid returns pid of the worker which has produced the payload, 0 for payloads not produced by workers.
+func (p *Payload) Pid() int {
+	return p.pid
 }
 
 // String returns payload body as string
 func (p *Payload) String() string {
 	return string(p.Body)
 }
+
+// Timings describe stages of the payload execution by the worker pool.
+type Timings struct {
+	// Allocate is the moment pool has started to wait for the free worker.
+	Allocate time.Time
+
+	// Exec is the moment worker has been allocated and payload has been sent to the worker.
+	Exec time.Time
+
+	// Done is the moment worker has responded or failed, zero if worker has never been allocated.
+	Done time.Time
+
+	// Pid of the worker which has executed the payload.
+	Pid int
+}
@@ -3,7 +3,6 @@ package roadrunner
 import (
 	"fmt"
 	"github.com/pkg/errors"
-	"github.com/spiral/goridge/v2"
 	"io"
 	"os/exec"
 )
@@ -39,7 +38,7 @@ func (f *PipeFactory) SpawnWorker(cmd *exec.Cmd) (w *Worker, err error) {
 		return nil, err
 	}
 
-	w.rl = goridge.NewPipeRelay(in, out)
+	w.rl = newPipeRelay(in, out)
 
 	if err := w.start(); err != nil {
 		return nil, errors.Wrap(err, "process error")
@@ -55,7 +54,7 @@ func (f *PipeFactory) SpawnWorker(cmd *exec.Cmd) (w *Worker, err error) {
 		}(w)
 
 		if wErr := w.Wait(); wErr != nil {
-			if _, ok := wErr.(*exec.ExitError); ok {
+			if exit, ok := wErr.(*WorkerExit); ok && exit.output == "" {
 				// error might be nil here
 				if err != nil {
 					err = errors.Wrap(wErr, err.Error())
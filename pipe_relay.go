@@ -0,0 +1,123 @@
+package roadrunner
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spiral/goridge/v2"
+)
+
+const (
+	// StrayOutputLimit limits amount of non protocol output captured from the worker.
+	StrayOutputLimit = 4096
+
+	// StrayOutputTimeout defines for how long relay collects non protocol output after it's detection.
+	StrayOutputTimeout = 10 * time.Millisecond
+
+	// number of captured bytes to be included into error message
+	previewSize = 128
+
+	// flags allowed to be sent by the worker
+	prefixFlags = goridge.PayloadEmpty | goridge.PayloadRaw | goridge.PayloadError | goridge.PayloadControl
+)
+
+// strayOutput is returned by relay when non protocol data found in the stream.
+type strayOutput []byte
+
+// Error returns captured output preview.
+func (so strayOutput) Error() string {
+	return fmt.Sprintf("invalid data found in the relay (possible echo): %s", preview(so))
+}
+
+// pipeRelay communicates with worker using standard streams. Unlike goridge.PipeRelay every
+// received prefix is validated strictly, non protocol output (echo, var_dump, notices and etc)
+// is captured and returned as strayOutput.
+type pipeRelay struct {
+	in  io.ReadCloser
+	out io.WriteCloser
+}
+
+// newPipeRelay creates new validating pipe relay.
+func newPipeRelay(in io.ReadCloser, out io.WriteCloser) *pipeRelay {
+	return &pipeRelay{in: in, out: out}
+}
+
+// Send signed (prefixed) data to underlying process.
+func (rl *pipeRelay) Send(data []byte, flags byte) error {
+	prefix := goridge.NewPrefix().WithFlags(flags).WithSize(uint64(len(data)))
+	_, err := rl.out.Write(append(prefix[:], data...))
+
+	return err
+}
+
+// Receive data from the underlying process and returns associated prefix or error.
+func (rl *pipeRelay) Receive() (data []byte, p goridge.Prefix, err error) {
+	if n, err := io.ReadFull(rl.in, p[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, p, rl.capture(p[:n])
+		}
+
+		return nil, p, err
+	}
+
+	if !validPrefix(p) {
+		return nil, p, rl.capture(p[:])
+	}
+
+	if !p.HasPayload() {
+		return nil, p, nil
+	}
+
+	data = make([]byte, p.Size())
+	if _, err := io.ReadFull(rl.in, data); err != nil {
+		return nil, p, err
+	}
+
+	return data, p, nil
+}
+
+// Close the connection. Pipes are closed automatically with the underlying process.
+func (rl *pipeRelay) Close() error {
+	return nil
+}
+
+// capture collects given bytes and non protocol output immediately available in the stream.
+func (rl *pipeRelay) capture(read []byte) strayOutput {
+	out := append(make([]byte, 0, StrayOutputLimit), read...)
+
+	d, ok := rl.in.(interface{ SetReadDeadline(t time.Time) error })
+	if !ok || d.SetReadDeadline(time.Now().Add(StrayOutputTimeout)) != nil {
+		return out
+	}
+	defer func() {
+		_ = d.SetReadDeadline(time.Time{})
+	}()
+
+	buf := make([]byte, StrayOutputLimit)
+	for len(out) < StrayOutputLimit {
+		n, err := rl.in.Read(buf[:StrayOutputLimit-len(out)])
+		out = append(out, buf[:n]...)
+
+		if err != nil {
+			break
+		}
+	}
+
+	return out
+}
+
+// validPrefix returns true if prefix is signed properly and contains only known flags, worker must
+// always send at least one flag.
+func validPrefix(p goridge.Prefix) bool {
+	return p.Valid() && p.Flags() != 0 && p.Flags()&^prefixFlags == 0
+}
+
+// preview returns quoted beginning of the given output.
+func preview(data []byte) string {
+	if len(data) > previewSize {
+		return fmt.Sprintf("%q...", data[:previewSize])
+	}
+
+	return fmt.Sprintf("%q", data)
+}
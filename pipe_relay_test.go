@@ -0,0 +1,151 @@
+package roadrunner
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/spiral/goridge/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PipeRelay_Receive(t *testing.T) {
+	in, out := io.Pipe()
+	rl := newPipeRelay(in, out)
+
+	go func() {
+		assert.NoError(t, goridge.NewPipeRelay(in, out).Send([]byte("hello"), goridge.PayloadRaw))
+	}()
+
+	data, p, err := rl.Receive()
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.True(t, p.HasFlag(goridge.PayloadRaw))
+}
+
+func Test_PipeRelay_ReceiveEmpty(t *testing.T) {
+	in, out := io.Pipe()
+	rl := newPipeRelay(in, out)
+
+	go func() {
+		assert.NoError(t, rl.Send(nil, goridge.PayloadControl|goridge.PayloadEmpty))
+	}()
+
+	data, p, err := rl.Receive()
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+	assert.True(t, p.HasFlag(goridge.PayloadControl))
+}
+
+func Test_PipeRelay_StrayOutput(t *testing.T) {
+	in, out, err := os.Pipe()
+	assert.NoError(t, err)
+	defer in.Close()
+
+	rl := newPipeRelay(in, out)
+
+	_, err = out.Write([]byte("Notice: Undefined variable: a in worker.php on line 10\n"))
+	assert.NoError(t, err)
+
+	_, _, err = rl.Receive()
+	assert.Error(t, err)
+	assert.IsType(t, strayOutput{}, err)
+	assert.Equal(t, "Notice: Undefined variable: a in worker.php on line 10\n", string(err.(strayOutput)))
+	assert.Contains(t, err.Error(), "possible echo")
+}
+
+func Test_PipeRelay_ShortStrayOutput(t *testing.T) {
+	in, out := io.Pipe()
+	rl := newPipeRelay(in, out)
+
+	go func() {
+		_, err := out.Write([]byte("hello"))
+		assert.NoError(t, err)
+		assert.NoError(t, out.Close())
+	}()
+
+	_, _, err := rl.Receive()
+	assert.Error(t, err)
+	assert.Equal(t, "hello", string(err.(strayOutput)))
+}
+
+func Test_PipeRelay_InvalidFlags(t *testing.T) {
+	in, out := io.Pipe()
+	rl := newPipeRelay(in, out)
+
+	go func() {
+		assert.NoError(t, rl.Send([]byte("hello"), 0))
+	}()
+
+	_, _, err := rl.Receive()
+	assert.Error(t, err)
+	assert.IsType(t, strayOutput{}, err)
+}
+
+func Test_Worker_StrayOutput(t *testing.T) {
+	w, err := newWorker(exec.Command("php", "tests/client.php", "stray", "pipes"))
+	assert.NoError(t, err)
+
+	sIn, wOut, err := os.Pipe()
+	assert.NoError(t, err)
+	defer sIn.Close()
+
+	wIn, sOut := io.Pipe()
+
+	pid := 100
+	w.Pid = &pid
+	w.rl = newPipeRelay(sIn, sOut)
+	w.state.set(StateReady)
+
+	go func() {
+		rl := goridge.NewPipeRelay(wIn, wOut)
+		_, _, err := rl.Receive()
+		assert.NoError(t, err)
+		_, _, err = rl.Receive()
+		assert.NoError(t, err)
+
+		_, err = wOut.Write([]byte("stray output"))
+		assert.NoError(t, err)
+		assert.NoError(t, rl.Send([]byte("hello"), goridge.PayloadControl|goridge.PayloadRaw))
+	}()
+
+	res, err := w.Exec(&Payload{Body: []byte("hello")})
+	assert.Nil(t, res)
+	assert.Error(t, err)
+	assert.Equal(t, StateErrored, w.State().Value())
+
+	so, ok := err.(StrayOutputError)
+	assert.True(t, ok)
+	assert.Equal(t, 100, so.Pid)
+	assert.Contains(t, string(so.Output), "stray output")
+	assert.Contains(t, so.Error(), "worker 100")
+	assert.Contains(t, so.Dump(), "73 74 72 61 79")
+}
+
+func Test_Pipe_StrayOutput(t *testing.T) {
+	p, err := NewPool(
+		func() *exec.Cmd { return exec.Command("php", "tests/client.php", "stray", "pipes") },
+		NewPipeFactory(),
+		Config{NumWorkers: 1, AllocateTimeout: cfg.AllocateTimeout, DestroyTimeout: cfg.DestroyTimeout},
+	)
+	assert.NoError(t, err)
+	defer p.Destroy()
+
+	events := make(chan StrayOutputError, 1)
+	p.Listen(func(event int, ctx interface{}) {
+		if event == EventWorkerStrayOutput {
+			events <- ctx.(StrayOutputError)
+		}
+	})
+
+	w := p.Workers()[0]
+
+	res, err := p.Exec(&Payload{Body: []byte("hello")})
+	assert.Nil(t, res)
+	assert.IsType(t, StrayOutputError{}, err)
+
+	so := <-events
+	assert.Equal(t, *w.Pid, so.Pid)
+	assert.Contains(t, string(so.Output), "stray output")
+}
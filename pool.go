@@ -1,5 +1,7 @@
 package roadrunner
 
+import "time"
+
 const (
 	// EventWorkerConstruct thrown when new worker is spawned.
 	EventWorkerConstruct = iota + 100
@@ -18,8 +20,29 @@ const (
 
 	// EventPoolError caused on pool wide errors
 	EventPoolError
+
+	// EventWorkerStrayOutput thrown when worker writes non protocol data into the relay (passed with StrayOutputError).
+	EventWorkerStrayOutput
+
+	// EventPoolDrain thrown when pool completes or aborts active executions while being destroyed (passed with DrainEvent).
+	EventPoolDrain
+
+	// EventPoolResize thrown when number of pool workers changed (passed with new number of workers).
+	EventPoolResize
 )
 
+// DrainEvent describes active executions handled by the pool while being destroyed.
+type DrainEvent struct {
+	// Drained contains number of executions completed within the drain timeout.
+	Drained int64
+
+	// Aborted contains number of executions aborted after the drain timeout.
+	Aborted int64
+
+	// Elapsed contains drain duration.
+	Elapsed time.Duration
+}
+
 // Pool managed set of inner worker processes.
 type Pool interface {
 	// Listen all caused events to attached controller.
@@ -34,6 +57,12 @@ type Pool interface {
 	// Remove forces pool to remove specific worker. Return true is this is first remove request on given worker.
 	Remove(w *Worker, err error) bool
 
-	// Destroy all underlying workers (but let them to complete the task).
+	// Resize changes number of pool workers in place.
+	Resize(n int64) error
+
+	// Reconfigure applies number of workers, job limit and timeouts without re-creating the pool.
+	Reconfigure(cfg Config) error
+
+	// Destroy all underlying workers (but let them to complete the task within drain timeout).
 	Destroy()
 }
@@ -0,0 +1,69 @@
+package roadrunner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// process represents underlying worker process and controls it's lifecycle.
+type process interface {
+	// start starts the process, must not block.
+	start() error
+
+	// pid returns process identifier, only available after start.
+	pid() int
+
+	// kill forcefully terminates the process.
+	kill() error
+
+	// wait blocks until process is complete and returns nil if process exited
+	// successfully or error (*exec.ExitError for os processes).
+	wait() error
+}
+
+// osProcess is system process started using exec.Cmd.
+type osProcess struct {
+	cmd *exec.Cmd
+}
+
+func (p *osProcess) start() error {
+	return p.cmd.Start()
+}
+
+func (p *osProcess) pid() int {
+	return p.cmd.Process.Pid
+}
+
+func (p *osProcess) kill() error {
+	return p.cmd.Process.Signal(os.Kill)
+}
+
+func (p *osProcess) wait() error {
+	st, err := p.cmd.Process.Wait()
+	if err != nil {
+		return err
+	}
+
+	if !st.Success() {
+		return &exec.ExitError{ProcessState: st}
+	}
+
+	return nil
+}
+
+// exitError describes abnormal termination of processes which are not managed by os.
+type exitError struct {
+	code   int
+	signal syscall.Signal
+}
+
+// Error returns process status.
+func (e *exitError) Error() string {
+	if e.signal != 0 {
+		return fmt.Sprintf("signal: %s", e.signal)
+	}
+
+	return fmt.Sprintf("exit status %v", e.code)
+}
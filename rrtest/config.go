@@ -0,0 +1,61 @@
+package rrtest
+
+import (
+	json "github.com/json-iterator/go"
+	"github.com/spiral/roadrunner/service"
+)
+
+// Config maps service names to their JSON configuration sections.
+type Config map[string]string
+
+// Get returns config section of the given service or nil if section is missing.
+func (c Config) Get(name string) service.Config {
+	data, ok := c[name]
+	if !ok {
+		return nil
+	}
+
+	return section(data)
+}
+
+// Unmarshal unmarshal whole config into given struct.
+func (c Config) Unmarshal(out interface{}) error {
+	sections := make(map[string]json.RawMessage)
+	for name, data := range c {
+		sections[name] = json.RawMessage(data)
+	}
+
+	j := json.ConfigCompatibleWithStandardLibrary
+	data, err := j.Marshal(sections)
+	if err != nil {
+		return err
+	}
+
+	return j.Unmarshal(data, out)
+}
+
+// section is single JSON config section.
+type section string
+
+// Get returns nested config section or nil if section is missing.
+func (s section) Get(name string) service.Config {
+	sections := make(map[string]json.RawMessage)
+
+	j := json.ConfigCompatibleWithStandardLibrary
+	if err := j.Unmarshal([]byte(s), &sections); err != nil {
+		return nil
+	}
+
+	data, ok := sections[name]
+	if !ok {
+		return nil
+	}
+
+	return section(data)
+}
+
+// Unmarshal unmarshal config data into given struct.
+func (s section) Unmarshal(out interface{}) error {
+	j := json.ConfigCompatibleWithStandardLibrary
+	return j.Unmarshal([]byte(s), out)
+}
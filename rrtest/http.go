@@ -0,0 +1,222 @@
+package rrtest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	json "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiral/roadrunner"
+	"github.com/spiral/roadrunner/service"
+	rrhttp "github.com/spiral/roadrunner/service/http"
+)
+
+// StartTimeout defines for how long Start waits for all services to become ready.
+const StartTimeout = 5 * time.Second
+
+// HTTP runs http service with in-process workers.
+type HTTP struct {
+	// Container holds http service and any additional services (middlewares).
+	Container service.Container
+
+	// Service is http service served by the container.
+	Service *rrhttp.Service
+
+	// Address of running http server, assigned by Start.
+	Address string
+
+	// Logger receives all container logs.
+	Logger *logrus.Logger
+}
+
+// NewHTTP creates container with http service which handles requests using given worker function.
+// Additional services can be registered in the container prior to Start.
+func NewHTTP(worker roadrunner.WorkerFunc) *HTTP {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	h := &HTTP{
+		Container: service.NewContainer(logger),
+		Service:   &rrhttp.Service{},
+		Logger:    logger,
+	}
+
+	h.Service.ProduceFactory(func(cfg *roadrunner.ServerConfig) (roadrunner.Factory, error) {
+		return roadrunner.NewInProcessFactory(worker), nil
+	})
+
+	h.Container.Register(rrhttp.ID, h.Service)
+
+	return h
+}
+
+// StartHTTP starts http service with single worker handled by the given handler and the service registered
+// under the given name. Config contains sections of the registered service, http section is optional.
+func StartHTTP(t testing.TB, handler http.Handler, name string, svc interface{}, cfg Config) *HTTP {
+	t.Helper()
+
+	h := NewHTTP(HTTPWorker(handler))
+	h.Container.Register(name, svc)
+
+	merged := Config{rrhttp.ID: `{"workers":{"pool":{"numWorkers": 1}}}`}
+	for name, section := range cfg {
+		merged[name] = section
+	}
+
+	h.Start(t, merged)
+
+	return h
+}
+
+// Start configures and serves all registered services, http server address is always assigned
+// automatically. Container is stopped on test cleanup.
+func (h *HTTP) Start(t testing.TB, cfg Config) {
+	t.Helper()
+
+	address, err := freeAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j := json.ConfigCompatibleWithStandardLibrary
+
+	httpCfg := make(map[string]interface{})
+	if data, ok := cfg[rrhttp.ID]; ok {
+		if err := j.Unmarshal([]byte(data), &httpCfg); err != nil {
+			t.Fatalf("invalid http config: %v", err)
+		}
+	}
+	httpCfg["address"] = address
+
+	data, err := j.Marshal(httpCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged := Config{rrhttp.ID: string(data)}
+	for name, section := range cfg {
+		if name != rrhttp.ID {
+			merged[name] = section
+		}
+	}
+
+	if err := h.Container.Init(merged); err != nil {
+		t.Fatal(err)
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- h.Container.Serve()
+	}()
+	t.Cleanup(h.Container.Stop)
+
+	h.Address = address
+
+	timer := time.NewTimer(StartTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-h.Container.Ready():
+		go func() {
+			if err := <-errc; err != nil {
+				t.Errorf("serve error: %v", err)
+			}
+		}()
+	case err := <-errc:
+		t.Fatalf("serve error: %v", err)
+	case <-timer.C:
+		t.Fatalf("http server is not ready after %s", StartTimeout)
+	}
+}
+
+// URL returns absolute url of the given path.
+func (h *HTTP) URL(path string) string {
+	return fmt.Sprintf("http://%s%s", h.Address, path)
+}
+
+// Get sends GET request with given headers to the http server, returns response and it's body. Test is
+// failed if request can not be sent.
+func (h *HTTP) Get(t testing.TB, path string, header map[string]string) (*http.Response, string) {
+	t.Helper()
+
+	r, err := http.NewRequest("GET", h.URL(path), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+
+	rsp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsp.Body.Close()
+
+	b, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rsp, string(b)
+}
+
+// HTTPWorker converts http.Handler into worker function which speaks PSR-7 protocol of the http
+// service. Parsed bodies (forms and multipart) are passed to the handler as JSON tree.
+func HTTPWorker(handler http.Handler) roadrunner.WorkerFunc {
+	return func(rqs *roadrunner.Payload) (*roadrunner.Payload, error) {
+		ctx := struct {
+			RemoteAddr string      `json:"remoteAddr"`
+			Protocol   string      `json:"protocol"`
+			Method     string      `json:"method"`
+			URI        string      `json:"uri"`
+			Header     http.Header `json:"headers"`
+		}{}
+
+		j := json.ConfigCompatibleWithStandardLibrary
+		if err := j.Unmarshal(rqs.Context, &ctx); err != nil {
+			return nil, err
+		}
+
+		r, err := http.NewRequest(ctx.Method, ctx.URI, bytes.NewReader(rqs.Body))
+		if err != nil {
+			return nil, err
+		}
+
+		r.RemoteAddr = ctx.RemoteAddr
+		r.Proto = ctx.Protocol
+		if ctx.Header != nil {
+			r.Header = ctx.Header
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		rsp := &roadrunner.Payload{Body: w.Body.Bytes()}
+		rsp.Context, err = j.Marshal(&rrhttp.Response{Status: w.Code, Headers: w.Header()})
+		if err != nil {
+			return nil, err
+		}
+
+		return rsp, nil
+	}
+}
+
+// freeAddress finds available local tcp address.
+func freeAddress() (string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+
+	defer ln.Close()
+	return ln.Addr().String(), nil
+}
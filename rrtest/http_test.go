@@ -0,0 +1,81 @@
+package rrtest
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/spiral/roadrunner"
+	"github.com/spiral/roadrunner/service/headers"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HTTP_Echo(t *testing.T) {
+	h := NewHTTP(HTTPWorker(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Method", r.Method)
+		w.WriteHeader(201)
+		_, _ = w.Write([]byte(r.URL.Query().Get("hello")))
+	})))
+
+	h.Start(t, Config{"http": `{"workers":{"pool":{"numWorkers": 2}}}`})
+
+	r, err := http.Get(h.URL("/?hello=world"))
+	assert.NoError(t, err)
+	defer r.Body.Close()
+
+	b, err := ioutil.ReadAll(r.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 201, r.StatusCode)
+	assert.Equal(t, "GET", r.Header.Get("X-Method"))
+	assert.Equal(t, "world", string(b))
+	assert.Len(t, h.Service.Server().Workers(), 2)
+}
+
+func Test_HTTP_Error(t *testing.T) {
+	h := NewHTTP(func(rqs *roadrunner.Payload) (*roadrunner.Payload, error) {
+		return nil, errors.New("failure")
+	})
+
+	h.Start(t, Config{"http": `{"workers":{"pool":{"numWorkers": 1}}}`})
+
+	r, err := http.Get(h.URL("/"))
+	assert.NoError(t, err)
+	defer r.Body.Close()
+
+	assert.Equal(t, 500, r.StatusCode)
+}
+
+func Test_StartHTTP(t *testing.T) {
+	h := StartHTTP(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("input")))
+	}), headers.ID, &headers.Service{}, Config{
+		"headers": `{"request":{"input": "custom-header"}}`,
+	})
+
+	r, body := h.Get(t, "/", nil)
+	assert.Equal(t, 200, r.StatusCode)
+	assert.Equal(t, "custom-header", body)
+	assert.Len(t, h.Service.Server().Workers(), 1)
+}
+
+func Test_HTTP_Middleware(t *testing.T) {
+	h := NewHTTP(HTTPWorker(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("input")))
+	})))
+	h.Container.Register(headers.ID, &headers.Service{})
+
+	h.Start(t, Config{
+		"http":    `{"workers":{"pool":{"numWorkers": 1}}}`,
+		"headers": `{"request":{"input": "custom-header"}}`,
+	})
+
+	r, err := http.Get(h.URL("/"))
+	assert.NoError(t, err)
+	defer r.Body.Close()
+
+	b, err := ioutil.ReadAll(r.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "custom-header", string(b))
+}
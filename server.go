@@ -143,8 +143,9 @@ func (s *Server) Exec(rqs *Payload) (rsp *Payload, err error) {
 	return pool.Exec(rqs)
 }
 
-// Reconfigure re-configures underlying pool and destroys it's previous version if any. Reconfigure will ignore factory
-// and relay settings.
+// Reconfigure re-configures underlying pool and destroys it's previous version if any. Changes of the
+// number of workers, job limit or timeouts are applied to the running pool without re-creating it. Relay
+// changes migrate the pool to the new factory, previous factory is closed once it's pool is destroyed.
 func (s *Server) Reconfigure(cfg *ServerConfig) error {
 	s.mup.Lock()
 	defer s.mup.Unlock()
@@ -158,15 +159,108 @@ func (s *Server) Reconfigure(cfg *ServerConfig) error {
 	s.mu.Unlock()
 
 	if s.cfg.Differs(cfg) {
-		return errors.New("unable to reconfigure server (cmd and pool changes are allowed)")
+		return s.migrateFactory(cfg)
 	}
 
+	if s.cfg.adjustable(cfg) {
+		if err := s.Pool().Reconfigure(*cfg.Pool); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		s.cfg = cfg
+		s.mu.Unlock()
+
+		return nil
+	}
+
+	return s.replacePool(cfg, s.Factory())
+}
+
+// Resize changes number of workers of the underlying pool without re-creating it.
+func (s *Server) Resize(numWorkers int64) error {
+	s.mup.Lock()
+	defer s.mup.Unlock()
+
+	s.mu.Lock()
+	pool, started := s.pool, s.started
+	cfg := *s.cfg.Pool
+	s.mu.Unlock()
+
+	cfg.NumWorkers = numWorkers
+	if err := cfg.Valid(); err != nil {
+		return err
+	}
+
+	if started {
+		if err := pool.Resize(numWorkers); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	s.cfg.Pool = &cfg
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Reset resets the state of underlying pool and rebuilds all of it's workers.
+func (s *Server) Reset() error {
+	s.mup.Lock()
+	defer s.mup.Unlock()
+
+	s.mu.Lock()
+	cfg, started := s.cfg, s.started
+	s.mu.Unlock()
+
+	if !started {
+		return nil
+	}
+
+	return s.replacePool(cfg, s.Factory())
+}
+
+// migrateFactory creates factory using new relay configuration and replaces the pool. Server keeps
+// using previous factory and pool if new pool can not be started.
+func (s *Server) migrateFactory(cfg *ServerConfig) (err error) {
+	var (
+		previous = s.Factory()
+		factory  Factory
+	)
+
+	// same socket can't be listened twice, new factory takes over the listener
+	sf, handoff := previous.(*SocketFactory)
+	if handoff = handoff && cfg.FactoryProducer == nil && s.cfg.Relay == cfg.Relay; handoff {
+		factory = sf.handoff(cfg.RelayTimeout)
+	} else if factory, err = cfg.makeFactory(); err != nil {
+		return errors.Wrap(err, "unable to reconfigure server")
+	}
+
+	if err = s.replacePool(cfg, factory); err != nil {
+		// rollback
+		if handoff {
+			sf.revoke()
+		} else {
+			factory.Close()
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// replacePool creates new pool using given configuration and factory and destroys the previous pool
+// in background. Previous factory is closed after it's pool is destroyed if factory has changed.
+func (s *Server) replacePool(cfg *ServerConfig, factory Factory) error {
 	s.mu.Lock()
 	previous := s.pool
 	pWatcher := s.pController
+	pFactory := s.factory
 	s.mu.Unlock()
 
-	pool, err := NewPool(cfg.makeCommand(), s.factory, *cfg.Pool)
+	pool, err := NewPool(cfg.makeCommand(), factory, *cfg.Pool)
 	if err != nil {
 		return err
 	}
@@ -174,7 +268,7 @@ func (s *Server) Reconfigure(cfg *ServerConfig) error {
 	pool.Listen(s.poolListener)
 
 	s.mu.Lock()
-	s.cfg.Pool, s.pool = cfg.Pool, pool
+	s.cfg, s.pool, s.factory = cfg, pool, factory
 
 	if s.controller != nil {
 		s.pController = s.controller.Attach(pool)
@@ -192,21 +286,15 @@ func (s *Server) Reconfigure(cfg *ServerConfig) error {
 			}
 
 			previous.Destroy()
+			if pFactory != factory {
+				pFactory.Close()
+			}
 		}(previous, pWatcher)
 	}
 
 	return nil
 }
 
-// Reset resets the state of underlying pool and rebuilds all of it's workers.
-func (s *Server) Reset() error {
-	s.mu.Lock()
-	cfg := s.cfg
-	s.mu.Unlock()
-
-	return s.Reconfigure(cfg)
-}
-
 // Workers returns worker list associated with the server pool.
 func (s *Server) Workers() (workers []*Worker) {
 	p := s.Pool()
@@ -217,6 +305,14 @@ func (s *Server) Workers() (workers []*Worker) {
 	return p.Workers()
 }
 
+// Factory returns active worker factory.
+func (s *Server) Factory() Factory {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.factory
+}
+
 // Pool returns active pool or error.
 func (s *Server) Pool() Pool {
 	s.mu.Lock()
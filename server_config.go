@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"reflect"
 	"strings"
 	"sync"
 	"syscall"
@@ -16,6 +17,12 @@ import (
 // CommandProducer can produce commands.
 type CommandProducer func(cfg *ServerConfig) func() *exec.Cmd
 
+// FactoryProducer can produce worker factories.
+type FactoryProducer func(cfg *ServerConfig) (Factory, error)
+
+// ListenerProducer can produce relay listeners by the relay DSN.
+type ListenerProducer func(address string) (net.Listener, error)
+
 // ServerConfig config combines factory, pool and cmd configurations.
 type ServerConfig struct {
 	// Command includes command strings with all the parameters, example: "php worker.php pipes".
@@ -27,6 +34,13 @@ type ServerConfig struct {
 	// CommandProducer overwrites
 	CommandProducer CommandProducer
 
+	// FactoryProducer overwrites default factory creation based on Relay value.
+	FactoryProducer FactoryProducer
+
+	// ListenerProducer overwrites creation of the socket relay listener, i.e. to pass the listener to the upgraded
+	// process.
+	ListenerProducer ListenerProducer
+
 	// Relay defines connection method and factory to be used to connect to workers:
 	// "pipes", "tcp://:6001", "unix://rr.sock"
 	// This config section must not change on re-configuration.
@@ -70,13 +84,32 @@ func (cfg *ServerConfig) UpscaleDurations() {
 	if cfg.Pool.DestroyTimeout < time.Microsecond {
 		cfg.Pool.DestroyTimeout = time.Second * time.Duration(cfg.Pool.DestroyTimeout.Nanoseconds())
 	}
+
+	if cfg.Pool.DrainTimeout < time.Microsecond {
+		cfg.Pool.DrainTimeout = time.Second * time.Duration(cfg.Pool.DrainTimeout.Nanoseconds())
+	}
 }
 
-// Differs returns true if configuration has changed but ignores pool or cmd changes.
+// Differs returns true if relay configuration has changed and workers must be connected using new factory.
+// Ignores pool or cmd changes.
 func (cfg *ServerConfig) Differs(new *ServerConfig) bool {
 	return cfg.Relay != new.Relay || cfg.RelayTimeout != new.RelayTimeout
 }
 
+// adjustable returns true if new configuration only changes pool settings which can be applied to the
+// running pool (number of workers, job limit and timeouts).
+func (cfg *ServerConfig) adjustable(new *ServerConfig) bool {
+	if cfg == new {
+		return true
+	}
+
+	if cfg.Command != new.Command || cfg.User != new.User || cfg.CommandProducer != nil || new.CommandProducer != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(cfg.envMap(), new.envMap())
+}
+
 // SetEnv sets new environment variable. Value is automatically uppercase-d.
 func (cfg *ServerConfig) SetEnv(k, v string) {
 	cfg.mu.Lock()
@@ -101,6 +134,19 @@ func (cfg *ServerConfig) GetEnv() (env []string) {
 
 //=================================== PRIVATE METHODS ======================================================
 
+// envMap returns copy of configured environment values.
+func (cfg *ServerConfig) envMap() map[string]string {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
+	env := make(map[string]string, len(cfg.env))
+	for k, v := range cfg.env {
+		env[k] = v
+	}
+
+	return env
+}
+
 func (cfg *ServerConfig) makeCommand() func() *exec.Cmd {
 	cfg.mu.Lock()
 	defer cfg.mu.Unlock()
@@ -133,10 +179,23 @@ func (cfg *ServerConfig) makeCommand() func() *exec.Cmd {
 
 // makeFactory creates and connects new factory instance based on given parameters.
 func (cfg *ServerConfig) makeFactory() (Factory, error) {
+	if cfg.FactoryProducer != nil {
+		return cfg.FactoryProducer(cfg)
+	}
+
 	if cfg.Relay == "pipes" || cfg.Relay == "pipe" {
 		return NewPipeFactory(), nil
 	}
 
+	if cfg.ListenerProducer != nil {
+		ln, err := cfg.ListenerProducer(cfg.Relay)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewSocketFactory(ln, cfg.RelayTimeout), nil
+	}
+
 	dsn := strings.Split(cfg.Relay, "://")
 	if len(dsn) != 2 {
 		return nil, errors.New("invalid relay DSN (pipes, tcp://:6001, unix://rr.sock)")
@@ -154,6 +213,12 @@ func (cfg *ServerConfig) makeFactory() (Factory, error) {
 		return nil, err
 	}
 
+	if ul, ok := ln.(*net.UnixListener); ok {
+		if ln, err = newUnixListener(ul, dsn[1]); err != nil {
+			return nil, err
+		}
+	}
+
 	return NewSocketFactory(ln, cfg.RelayTimeout), nil
 }
 
@@ -166,3 +231,34 @@ func fileExists(filename string) bool {
 	}
 	return !info.IsDir()
 }
+
+// unixListener removes socket file on close only if the file has not been replaced by another
+// process (for example by the upgraded server).
+type unixListener struct {
+	*net.UnixListener
+	path string
+	info os.FileInfo
+}
+
+// newUnixListener wraps listener created on the given socket path.
+func newUnixListener(ln *net.UnixListener, path string) (*unixListener, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		_ = ln.Close()
+		return nil, err
+	}
+
+	ln.SetUnlinkOnClose(false)
+	return &unixListener{UnixListener: ln, path: path, info: info}, nil
+}
+
+// Close closes the listener and removes socket file it has created.
+func (l *unixListener) Close() error {
+	err := l.UnixListener.Close()
+
+	if info, serr := os.Stat(l.path); serr == nil && os.SameFile(info, l.info) {
+		_ = os.Remove(l.path)
+	}
+
+	return err
+}
@@ -1,7 +1,9 @@
 package roadrunner
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"net"
 	"testing"
 	"time"
 )
@@ -63,6 +65,29 @@ func Test_ServerConfig_SocketFactory(t *testing.T) {
 	assert.Equal(t, "127.0.0.1:9112", f.(*SocketFactory).ls.Addr().String())
 }
 
+func Test_ServerConfig_ListenerProducer(t *testing.T) {
+	var address string
+	cfg := &ServerConfig{Relay: "tcp://127.0.0.1:9113", ListenerProducer: func(a string) (net.Listener, error) {
+		address = a
+		return net.Listen("tcp", "127.0.0.1:9113")
+	}}
+
+	f, err := cfg.makeFactory()
+	assert.NoError(t, err)
+	defer f.Close()
+
+	assert.Equal(t, "tcp://127.0.0.1:9113", address)
+	assert.IsType(t, &SocketFactory{}, f)
+	assert.Equal(t, "127.0.0.1:9113", f.(*SocketFactory).ls.Addr().String())
+
+	cfg = &ServerConfig{Relay: "tcp://127.0.0.1:9114", ListenerProducer: func(a string) (net.Listener, error) {
+		return nil, errors.New("failure")
+	}}
+
+	_, err = cfg.makeFactory()
+	assert.Error(t, err)
+}
+
 func Test_ServerConfig_UnixSocketFactory(t *testing.T) {
 	cfg := &ServerConfig{Relay: "unix://unix.sock"}
 	f, err := cfg.makeFactory()
@@ -83,6 +108,22 @@ func Test_ServerConfig_UnixSocketFactory(t *testing.T) {
 	assert.Equal(t, "unix.sock", f.(*SocketFactory).ls.Addr().String())
 }
 
+func Test_ServerConfig_UnixSocketFactory_Replaced(t *testing.T) {
+	cfg := &ServerConfig{Relay: "unix://replaced.sock"}
+	f1, err := cfg.makeFactory()
+	assert.NoError(t, err)
+
+	// socket file is replaced by another server
+	f2, err := cfg.makeFactory()
+	assert.NoError(t, err)
+
+	assert.NoError(t, f1.Close())
+	assert.True(t, fileExists("replaced.sock"))
+
+	assert.NoError(t, f2.Close())
+	assert.False(t, fileExists("replaced.sock"))
+}
+
 func Test_ServerConfig_ErrorFactory(t *testing.T) {
 	cfg := &ServerConfig{Relay: "uni:unix.sock"}
 	f, err := cfg.makeFactory()
@@ -251,3 +251,179 @@ func TestServer_ServerFailure(t *testing.T) {
 	<-failure
 	assert.True(t, true)
 }
+
+func TestServer_Reconfigure_Live(t *testing.T) {
+	rr := NewServer(
+		&ServerConfig{
+			Command:         "go worker",
+			FactoryProducer: func(cfg *ServerConfig) (Factory, error) { return NewInProcessFactory(echoFunc), nil },
+			Pool: &Config{
+				NumWorkers:      1,
+				AllocateTimeout: time.Second,
+				DestroyTimeout:  time.Second,
+			},
+		})
+	defer rr.Stop()
+
+	assert.NoError(t, rr.Start())
+
+	constructed := make(chan interface{}, 1)
+	rr.Listen(func(e int, ctx interface{}) {
+		if e == EventPoolConstruct {
+			constructed <- ctx
+		}
+	})
+
+	pool := rr.Pool()
+	w := rr.Workers()[0]
+
+	err := rr.Reconfigure(&ServerConfig{
+		Command: "go worker",
+		Pool: &Config{
+			NumWorkers:      3,
+			MaxJobs:         10,
+			AllocateTimeout: time.Second,
+			DestroyTimeout:  time.Second,
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.Same(t, pool, rr.Pool())
+	assert.Len(t, rr.Workers(), 3)
+	assert.Same(t, w, rr.Workers()[0])
+	assert.Equal(t, int64(10), pool.(*StaticPool).Config().MaxJobs)
+
+	assert.NoError(t, rr.Resize(2))
+	assert.Same(t, pool, rr.Pool())
+	assert.Equal(t, int64(2), rr.cfg.Pool.NumWorkers)
+
+	// command change requires new pool
+	err = rr.Reconfigure(&ServerConfig{
+		Command: "go worker2",
+		Pool: &Config{
+			NumWorkers:      1,
+			AllocateTimeout: time.Second,
+			DestroyTimeout:  time.Second,
+		},
+	})
+	assert.NoError(t, err)
+	previous := pool
+	pool = (<-constructed).(Pool)
+	assert.NotSame(t, previous, pool)
+	assert.Len(t, rr.Workers(), 1)
+
+	// pool created with the new command is adjusted in place
+	err = rr.Reconfigure(&ServerConfig{
+		Command: "go worker2",
+		Pool: &Config{
+			NumWorkers:      2,
+			AllocateTimeout: time.Second,
+			DestroyTimeout:  time.Second,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Same(t, pool, rr.Pool())
+	assert.Len(t, rr.Workers(), 2)
+}
+
+// closeFactory tracks factory closing.
+type closeFactory struct {
+	Factory
+	closed chan interface{}
+}
+
+func (f *closeFactory) Close() error {
+	close(f.closed)
+	return f.Factory.Close()
+}
+
+func TestServer_Reconfigure_Relay(t *testing.T) {
+	var factories []*closeFactory
+	producer := func(cfg *ServerConfig) (Factory, error) {
+		f := &closeFactory{Factory: NewInProcessFactory(echoFunc), closed: make(chan interface{})}
+		factories = append(factories, f)
+		return f, nil
+	}
+
+	rr := NewServer(
+		&ServerConfig{
+			Command:         "go worker",
+			Relay:           "pipes",
+			FactoryProducer: producer,
+			Pool: &Config{
+				NumWorkers:      1,
+				AllocateTimeout: time.Second,
+				DestroyTimeout:  time.Second,
+			},
+		})
+	defer rr.Stop()
+
+	assert.NoError(t, rr.Start())
+	pool := rr.Pool()
+
+	err := rr.Reconfigure(&ServerConfig{
+		Command:         "go worker",
+		Relay:           "pipes",
+		RelayTimeout:    time.Second,
+		FactoryProducer: producer,
+		Pool: &Config{
+			NumWorkers:      1,
+			AllocateTimeout: time.Second,
+			DestroyTimeout:  time.Second,
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, factories, 2)
+	assert.Same(t, factories[1], rr.Factory())
+	assert.NotSame(t, pool, rr.Pool())
+	assert.Equal(t, time.Second, rr.cfg.RelayTimeout)
+
+	// previous factory is closed once it's pool is destroyed
+	select {
+	case <-factories[0].closed:
+	case <-time.After(time.Second):
+		t.Fatal("previous factory is not closed")
+	}
+
+	res, err := rr.Exec(&Payload{Body: []byte("hello")})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", res.String())
+}
+
+func TestServer_Reconfigure_Relay_Rollback(t *testing.T) {
+	rr := NewServer(
+		&ServerConfig{
+			Command:         "go worker",
+			Relay:           "pipes",
+			FactoryProducer: func(cfg *ServerConfig) (Factory, error) { return NewInProcessFactory(echoFunc), nil },
+			Pool: &Config{
+				NumWorkers:      1,
+				AllocateTimeout: time.Second,
+				DestroyTimeout:  time.Second,
+			},
+		})
+	defer rr.Stop()
+
+	assert.NoError(t, rr.Start())
+	pool, factory := rr.Pool(), rr.Factory()
+
+	err := rr.Reconfigure(&ServerConfig{
+		Command: "go worker",
+		Relay:   "tcp://localhost:0",
+		Pool: &Config{
+			NumWorkers:      1,
+			AllocateTimeout: time.Second,
+			DestroyTimeout:  time.Second,
+		},
+	})
+	assert.Error(t, err)
+
+	assert.Same(t, pool, rr.Pool())
+	assert.Same(t, factory, rr.Factory())
+	assert.Equal(t, "pipes", rr.cfg.Relay)
+
+	res, err := rr.Exec(&Payload{Body: []byte("hello")})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", res.String())
+}
@@ -0,0 +1,114 @@
+package accesslog
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/spiral/roadrunner/service"
+)
+
+const (
+	// FormatCommon is the Common Log Format.
+	FormatCommon = "common"
+
+	// FormatCombined is the Common Log Format with referer and user agent.
+	FormatCombined = "combined"
+
+	// FormatJSON writes every entry as json object.
+	FormatJSON = "json"
+
+	// syslogPrefix starts address of the syslog unix socket.
+	syslogPrefix = "syslog://"
+)
+
+// Config configures access log.
+type Config struct {
+	// Format of the log entries: common, combined (default), json or Go template with Entry fields, for example
+	// `{{.RemoteAddr}} {{.Method}} {{.URI}} {{.Status}} {{.Duration}} {{.Pool}}:{{.Pid}}`.
+	Format string
+
+	// Output defines where to write the log: stdout (default), stderr, path to the file (reopened on SIGUSR1) or
+	// address of the syslog unix socket prefixed with syslog://, for example syslog:///dev/log.
+	Output string
+
+	// Tag of the syslog messages, defaults to "rr".
+	Tag string
+
+	// Sample defines fraction of the requests to be logged (0.1 - every 10th request in average), requests
+	// responded with 5xx status are always logged. Defaults to 1.
+	Sample float64
+
+	// Exclude contains path prefixes of the requests which must not be logged.
+	Exclude []string
+
+	// parsed custom format
+	template *template.Template
+}
+
+// Hydrate must populate Config values using given Config source. Must return error if Config is not valid.
+func (c *Config) Hydrate(cfg service.Config) error {
+	c.initDefaults()
+	if err := cfg.Unmarshal(c); err != nil {
+		return err
+	}
+
+	if err := c.Valid(); err != nil {
+		return err
+	}
+
+	return c.parseFormat()
+}
+
+// initDefaults sets missing values to their default values. Service is enabled only when config section is
+// present, defaults are not applied otherwise.
+func (c *Config) initDefaults() {
+	c.Format = FormatCombined
+	c.Output = "stdout"
+	c.Tag = "rr"
+	c.Sample = 1
+}
+
+// Valid validates the configuration.
+func (c *Config) Valid() error {
+	if c.Format == "" {
+		return errors.New("access log format is missing")
+	}
+
+	if c.Output == "" || c.Output == syslogPrefix {
+		return errors.New("access log output is missing")
+	}
+
+	if c.Sample <= 0 || c.Sample > 1 {
+		return fmt.Errorf("invalid sample rate %v, must be within (0, 1]", c.Sample)
+	}
+
+	return nil
+}
+
+// Excludes returns true if requests to the given path must not be logged.
+func (c *Config) Excludes(path string) bool {
+	for _, prefix := range c.Exclude {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseFormat parses custom format template.
+func (c *Config) parseFormat() (err error) {
+	switch c.Format {
+	case FormatCommon, FormatCombined, FormatJSON:
+		return nil
+	}
+
+	c.template, err = template.New("accesslog").Parse(c.Format)
+	if err != nil {
+		return fmt.Errorf("invalid access log format: %s", err)
+	}
+
+	return nil
+}
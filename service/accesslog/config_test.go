@@ -0,0 +1,39 @@
+package accesslog
+
+import (
+	"testing"
+
+	"github.com/spiral/roadrunner/rrtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Config_Hydrate(t *testing.T) {
+	cfg := &Config{}
+	assert.NoError(t, cfg.Hydrate(rrtest.Config{"accesslog": `{}`}.Get("accesslog")))
+
+	assert.Equal(t, FormatCombined, cfg.Format)
+	assert.Equal(t, "stdout", cfg.Output)
+	assert.Equal(t, "rr", cfg.Tag)
+	assert.Equal(t, float64(1), cfg.Sample)
+	assert.Nil(t, cfg.template)
+
+	cfg = &Config{}
+	assert.NoError(t, cfg.Hydrate(rrtest.Config{"accesslog": `{"format": "{{.Status}}", "exclude": ["/health"]}`}.Get("accesslog")))
+	assert.NotNil(t, cfg.template)
+	assert.True(t, cfg.Excludes("/health/live"))
+	assert.False(t, cfg.Excludes("/"))
+}
+
+func Test_Config_Hydrate_Error(t *testing.T) {
+	for _, c := range []string{
+		`{"format": ""}`,
+		`{"format": "{{.Status"}`,
+		`{"output": ""}`,
+		`{"output": "syslog://"}`,
+		`{"sample": 0}`,
+		`{"sample": 1.5}`,
+	} {
+		cfg := &Config{}
+		assert.Error(t, cfg.Hydrate(rrtest.Config{"accesslog": c}.Get("accesslog")), c)
+	}
+}
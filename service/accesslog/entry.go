@@ -0,0 +1,140 @@
+package accesslog
+
+import (
+	"bytes"
+	"strconv"
+	"text/template"
+	"time"
+
+	json "github.com/json-iterator/go"
+)
+
+// Entry contains details of the served request.
+type Entry struct {
+	// Time when request has been received.
+	Time time.Time
+
+	// RemoteAddr contains client ip, proxy headers are used for the requests received from trusted subnets.
+	RemoteAddr string
+
+	// User contains basic auth username, if any.
+	User string
+
+	// Method, URI, Protocol and Host of the request.
+	Method   string
+	URI      string
+	Protocol string
+	Host     string
+
+	// Status code of the response.
+	Status int
+
+	// Bytes contains size of the response body sent to the client.
+	Bytes int64
+
+	// Duration of the request processing.
+	Duration time.Duration
+
+	// Referer and UserAgent request headers.
+	Referer   string
+	UserAgent string
+
+	// RequestID contains id assigned to the request by the http service.
+	RequestID string
+
+	// Pool contains name of the worker pool which has served the request, empty if request has been served
+	// without workers.
+	Pool string
+
+	// Pid of the worker which has served the request.
+	Pid int
+}
+
+// jsonEntry defines json representation of the entry.
+type jsonEntry struct {
+	Time      string  `json:"time"`
+	Remote    string  `json:"remote_addr"`
+	User      string  `json:"user,omitempty"`
+	Method    string  `json:"method"`
+	URI       string  `json:"uri"`
+	Protocol  string  `json:"protocol"`
+	Host      string  `json:"host"`
+	Status    int     `json:"status"`
+	Bytes     int64   `json:"bytes"`
+	Duration  float64 `json:"duration"`
+	Referer   string  `json:"referer,omitempty"`
+	UserAgent string  `json:"user_agent,omitempty"`
+	RequestID string  `json:"request_id,omitempty"`
+	Pool      string  `json:"pool,omitempty"`
+	Pid       int     `json:"pid,omitempty"`
+}
+
+// format renders entry as single line (without line break) using given format or template.
+func (e *Entry) format(format string, tpl *template.Template) ([]byte, error) {
+	switch format {
+	case FormatCommon:
+		return e.common(), nil
+	case FormatCombined:
+		b := e.common()
+		b = append(b, ' ')
+		b = strconv.AppendQuote(b, dash(e.Referer))
+		b = append(b, ' ')
+		return strconv.AppendQuote(b, dash(e.UserAgent)), nil
+	case FormatJSON:
+		j := json.ConfigCompatibleWithStandardLibrary
+		return j.Marshal(&jsonEntry{
+			Time:      e.Time.Format(time.RFC3339),
+			Remote:    e.RemoteAddr,
+			User:      e.User,
+			Method:    e.Method,
+			URI:       e.URI,
+			Protocol:  e.Protocol,
+			Host:      e.Host,
+			Status:    e.Status,
+			Bytes:     e.Bytes,
+			Duration:  e.Duration.Seconds(),
+			Referer:   e.Referer,
+			UserAgent: e.UserAgent,
+			RequestID: e.RequestID,
+			Pool:      e.Pool,
+			Pid:       e.Pid,
+		})
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tpl.Execute(buf, e); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimRight(buf.Bytes(), "\r\n"), nil
+}
+
+// common renders entry using Common Log Format: host ident user [time] "request" status bytes.
+func (e *Entry) common() []byte {
+	b := make([]byte, 0, 128)
+	b = append(b, dash(e.RemoteAddr)...)
+	b = append(b, " - "...)
+	b = append(b, dash(e.User)...)
+	b = append(b, " ["...)
+	b = e.Time.AppendFormat(b, "02/Jan/2006:15:04:05 -0700")
+	b = append(b, "] "...)
+	b = strconv.AppendQuote(b, e.Method+" "+e.URI+" "+e.Protocol)
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, int64(e.Status), 10)
+	b = append(b, ' ')
+
+	if e.Bytes == 0 {
+		return append(b, '-')
+	}
+
+	return strconv.AppendInt(b, e.Bytes, 10)
+}
+
+// dash returns "-" for empty values.
+func dash(v string) string {
+	if v == "" {
+		return "-"
+	}
+
+	return v
+}
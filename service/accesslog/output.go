@@ -0,0 +1,234 @@
+package accesslog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// syslog priority of the entries (facility local7, severity info)
+const syslogPriority = 23<<3 | 6
+
+// output writes log lines.
+type output interface {
+	io.WriteCloser
+
+	// reopen reopens underlying file or connection, used to rotate log files.
+	reopen() error
+}
+
+// newOutput creates output configured by Config.
+func newOutput(cfg *Config) (output, error) {
+	switch {
+	case cfg.Output == "stdout":
+		return &streamOutput{w: os.Stdout}, nil
+	case cfg.Output == "stderr":
+		return &streamOutput{w: os.Stderr}, nil
+	case strings.HasPrefix(cfg.Output, syslogPrefix):
+		o := &syslogOutput{address: strings.TrimPrefix(cfg.Output, syslogPrefix), tag: cfg.Tag}
+		return o, o.reopen()
+	default:
+		o := &fileOutput{path: cfg.Output}
+		return o, o.reopen()
+	}
+}
+
+// sharedOutput is output used by the requests in progress, output is closed once it's released by all of them.
+type sharedOutput struct {
+	output
+
+	mu     sync.Mutex
+	refs   int
+	closed bool
+}
+
+// acquire marks output as used by the request.
+func (o *sharedOutput) acquire() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.refs++
+}
+
+// release releases output used by the request, closes the output if it has been closed while in use.
+func (o *sharedOutput) release() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.refs--
+	if o.closed && o.refs == 0 {
+		return o.output.Close()
+	}
+
+	return nil
+}
+
+// Close closes the output, closing is delayed until output is released by all requests.
+func (o *sharedOutput) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return nil
+	}
+
+	o.closed = true
+	if o.refs == 0 {
+		return o.output.Close()
+	}
+
+	return nil
+}
+
+// streamOutput writes to stdout or stderr.
+type streamOutput struct {
+	w io.Writer
+}
+
+// Write writes log line.
+func (o *streamOutput) Write(b []byte) (int, error) {
+	return o.w.Write(b)
+}
+
+// reopen does nothing.
+func (o *streamOutput) reopen() error {
+	return nil
+}
+
+// Close does nothing, standard streams are never closed.
+func (o *streamOutput) Close() error {
+	return nil
+}
+
+// fileOutput appends lines to the file, file is reopened on demand to support external log rotation.
+type fileOutput struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+}
+
+// Write writes log line.
+func (o *fileOutput) Write(b []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.f.Write(b)
+}
+
+// reopen opens the file by it's path, previously opened file is closed.
+func (o *fileOutput) reopen() error {
+	f, err := os.OpenFile(o.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.f != nil {
+		_ = o.f.Close()
+	}
+	o.f = f
+
+	return nil
+}
+
+// Close closes the file.
+func (o *fileOutput) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.f == nil {
+		return nil
+	}
+
+	err := o.f.Close()
+	o.f = nil
+
+	return err
+}
+
+// syslogOutput sends lines to the local syslog daemon over unix socket.
+type syslogOutput struct {
+	mu      sync.Mutex
+	address string
+	tag     string
+	conn    net.Conn
+}
+
+// Write sends log line as syslog message, connection is restored once if daemon has been restarted.
+func (o *syslogOutput) Write(b []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	msg := fmt.Sprintf(
+		"<%d>%s %s[%d]: %s\n",
+		syslogPriority,
+		time.Now().Format(time.Stamp),
+		o.tag,
+		os.Getpid(),
+		bytes.TrimRight(b, "\n"),
+	)
+
+	if o.conn != nil {
+		if _, err := io.WriteString(o.conn, msg); err == nil {
+			return len(b), nil
+		}
+	}
+
+	if err := o.connect(); err != nil {
+		return 0, err
+	}
+
+	if _, err := io.WriteString(o.conn, msg); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// reopen reconnects to the syslog socket.
+func (o *syslogOutput) reopen() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.connect()
+}
+
+// connect connects to the syslog socket using datagram or stream connection, must be called under lock.
+func (o *syslogOutput) connect() (err error) {
+	if o.conn != nil {
+		_ = o.conn.Close()
+		o.conn = nil
+	}
+
+	for _, network := range []string{"unixgram", "unix"} {
+		var conn net.Conn
+		if conn, err = net.Dial(network, o.address); err == nil {
+			o.conn = conn
+			return nil
+		}
+	}
+
+	return err
+}
+
+// Close closes syslog connection.
+func (o *syslogOutput) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.conn == nil {
+		return nil
+	}
+
+	err := o.conn.Close()
+	o.conn = nil
+
+	return err
+}
@@ -0,0 +1,14 @@
+// +build !windows
+
+package accesslog
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyReopen relays log reopen requests (SIGUSR1) to the given channel.
+func notifyReopen(c chan os.Signal) {
+	signal.Notify(c, syscall.SIGUSR1)
+}
@@ -0,0 +1,8 @@
+// +build windows
+
+package accesslog
+
+import "os"
+
+// notifyReopen does nothing, log files can be reopened using RPC on windows.
+func notifyReopen(c chan os.Signal) {}
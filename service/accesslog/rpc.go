@@ -0,0 +1,13 @@
+package accesslog
+
+type rpcServer struct{ svc *Service }
+
+// Reopen reopens log file or syslog connection, used to rotate log files.
+func (rpc *rpcServer) Reopen(reopen bool, r *string) error {
+	if err := rpc.svc.Reopen(); err != nil {
+		return err
+	}
+
+	*r = "OK"
+	return nil
+}
@@ -0,0 +1,235 @@
+package accesslog
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spiral/roadrunner/service"
+	rrhttp "github.com/spiral/roadrunner/service/http"
+	"github.com/spiral/roadrunner/service/rpc"
+)
+
+// ID contains default service name.
+const ID = "accesslog"
+
+// Service writes access log of the http service.
+type Service struct {
+	mu   sync.Mutex
+	cfg  *Config
+	out  *sharedOutput
+	log  *logrus.Logger
+	http *rrhttp.Service
+
+	stop chan struct{}
+}
+
+// Init must return configure service and return true if service hasStatus enabled. Must return error in case of
+// misconfiguration. Services must not be used without proper configuration pushed first.
+func (s *Service) Init(cfg *Config, h *rrhttp.Service, r *rpc.Service, log *logrus.Logger) (bool, error) {
+	if h == nil {
+		return false, nil
+	}
+
+	out, err := newOutput(cfg)
+	if err != nil {
+		return false, err
+	}
+
+	if r != nil {
+		if err := r.Register(ID, &rpcServer{s}); err != nil {
+			_ = out.Close()
+			return false, err
+		}
+	}
+
+	s.cfg = cfg
+	s.out = &sharedOutput{output: out}
+	s.log = log
+	s.http = h
+
+	h.AddMiddleware(s.middleware)
+
+	return true, nil
+}
+
+// Serve reopens log output on SIGUSR1 until service is stopped.
+func (s *Service) Serve() error {
+	s.mu.Lock()
+	stop := make(chan struct{})
+	s.stop = stop
+	s.mu.Unlock()
+
+	c := make(chan os.Signal, 1)
+	notifyReopen(c)
+	defer signal.Stop(c)
+
+	for {
+		select {
+		case <-c:
+			if err := s.Reopen(); err != nil {
+				s.log.Errorf("[%s]: unable to reopen log: %s", ID, err)
+			}
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+// Stop stops the service and closes log output, requests in progress are logged before output is closed.
+// Requests started afterwards are not logged.
+func (s *Service) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+
+	if s.out != nil {
+		if err := s.out.Close(); err != nil {
+			s.log.Errorf("[%s]: %s", ID, err)
+		}
+		s.out = nil
+	}
+}
+
+// Reconfigure replaces service configuration and log output, requests in progress are logged using previous
+// configuration and previous output is closed once they are done.
+func (s *Service) Reconfigure(cfg service.HydrateConfig) error {
+	c, ok := cfg.(*Config)
+	if !ok {
+		return fmt.Errorf("invalid config type %T", cfg)
+	}
+
+	out, err := newOutput(c)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	prev := s.out
+	s.cfg, s.out = c, &sharedOutput{output: out}
+	s.mu.Unlock()
+
+	if prev == nil {
+		return nil
+	}
+
+	return prev.Close()
+}
+
+// Reopen reopens log file or syslog connection, used to rotate log files.
+func (s *Service) Reopen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.out == nil {
+		return nil
+	}
+
+	return s.out.reopen()
+}
+
+// acquire returns current service configuration and output used by the request, output must be released once
+// request is logged. Output is nil when service is stopped.
+func (s *Service) acquire() (*Config, *sharedOutput) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.out != nil {
+		s.out.acquire()
+	}
+
+	return s.cfg, s.out
+}
+
+// release releases output used by the request.
+func (s *Service) release(out *sharedOutput) {
+	if err := out.release(); err != nil {
+		s.log.Errorf("[%s]: %s", ID, err)
+	}
+}
+
+// middleware logs every request served by the http service.
+func (s *Service) middleware(f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cfg, out := s.acquire()
+		if out == nil {
+			f(w, r)
+			return
+		}
+		defer s.release(out)
+
+		if cfg.Excludes(r.URL.Path) {
+			f(w, r)
+			return
+		}
+
+		start := time.Now()
+		r, info := rrhttp.WithServeInfo(r)
+		rw := rrhttp.WrapWriter(w)
+
+		f(rw.Writer(), r)
+
+		if cfg.Sample < 1 && rw.Status < 500 && rand.Float64() >= cfg.Sample {
+			return
+		}
+
+		s.write(cfg, out, s.entry(r, rw, info, start))
+	}
+}
+
+// entry creates log entry of the served request.
+func (s *Service) entry(r *http.Request, rw *rrhttp.ResponseWriter, info *rrhttp.ServeInfo, start time.Time) *Entry {
+	e := &Entry{
+		Time:       start,
+		RemoteAddr: info.RemoteAddr,
+		Method:     r.Method,
+		URI:        r.RequestURI,
+		Protocol:   r.Proto,
+		Host:       r.Host,
+		Status:     rw.Status,
+		Bytes:      rw.Bytes,
+		Duration:   time.Since(start),
+		Referer:    r.Referer(),
+		UserAgent:  r.UserAgent(),
+		RequestID:  rrhttp.RequestID(r),
+		Pool:       info.Pool,
+		Pid:        info.Pid,
+	}
+
+	if e.RemoteAddr == "" {
+		// request has not reached the workers
+		e.RemoteAddr = s.http.ClientIP(r)
+	}
+
+	if e.URI == "" {
+		e.URI = r.URL.RequestURI()
+	}
+
+	if user, _, ok := r.BasicAuth(); ok {
+		e.User = user
+	}
+
+	return e
+}
+
+// write formats and writes entry to the log output.
+func (s *Service) write(cfg *Config, out output, e *Entry) {
+	line, err := e.format(cfg.Format, cfg.template)
+	if err != nil {
+		s.log.Errorf("[%s]: %s", ID, err)
+		return
+	}
+
+	if _, err := out.Write(append(line, '\n')); err != nil {
+		s.log.Errorf("[%s]: %s", ID, err)
+	}
+}
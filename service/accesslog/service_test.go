@@ -0,0 +1,182 @@
+package accesslog
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	json "github.com/json-iterator/go"
+	"github.com/spiral/roadrunner/rrtest"
+	"github.com/spiral/roadrunner/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func startLogged(t *testing.T, cfg string) *rrtest.HTTP {
+	return rrtest.StartHTTP(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(201)
+		_, _ = w.Write([]byte("hello"))
+	}), ID, &Service{}, rrtest.Config{ID: cfg})
+}
+
+func Test_Service_Disabled(t *testing.T) {
+	h := rrtest.StartHTTP(t, http.NotFoundHandler(), ID, &Service{}, nil)
+
+	_, st := h.Container.Get(ID)
+	assert.Equal(t, service.StatusInactive, st)
+}
+
+func tempLog(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "rr")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	return filepath.Join(dir, "access.log")
+}
+
+func lines(t *testing.T, file string) []string {
+	data, err := ioutil.ReadFile(file)
+	assert.NoError(t, err)
+
+	return strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+}
+
+func Test_Service_Combined(t *testing.T) {
+	file := tempLog(t)
+	h := startLogged(t, `{"output": "`+file+`"}`)
+
+	h.Get(t, "/hello?a=b", map[string]string{"User-Agent": "test-agent", "Referer": "http://referer/"})
+
+	l := lines(t, file)
+	assert.Len(t, l, 1)
+	assert.Regexp(t, `^127\.0\.0\.1 - - \[.+\] "GET /hello\?a=b HTTP/1\.1" 201 5 "http://referer/" "test-agent"$`, l[0])
+}
+
+func Test_Service_JSON(t *testing.T) {
+	file := tempLog(t)
+	h := startLogged(t, `{"output": "`+file+`", "format": "json"}`)
+
+	h.Get(t, "/", map[string]string{"X-Real-Ip": "10.0.0.1"})
+
+	e := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(lines(t, file)[0]), &e))
+
+	assert.Equal(t, "10.0.0.1", e["remote_addr"])
+	assert.Equal(t, float64(201), e["status"])
+	assert.Equal(t, float64(5), e["bytes"])
+	assert.Equal(t, "default", e["pool"])
+	assert.Len(t, e["request_id"], 36)
+	assert.NotZero(t, e["pid"])
+}
+
+func Test_Service_Template(t *testing.T) {
+	file := tempLog(t)
+	h := startLogged(t, `{"output": "`+file+`", "format": "{{.Method}} {{.URI}} {{.Status}} {{.Pool}} {{.RequestID}}"}`)
+
+	h.Get(t, "/path", map[string]string{"X-Request-Id": "incoming"})
+	assert.Equal(t, []string{"GET /path 201 default incoming"}, lines(t, file))
+}
+
+func Test_Service_ExcludeSample(t *testing.T) {
+	file := tempLog(t)
+	h := startLogged(t, `{"output": "`+file+`", "format": "{{.URI}}", "exclude": ["/health"], "sample": 0.0001}`)
+
+	h.Get(t, "/health/check", nil)
+	for i := 0; i < 10; i++ {
+		h.Get(t, "/sampled", nil)
+	}
+
+	_, err := os.Stat(file)
+	assert.NoError(t, err)
+
+	data, _ := ioutil.ReadFile(file)
+	assert.NotContains(t, string(data), "/health")
+	assert.True(t, strings.Count(string(data), "/sampled") < 10)
+}
+
+func Test_Service_Reopen(t *testing.T) {
+	file := tempLog(t)
+	h := startLogged(t, `{"output": "`+file+`", "format": "{{.URI}}"}`)
+
+	h.Get(t, "/first", nil)
+	assert.NoError(t, os.Rename(file, file+".1"))
+
+	svc, _ := h.Container.Get(ID)
+	assert.NoError(t, svc.(*Service).Reopen())
+
+	h.Get(t, "/second", nil)
+
+	assert.Equal(t, []string{"/first"}, lines(t, file+".1"))
+	assert.Equal(t, []string{"/second"}, lines(t, file))
+}
+
+func Test_Service_Syslog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rr")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "log.sock")
+	conn, err := net.ListenPacket("unixgram", socket)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	h := startLogged(t, `{"output": "syslog://`+socket+`", "tag": "web", "format": "{{.Method}} {{.URI}}"}`)
+	h.Get(t, "/syslog", nil)
+
+	buf := make([]byte, 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^<190>.+ web\[[0-9]+\]: GET /syslog\n$`, string(buf[:n]))
+}
+
+func Test_Service_ReconfigureStop(t *testing.T) {
+	file, next := tempLog(t), tempLog(t)
+
+	served := make(chan struct{})
+	release := make(chan struct{})
+
+	svc := &Service{}
+	h := rrtest.StartHTTP(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			close(served)
+			<-release
+		}
+	}), ID, svc, rrtest.Config{ID: `{"output": "` + file + `", "format": "{{.URI}}"}`})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.Get(t, "/slow", nil)
+	}()
+	<-served
+
+	// request in progress is logged to the previous output
+	cfg := &Config{}
+	assert.NoError(t, cfg.Hydrate(rrtest.Config{ID: `{"output": "` + next + `", "format": "{{.URI}}"}`}.Get(ID)))
+	prev := svc.out
+	assert.NoError(t, svc.Reconfigure(cfg))
+	assert.NotNil(t, prev.output.(*fileOutput).f)
+
+	close(release)
+	<-done
+
+	// previous output is closed once request is logged
+	assert.Nil(t, prev.output.(*fileOutput).f)
+
+	h.Get(t, "/next", nil)
+	assert.Equal(t, []string{"/slow"}, lines(t, file))
+	assert.Equal(t, []string{"/next"}, lines(t, next))
+
+	out := svc.out
+	svc.Stop()
+	assert.Nil(t, out.output.(*fileOutput).f)
+
+	// requests served after stop are not logged
+	h.Get(t, "/stopped", nil)
+	assert.Equal(t, []string{"/next"}, lines(t, next))
+}
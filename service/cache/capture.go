@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	rrhttp "github.com/spiral/roadrunner/service/http"
+)
+
+// hop-by-hop headers are meaningful only for a single connection and never stored, see RFC 7230 section 6.1
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// capture collects response passed to the client to be stored in the cache.
+type capture struct {
+	tagHeader string
+	limit     int64
+
+	// headers set by the outer layers prior to the handler, i.e. request id, describe the current request only
+	outer http.Header
+
+	header http.Header
+	tags   []string
+	body   bytes.Buffer
+
+	// body exceeds the limit and can not be stored
+	skip bool
+}
+
+// newCapture captures response written to the given writer, bodies larger than limit are not captured.
+func newCapture(rw *rrhttp.ResponseWriter, tagHeader string, limit int64) *capture {
+	c := &capture{tagHeader: tagHeader, limit: limit, outer: rw.Header().Clone()}
+	rw.OnHeader(func(int) { c.captureHeader(rw.Header()) })
+	rw.OnWrite(c.write)
+
+	return c
+}
+
+// captureHeader captures response headers before they are sent to the client, response tags are not sent.
+// Headers set by the outer layers and hop-by-hop headers are not captured.
+func (c *capture) captureHeader(h http.Header) {
+	c.tags = parseTags(h.Values(c.tagHeader))
+	h.Del(c.tagHeader)
+
+	c.header = h.Clone()
+	for name := range c.outer {
+		c.header.Del(name)
+	}
+
+	for _, name := range c.header.Values("Connection") {
+		for _, token := range strings.Split(name, ",") {
+			c.header.Del(strings.TrimSpace(token))
+		}
+	}
+
+	for _, name := range hopHeaders {
+		c.header.Del(name)
+	}
+
+	h.Set("X-Cache", "MISS")
+}
+
+// write captures chunk of the response body.
+func (c *capture) write(b []byte) {
+	if c.skip {
+		return
+	}
+
+	if int64(c.body.Len()+len(b)) > c.limit {
+		c.skip = true
+		c.body = bytes.Buffer{}
+		return
+	}
+
+	c.body.Write(b)
+}
+
+// parseTags splits values of the tag header by comma and space.
+func parseTags(values []string) (tags []string) {
+	for _, v := range values {
+		tags = append(tags, strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' })...)
+	}
+
+	return tags
+}
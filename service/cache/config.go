@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/spiral/roadrunner/service"
+)
+
+// Config configures http response cache.
+type Config struct {
+	// MaxSize limits size of the memory cache in megabytes, least recently used responses are evicted (or moved
+	// to the disk when enabled). Defaults to 64.
+	MaxSize int64
+
+	// MaxEntrySize limits size of the cached response body in kilobytes, larger responses are never cached.
+	// Defaults to 1024.
+	MaxEntrySize int64
+
+	// Dir enables disk cache, responses evicted from the memory are stored in the given directory.
+	Dir string
+
+	// DiskSize limits size of the disk cache in megabytes, defaults to 1024.
+	DiskSize int64
+
+	// TagHeader is the response header listing tags of the response (separated by comma or space), tags are
+	// used to purge related responses and are not sent to the client. Defaults to X-Cache-Tags.
+	TagHeader string
+
+	// LockTimeout limits time concurrent requests of the same resource wait for the first request to fill the
+	// cache, requests are passed to the workers once timeout expires. Defaults to 10 seconds.
+	LockTimeout time.Duration
+}
+
+// Hydrate must populate Config values using given Config source. Must return error if Config is not valid.
+func (c *Config) Hydrate(cfg service.Config) error {
+	c.initDefaults()
+	if err := cfg.Unmarshal(c); err != nil {
+		return err
+	}
+
+	// timeout is defined in seconds
+	if c.LockTimeout < time.Microsecond {
+		c.LockTimeout = time.Second * time.Duration(c.LockTimeout.Nanoseconds())
+	}
+
+	return c.Valid()
+}
+
+// initDefaults sets missing values to their default values. Defaults are not exposed to the container to keep the
+// cache disabled unless it's configured explicitly.
+func (c *Config) initDefaults() {
+	c.MaxSize = 64
+	c.MaxEntrySize = 1024
+	c.DiskSize = 1024
+	c.TagHeader = "X-Cache-Tags"
+	c.LockTimeout = 10 * time.Second
+}
+
+// Valid validates the configuration.
+func (c *Config) Valid() error {
+	if c.MaxSize <= 0 {
+		return errors.New("cache size must be positive")
+	}
+
+	if c.MaxEntrySize <= 0 || c.MaxEntrySize*1024 > c.MaxSize*1024*1024 {
+		return errors.New("max entry size must be positive and must not exceed cache size")
+	}
+
+	if c.TagHeader == "" {
+		return errors.New("tag header is missing")
+	}
+
+	if c.LockTimeout < 0 {
+		return errors.New("lock timeout must not be negative")
+	}
+
+	if c.Dir != "" {
+		if c.DiskSize <= 0 {
+			return errors.New("disk cache size must be positive")
+		}
+
+		if s, err := os.Stat(c.Dir); err != nil {
+			return err
+		} else if !s.IsDir() {
+			return errors.New("cache dir is not a directory")
+		}
+	}
+
+	return nil
+}
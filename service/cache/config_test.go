@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spiral/roadrunner/rrtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Config_Hydrate(t *testing.T) {
+	cfg := &Config{}
+	assert.NoError(t, cfg.Hydrate(rrtest.Config{ID: `{"lockTimeout": 5}`}.Get(ID)))
+
+	assert.Equal(t, int64(64), cfg.MaxSize)
+	assert.Equal(t, int64(1024), cfg.MaxEntrySize)
+	assert.Equal(t, "X-Cache-Tags", cfg.TagHeader)
+	assert.Equal(t, 5*time.Second, cfg.LockTimeout)
+	assert.Equal(t, "", cfg.Dir)
+}
+
+func Test_Config_Hydrate_Error(t *testing.T) {
+	for _, c := range []string{
+		`{"maxSize": 0}`,
+		`{"maxSize": 1, "maxEntrySize": 2048}`,
+		`{"tagHeader": ""}`,
+		`{"dir": "/missing/dir"}`,
+		`{"dir": "config.go"}`,
+	} {
+		assert.Error(t, (&Config{}).Hydrate(rrtest.Config{ID: c}.Get(ID)), c)
+	}
+}
@@ -0,0 +1,240 @@
+package cache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// statuses cacheable by default, see RFC 7231 section 6.1
+var cacheableStatus = map[int]bool{
+	http.StatusOK:                   true,
+	http.StatusNonAuthoritativeInfo: true,
+	http.StatusNoContent:            true,
+	http.StatusMultipleChoices:      true,
+	http.StatusMovedPermanently:     true,
+	http.StatusNotFound:             true,
+	http.StatusMethodNotAllowed:     true,
+	http.StatusGone:                 true,
+	http.StatusRequestURITooLong:    true,
+	http.StatusNotImplemented:       true,
+}
+
+// headers sent with 304 response, see RFC 7232 section 4.1
+var notModifiedHeaders = []string{"Cache-Control", "Content-Location", "Date", "ETag", "Expires", "Vary"}
+
+// entry is cached response.
+type entry struct {
+	// Key identifies requested resource (host and request uri), all variants of the resource share the key.
+	Key string
+
+	// Variant identifies the response among variants of the resource selected by the Vary header.
+	Variant string
+
+	// Status, Header and Body of the response.
+	Status int
+	Header http.Header
+	Body   []byte
+
+	// Tags of the response.
+	Tags []string
+
+	// Stored is the moment response has been received, Age is the age of the response at this moment.
+	Stored time.Time
+	Age    time.Duration
+
+	// Expires is the moment response becomes stale.
+	Expires time.Time
+
+	// size of the entry in bytes
+	size int64
+
+	// headers of the request which has produced the response, used to select the variant
+	requestHeader http.Header
+}
+
+// cacheControl contains directives of the Cache-Control header.
+type cacheControl map[string]string
+
+// parseCacheControl parses Cache-Control directives, directive names are case insensitive.
+func parseCacheControl(header http.Header) cacheControl {
+	cc := cacheControl{}
+	for _, value := range header.Values("Cache-Control") {
+		for _, d := range strings.Split(value, ",") {
+			d = strings.TrimSpace(d)
+			if d == "" {
+				continue
+			}
+
+			name, arg := d, ""
+			if i := strings.IndexByte(d, '='); i != -1 {
+				name, arg = d[:i], strings.Trim(strings.TrimSpace(d[i+1:]), `"`)
+			}
+
+			cc[strings.ToLower(strings.TrimSpace(name))] = arg
+		}
+	}
+
+	return cc
+}
+
+// has returns true if directive is present.
+func (cc cacheControl) has(name string) bool {
+	_, ok := cc[name]
+	return ok
+}
+
+// seconds returns value of the delta-seconds directive, false if directive is missing or malformed.
+func (cc cacheControl) seconds(name string) (time.Duration, bool) {
+	v, ok := cc[name]
+	if !ok {
+		return 0, false
+	}
+
+	s, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || s < 0 {
+		return 0, false
+	}
+
+	return time.Duration(s) * time.Second, true
+}
+
+// newEntry creates cache entry of the response, returns nil if response must not be stored by the shared cache.
+func newEntry(r *http.Request, status int, header http.Header, body []byte, tags []string) *entry {
+	if !cacheableStatus[status] || header.Get("Set-Cookie") != "" || header.Get("Vary") == "*" {
+		return nil
+	}
+
+	cc := parseCacheControl(header)
+	if cc.has("no-store") || cc.has("private") || cc.has("no-cache") {
+		return nil
+	}
+
+	// responses to authorized requests are private unless explicitly allowed, see RFC 7234 section 3.2
+	if r.Header.Get("Authorization") != "" && !cc.has("public") && !cc.has("s-maxage") && !cc.has("must-revalidate") {
+		return nil
+	}
+
+	if body == nil {
+		body = []byte{}
+	}
+
+	now := time.Now()
+	e := &entry{Status: status, Header: header, Body: body, Tags: tags, Stored: now, requestHeader: r.Header}
+
+	if age, err := strconv.ParseInt(header.Get("Age"), 10, 64); err == nil && age > 0 {
+		e.Age = time.Duration(age) * time.Second
+	}
+
+	lifetime, ok := freshness(cc, header, now)
+	if !ok || lifetime <= e.Age {
+		return nil
+	}
+	e.Expires = now.Add(lifetime - e.Age)
+
+	return e
+}
+
+// freshness returns freshness lifetime of the response, false if response has no explicit lifetime.
+func freshness(cc cacheControl, header http.Header, now time.Time) (time.Duration, bool) {
+	if lifetime, ok := cc.seconds("s-maxage"); ok {
+		return lifetime, true
+	}
+
+	if lifetime, ok := cc.seconds("max-age"); ok {
+		return lifetime, true
+	}
+
+	if header.Get("Expires") == "" {
+		return 0, false
+	}
+
+	expires, err := http.ParseTime(header.Get("Expires"))
+	if err != nil {
+		// invalid date means already expired
+		return 0, true
+	}
+
+	date := now
+	if d, err := http.ParseTime(header.Get("Date")); err == nil {
+		date = d
+	}
+
+	return expires.Sub(date), true
+}
+
+// age returns current age of the entry.
+func (e *entry) age(now time.Time) time.Duration {
+	return e.Age + now.Sub(e.Stored)
+}
+
+// fresh returns true if entry can be served to the request with given Cache-Control directives.
+func (e *entry) fresh(cc cacheControl, now time.Time) bool {
+	if !now.Before(e.Expires) {
+		return false
+	}
+
+	if maxAge, ok := cc.seconds("max-age"); ok && e.age(now) > maxAge {
+		return false
+	}
+
+	if minFresh, ok := cc.seconds("min-fresh"); ok && e.Expires.Sub(now) < minFresh {
+		return false
+	}
+
+	return true
+}
+
+// notModified returns true if client has a valid copy of the entry, If-None-Match takes precedence over
+// If-Modified-Since, see RFC 7232 section 6.
+func (e *entry) notModified(r *http.Request) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		etag := e.Header.Get("ETag")
+		if etag == "" {
+			return false
+		}
+
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || weakTag(tag) == weakTag(etag) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	ims, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	lm, err := http.ParseTime(e.Header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+
+	return !lm.After(ims)
+}
+
+// weakTag removes weak indicator of the entity tag, If-None-Match uses weak comparison.
+func weakTag(tag string) string {
+	return strings.TrimPrefix(tag, "W/")
+}
+
+// entrySize estimates memory used by the entry.
+func entrySize(e *entry) int64 {
+	size := int64(len(e.Key) + len(e.Variant) + len(e.Body))
+	for k, values := range e.Header {
+		for _, v := range values {
+			size += int64(len(k) + len(v))
+		}
+	}
+
+	for _, tag := range e.Tags {
+		size += int64(len(tag))
+	}
+
+	return size
+}
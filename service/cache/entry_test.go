@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewEntry_Freshness(t *testing.T) {
+	r, _ := http.NewRequest("GET", "http://host/", nil)
+	now := time.Now()
+
+	cases := []struct {
+		header   http.Header
+		lifetime time.Duration
+	}{
+		{http.Header{"Cache-Control": {"max-age=60"}}, time.Minute},
+		{http.Header{"Cache-Control": {"max-age=60, s-maxage=10"}}, 10 * time.Second},
+		{http.Header{"Cache-Control": {"max-age=60"}, "Age": {"20"}}, 40 * time.Second},
+		{http.Header{
+			"Date":    {now.UTC().Format(http.TimeFormat)},
+			"Expires": {now.Add(time.Hour).UTC().Format(http.TimeFormat)},
+		}, time.Hour},
+	}
+
+	for _, c := range cases {
+		e := newEntry(r, http.StatusOK, c.header, nil, nil)
+		if assert.NotNil(t, e, c.header) {
+			assert.InDelta(t, float64(c.lifetime), float64(e.Expires.Sub(e.Stored)), float64(time.Second), c.header)
+		}
+	}
+}
+
+func Test_NewEntry_NotCacheable(t *testing.T) {
+	r, _ := http.NewRequest("GET", "http://host/", nil)
+
+	for _, header := range []http.Header{
+		{},
+		{"Cache-Control": {"no-store, max-age=60"}},
+		{"Cache-Control": {"private, max-age=60"}},
+		{"Cache-Control": {"max-age=60"}, "Set-Cookie": {"a=b"}},
+		{"Cache-Control": {"max-age=60"}, "Vary": {"*"}},
+		{"Cache-Control": {"max-age=60"}, "Age": {"60"}},
+		{"Expires": {"0"}},
+	} {
+		assert.Nil(t, newEntry(r, http.StatusOK, header, nil, nil), header)
+	}
+
+	assert.Nil(t, newEntry(r, http.StatusInternalServerError, http.Header{"Cache-Control": {"max-age=60"}}, nil, nil))
+
+	r.Header.Set("Authorization", "Basic")
+	assert.Nil(t, newEntry(r, http.StatusOK, http.Header{"Cache-Control": {"max-age=60"}}, nil, nil))
+	assert.NotNil(t, newEntry(r, http.StatusOK, http.Header{"Cache-Control": {"public, max-age=60"}}, nil, nil))
+}
+
+func Test_Entry_Fresh(t *testing.T) {
+	now := time.Now()
+	e := &entry{Stored: now.Add(-30 * time.Second), Expires: now.Add(30 * time.Second)}
+
+	assert.True(t, e.fresh(cacheControl{}, now))
+	assert.True(t, e.fresh(cacheControl{"max-age": "40"}, now))
+	assert.False(t, e.fresh(cacheControl{"max-age": "20"}, now))
+	assert.False(t, e.fresh(cacheControl{"min-fresh": "40"}, now))
+	assert.False(t, e.fresh(cacheControl{}, now.Add(time.Minute)))
+}
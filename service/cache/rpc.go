@@ -0,0 +1,22 @@
+package cache
+
+type rpcServer struct{ svc *Service }
+
+// Purge removes all variants of the resource with given key (host and request uri), returns number of removed
+// responses.
+func (rpc *rpcServer) Purge(key string, n *int) error {
+	*n = rpc.svc.Purge(key)
+	return nil
+}
+
+// PurgePrefix removes responses of the resources which keys start with the given prefix.
+func (rpc *rpcServer) PurgePrefix(prefix string, n *int) error {
+	*n = rpc.svc.PurgePrefix(prefix)
+	return nil
+}
+
+// PurgeTag removes responses tagged with the given tag.
+func (rpc *rpcServer) PurgeTag(tag string, n *int) error {
+	*n = rpc.svc.PurgeTag(tag)
+	return nil
+}
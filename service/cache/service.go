@@ -0,0 +1,243 @@
+package cache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	rrhttp "github.com/spiral/roadrunner/service/http"
+	"github.com/spiral/roadrunner/service/rpc"
+)
+
+// ID contains default service name.
+const ID = "cache"
+
+// Service caches responses of the http service following RFC 7234 shared cache semantics. Only GET and HEAD
+// requests are served from the cache, responses are stored when they declare explicit freshness lifetime.
+type Service struct {
+	cfg   *Config
+	log   *logrus.Logger
+	store *store
+
+	// requests filling the cache by variant, concurrent requests of the same variant wait for them
+	mu      sync.Mutex
+	flights map[string]chan struct{}
+}
+
+// Init must return configure service and return true if service hasStatus enabled. Must return error in case of
+// misconfiguration. Services must not be used without proper configuration pushed first.
+func (s *Service) Init(cfg *Config, h *rrhttp.Service, r *rpc.Service, log *logrus.Logger) (bool, error) {
+	if h == nil {
+		return false, nil
+	}
+
+	st, err := newStore(cfg, func(err error) {
+		log.Errorf("[%s]: %s", ID, err)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if r != nil {
+		if err := r.Register(ID, &rpcServer{s}); err != nil {
+			return false, err
+		}
+	}
+
+	s.cfg = cfg
+	s.log = log
+	s.store = st
+	s.flights = make(map[string]chan struct{})
+
+	h.AddMiddleware(s.middleware)
+
+	return true, nil
+}
+
+// Purge removes all variants of the resource with given key (host and request uri, i.e. example.com/path?a=b),
+// returns number of removed responses.
+func (s *Service) Purge(key string) int {
+	return s.store.purge(func(e *entry) bool { return e.Key == key })
+}
+
+// PurgePrefix removes responses of the resources which keys start with the given prefix.
+func (s *Service) PurgePrefix(prefix string) int {
+	return s.store.purge(func(e *entry) bool { return strings.HasPrefix(e.Key, prefix) })
+}
+
+// PurgeTag removes responses tagged with the given tag.
+func (s *Service) PurgeTag(tag string) int {
+	return s.store.purge(func(e *entry) bool {
+		for _, t := range e.Tags {
+			if t == tag {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// middleware serves requests from the cache and stores cacheable responses.
+func (s *Service) middleware(f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			f(w, r)
+			return
+		}
+
+		cc := parseCacheControl(r.Header)
+		if cc.has("no-store") {
+			f(w, r)
+			return
+		}
+
+		key := keyOf(r)
+
+		// no-cache requires the response to be obtained from the workers, response is still stored
+		if !cc.has("no-cache") && r.Header.Get("Pragma") != "no-cache" {
+			if s.serve(w, r, key, cc) {
+				return
+			}
+
+			if cc.has("only-if-cached") {
+				w.WriteHeader(http.StatusGatewayTimeout)
+				return
+			}
+		}
+
+		// HEAD responses have no body to be stored
+		if r.Method == http.MethodHead {
+			f(w, r)
+			return
+		}
+
+		variant := s.store.variant(key, r)
+		done, leader := s.acquire(variant)
+		if !leader {
+			if s.wait(done) && s.serve(w, r, key, cc) {
+				return
+			}
+
+			s.fetch(f, w, r, key)
+			return
+		}
+
+		defer s.release(variant, done)
+		s.fetch(f, w, r, key)
+	}
+}
+
+// serve responds with the fresh cached response, returns false if request can not be served from the cache.
+func (s *Service) serve(w http.ResponseWriter, r *http.Request, key string, cc cacheControl) bool {
+	e := s.store.get(key, r)
+	now := time.Now()
+	if e == nil || !e.fresh(cc, now) {
+		return false
+	}
+
+	h := w.Header()
+	age := strconv.FormatInt(int64(e.age(now)/time.Second), 10)
+
+	if e.notModified(r) {
+		for _, name := range notModifiedHeaders {
+			if values, ok := e.Header[http.CanonicalHeaderKey(name)]; ok {
+				h[name] = append([]string(nil), values...)
+			}
+		}
+
+		h.Set("Age", age)
+		h.Set("X-Cache", "HIT")
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+
+	for name, values := range e.Header {
+		// headers of the current request set by the outer layers, i.e. request id, are preserved
+		if _, ok := h[name]; ok {
+			continue
+		}
+
+		h[name] = append([]string(nil), values...)
+	}
+
+	h.Set("Age", age)
+	h.Set("X-Cache", "HIT")
+	w.WriteHeader(e.Status)
+
+	if r.Method != http.MethodHead {
+		if _, err := w.Write(e.Body); err != nil {
+			s.log.Debugf("[%s]: %s", ID, err)
+		}
+	}
+
+	return true
+}
+
+// fetch passes request to the workers and stores the response if it's cacheable.
+func (s *Service) fetch(f http.HandlerFunc, w http.ResponseWriter, r *http.Request, key string) {
+	rw := rrhttp.WrapWriter(w)
+	c := newCapture(rw, s.cfg.TagHeader, s.cfg.MaxEntrySize*1024)
+	f(rw.Writer(), r)
+
+	// responses of hijacked connections are never stored
+	if !rw.Wrote || rw.Hijacked || c.skip {
+		return
+	}
+
+	if e := newEntry(r, rw.Status, c.header, c.body.Bytes(), c.tags); e != nil {
+		e.Key = key
+		s.store.add(e)
+	}
+}
+
+// acquire returns channel closed once the first request of the variant fills the cache, leader is true if
+// current request is the first one.
+func (s *Service) acquire(variant string) (done chan struct{}, leader bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if done, ok := s.flights[variant]; ok {
+		return done, false
+	}
+
+	done = make(chan struct{})
+	s.flights[variant] = done
+
+	return done, true
+}
+
+// release notifies requests waiting for the variant.
+func (s *Service) release(variant string, done chan struct{}) {
+	s.mu.Lock()
+	delete(s.flights, variant)
+	s.mu.Unlock()
+
+	close(done)
+}
+
+// wait waits for the first request to fill the cache, returns false on lock timeout.
+func (s *Service) wait(done chan struct{}) bool {
+	if s.cfg.LockTimeout == 0 {
+		<-done
+		return true
+	}
+
+	timer := time.NewTimer(s.cfg.LockTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// keyOf returns cache key of the requested resource.
+func keyOf(r *http.Request) string {
+	return r.Host + r.URL.RequestURI()
+}
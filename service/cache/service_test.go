@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spiral/roadrunner/rrtest"
+	"github.com/spiral/roadrunner/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// startCached starts http service with the cache, worker responds with given headers and counts requests.
+func startCached(t *testing.T, cfg string, header map[string]string, delay time.Duration) (*rrtest.HTTP, *Service, *int64) {
+	served := new(int64)
+	svc := &Service{}
+
+	h := rrtest.StartHTTP(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt64(served, 1)
+		time.Sleep(delay)
+
+		for k, v := range header {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("X-Served", string(rune('0'+n)))
+		_, _ = w.Write([]byte("lang:" + r.Header.Get("Accept-Language")))
+	}), ID, svc, rrtest.Config{ID: cfg})
+
+	return h, svc, served
+}
+
+func Test_Service_Disabled(t *testing.T) {
+	h := rrtest.StartHTTP(t, http.NotFoundHandler(), ID, &Service{}, nil)
+
+	_, st := h.Container.Get(ID)
+	assert.Equal(t, service.StatusInactive, st)
+}
+
+func Test_Service_Hit(t *testing.T) {
+	h, _, served := startCached(t, `{}`, map[string]string{"Cache-Control": "public, max-age=60"}, 0)
+
+	rsp, body := h.Get(t, "/page", nil)
+	assert.Equal(t, "MISS", rsp.Header.Get("X-Cache"))
+	assert.Equal(t, "lang:", body)
+
+	rsp, body = h.Get(t, "/page", nil)
+	assert.Equal(t, "HIT", rsp.Header.Get("X-Cache"))
+	assert.Equal(t, "0", rsp.Header.Get("Age"))
+	assert.Equal(t, "1", rsp.Header.Get("X-Served"))
+	assert.Equal(t, "lang:", body)
+
+	// query is part of the key
+	rsp, _ = h.Get(t, "/page?a=b", nil)
+	assert.Equal(t, "MISS", rsp.Header.Get("X-Cache"))
+
+	// no-cache request bypasses the cache
+	rsp, _ = h.Get(t, "/page", map[string]string{"Cache-Control": "no-cache"})
+	assert.Equal(t, "MISS", rsp.Header.Get("X-Cache"))
+
+	assert.Equal(t, int64(3), atomic.LoadInt64(served))
+}
+
+func Test_Service_RequestHeaders(t *testing.T) {
+	h, _, _ := startCached(t, `{}`, map[string]string{"Cache-Control": "max-age=60", "Connection": "X-Hop", "X-Hop": "hop"}, 0)
+
+	rsp, _ := h.Get(t, "/", map[string]string{"X-Request-Id": "first"})
+	assert.Equal(t, "first", rsp.Header.Get("X-Request-Id"))
+	assert.Equal(t, "hop", rsp.Header.Get("X-Hop"))
+
+	rsp, _ = h.Get(t, "/", map[string]string{"X-Request-Id": "second"})
+	assert.Equal(t, "HIT", rsp.Header.Get("X-Cache"))
+	assert.Equal(t, "second", rsp.Header.Get("X-Request-Id"))
+	assert.Equal(t, "", rsp.Header.Get("X-Hop"))
+
+	rsp, _ = h.Get(t, "/", nil)
+	assert.Equal(t, "HIT", rsp.Header.Get("X-Cache"))
+	assert.NotEqual(t, "first", rsp.Header.Get("X-Request-Id"))
+	assert.Len(t, rsp.Header.Get("X-Request-Id"), 36)
+}
+
+func Test_Service_NotCacheable(t *testing.T) {
+	for _, cc := range []string{"", "no-store", "private, max-age=60", "no-cache"} {
+		h, _, served := startCached(t, `{}`, map[string]string{"Cache-Control": cc}, 0)
+
+		h.Get(t, "/", nil)
+		rsp, _ := h.Get(t, "/", nil)
+
+		assert.Equal(t, "MISS", rsp.Header.Get("X-Cache"), cc)
+		assert.Equal(t, int64(2), atomic.LoadInt64(served), cc)
+	}
+}
+
+func Test_Service_Vary(t *testing.T) {
+	h, _, served := startCached(t, `{}`, map[string]string{"Cache-Control": "max-age=60", "Vary": "Accept-Language"}, 0)
+
+	_, body := h.Get(t, "/", map[string]string{"Accept-Language": "en"})
+	assert.Equal(t, "lang:en", body)
+
+	_, body = h.Get(t, "/", map[string]string{"Accept-Language": "de"})
+	assert.Equal(t, "lang:de", body)
+
+	rsp, body := h.Get(t, "/", map[string]string{"Accept-Language": "en"})
+	assert.Equal(t, "HIT", rsp.Header.Get("X-Cache"))
+	assert.Equal(t, "lang:en", body)
+
+	assert.Equal(t, int64(2), atomic.LoadInt64(served))
+}
+
+func Test_Service_NotModified(t *testing.T) {
+	h, _, _ := startCached(t, `{}`, map[string]string{
+		"Cache-Control": "max-age=60",
+		"ETag":          `"v1"`,
+		"Last-Modified": "Mon, 02 Jan 2006 15:04:05 GMT",
+	}, 0)
+
+	h.Get(t, "/", nil)
+
+	rsp, body := h.Get(t, "/", map[string]string{"If-None-Match": `"v0", W/"v1"`})
+	assert.Equal(t, http.StatusNotModified, rsp.StatusCode)
+	assert.Equal(t, `"v1"`, rsp.Header.Get("ETag"))
+	assert.Equal(t, "", body)
+
+	rsp, _ = h.Get(t, "/", map[string]string{"If-None-Match": `"v2"`})
+	assert.Equal(t, http.StatusOK, rsp.StatusCode)
+
+	rsp, _ = h.Get(t, "/", map[string]string{"If-Modified-Since": "Tue, 03 Jan 2006 15:04:05 GMT"})
+	assert.Equal(t, http.StatusNotModified, rsp.StatusCode)
+
+	rsp, _ = h.Get(t, "/", map[string]string{"If-Modified-Since": "Sun, 01 Jan 2006 15:04:05 GMT"})
+	assert.Equal(t, http.StatusOK, rsp.StatusCode)
+}
+
+func Test_Service_Stampede(t *testing.T) {
+	h, _, served := startCached(t, `{}`, map[string]string{"Cache-Control": "max-age=60"}, 100*time.Millisecond)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, body := h.Get(t, "/", nil)
+			assert.Equal(t, "lang:", body)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(1), atomic.LoadInt64(served))
+}
+
+func Test_Service_Purge(t *testing.T) {
+	h, svc, _ := startCached(t, `{}`, map[string]string{"Cache-Control": "max-age=60", "X-Cache-Tags": "page, user:1"}, 0)
+
+	rsp, _ := h.Get(t, "/a", nil)
+	assert.Equal(t, "", rsp.Header.Get("X-Cache-Tags"))
+	h.Get(t, "/a/b", nil)
+	h.Get(t, "/c", nil)
+
+	host := h.Address
+	assert.Equal(t, 1, svc.Purge(host+"/a"))
+	assert.Equal(t, 0, svc.Purge(host+"/a"))
+	assert.Equal(t, 1, svc.PurgePrefix(host+"/a"))
+	assert.Equal(t, 1, svc.PurgeTag("user:1"))
+	assert.Equal(t, 0, svc.PurgeTag("page"))
+
+	rsp, _ = h.Get(t, "/c", nil)
+	assert.Equal(t, "MISS", rsp.Header.Get("X-Cache"))
+}
+
+func Test_Service_MaxEntrySize(t *testing.T) {
+	h, _, served := startCached(t, `{"maxEntrySize": 1}`, map[string]string{"Cache-Control": "max-age=60"}, 0)
+
+	h.Get(t, "/", map[string]string{"Accept-Language": strings.Repeat("a", 2048)})
+	h.Get(t, "/", nil)
+
+	assert.Equal(t, int64(2), atomic.LoadInt64(served))
+}
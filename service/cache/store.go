@@ -0,0 +1,373 @@
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/json-iterator/go"
+)
+
+// extension of the disk cache files
+const fileExt = ".cache"
+
+// lru keeps entries ordered by usage, least recently used entries are evicted once size exceeds the limit.
+type lru struct {
+	max   int64
+	size  int64
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+// newLRU creates lru limited by the given size in bytes.
+func newLRU(max int64) *lru {
+	return &lru{max: max, ll: list.New(), items: make(map[string]*list.Element)}
+}
+
+// get returns entry by variant and marks it as recently used.
+func (l *lru) get(variant string) *entry {
+	el, ok := l.items[variant]
+	if !ok {
+		return nil
+	}
+
+	l.ll.MoveToFront(el)
+	return el.Value.(*entry)
+}
+
+// add puts entry to the lru, returns entries evicted to fit the size limit.
+func (l *lru) add(e *entry) (evicted []*entry) {
+	l.items[e.Variant] = l.ll.PushFront(e)
+	l.size += e.size
+
+	for l.size > l.max {
+		evicted = append(evicted, l.remove(l.ll.Back().Value.(*entry).Variant))
+	}
+
+	return evicted
+}
+
+// remove removes entry by variant, returns nil if entry is missing.
+func (l *lru) remove(variant string) *entry {
+	el, ok := l.items[variant]
+	if !ok {
+		return nil
+	}
+
+	l.ll.Remove(el)
+	delete(l.items, variant)
+
+	e := el.Value.(*entry)
+	l.size -= e.size
+
+	return e
+}
+
+// filter returns entries matching given function.
+func (l *lru) filter(match func(e *entry) bool) (entries []*entry) {
+	for el := l.ll.Front(); el != nil; el = el.Next() {
+		if e := el.Value.(*entry); match(e) {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+// resource contains variants of the resource.
+type resource struct {
+	// vary contains canonical names of the headers selecting the variant
+	vary []string
+
+	// number of stored variants
+	variants int
+}
+
+// store keeps responses in memory, entries evicted from the memory are moved to the disk when disk cache is
+// enabled.
+type store struct {
+	mu        sync.Mutex
+	mem       *lru
+	disk      *lru
+	dir       string
+	resources map[string]*resource
+	onError   func(err error)
+}
+
+// newStore creates store using given configuration, disk cache files left by the previous run are removed.
+func newStore(cfg *Config, onError func(err error)) (*store, error) {
+	s := &store{
+		mem:       newLRU(cfg.MaxSize * 1024 * 1024),
+		resources: make(map[string]*resource),
+		onError:   onError,
+	}
+
+	if cfg.Dir == "" {
+		return s, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(cfg.Dir, "*"+fileExt))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		if err := os.Remove(f); err != nil {
+			return nil, err
+		}
+	}
+
+	s.dir = cfg.Dir
+	s.disk = newLRU(cfg.DiskSize * 1024 * 1024)
+
+	return s, nil
+}
+
+// variant returns variant of the resource selected by the request headers.
+func (s *store) variant(key string, r *http.Request) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if res, ok := s.resources[key]; ok {
+		return variantOf(key, res.vary, r.Header)
+	}
+
+	return key
+}
+
+// get returns fresh entry of the resource variant selected by the request, entries are promoted from the disk to
+// the memory on access. Expired entries are removed.
+func (s *store) get(key string, r *http.Request) *entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, ok := s.resources[key]
+	if !ok {
+		return nil
+	}
+
+	variant := variantOf(key, res.vary, r.Header)
+
+	e := s.mem.get(variant)
+	if e == nil && s.disk != nil {
+		e = s.load(variant)
+	}
+
+	if e == nil {
+		return nil
+	}
+
+	if !time.Now().Before(e.Expires) {
+		s.mem.remove(variant)
+		s.drop(e)
+		return nil
+	}
+
+	return e
+}
+
+// add stores the entry, previous entry of the same variant is replaced.
+func (s *store) add(e *entry) {
+	vary := varyOf(e.Header)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res, ok := s.resources[e.Key]
+	if ok && !equal(res.vary, vary) {
+		// variants selected by the previous headers are unreachable
+		s.removeWhere(func(v *entry) bool { return v.Key == e.Key })
+		res, ok = nil, false
+	}
+
+	if !ok {
+		res = &resource{vary: vary}
+		s.resources[e.Key] = res
+	}
+
+	e.Variant = variantOf(e.Key, vary, e.requestHeader)
+	e.requestHeader = nil
+	e.size = entrySize(e)
+
+	if prev := s.mem.remove(e.Variant); prev != nil {
+		s.drop(prev)
+	} else if s.disk != nil {
+		if prev := s.disk.remove(e.Variant); prev != nil {
+			s.drop(prev)
+		}
+	}
+
+	res.variants++
+	for _, evicted := range s.mem.add(e) {
+		s.spill(evicted)
+	}
+}
+
+// purge removes entries matching given function, returns number of removed entries.
+func (s *store) purge(match func(e *entry) bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.removeWhere(match)
+}
+
+// removeWhere removes entries matching given function from the memory and disk.
+func (s *store) removeWhere(match func(e *entry) bool) int {
+	entries := s.mem.filter(match)
+	for _, e := range entries {
+		s.mem.remove(e.Variant)
+		s.drop(e)
+	}
+
+	if s.disk != nil {
+		onDisk := s.disk.filter(match)
+		for _, e := range onDisk {
+			s.disk.remove(e.Variant)
+			s.drop(e)
+		}
+
+		entries = append(entries, onDisk...)
+	}
+
+	return len(entries)
+}
+
+// drop removes file of the entry and forgets the resource once all its variants are removed.
+func (s *store) drop(e *entry) {
+	if e.Body == nil && s.dir != "" {
+		if err := os.Remove(s.file(e.Variant)); err != nil && !os.IsNotExist(err) {
+			s.onError(err)
+		}
+	}
+
+	if res, ok := s.resources[e.Key]; ok {
+		res.variants--
+		if res.variants <= 0 {
+			delete(s.resources, e.Key)
+		}
+	}
+}
+
+// spill moves entry evicted from the memory to the disk, entry is dropped when disk cache is disabled.
+func (s *store) spill(e *entry) {
+	if s.disk == nil || e.size > s.disk.max {
+		s.drop(e)
+		return
+	}
+
+	data, err := json.ConfigCompatibleWithStandardLibrary.Marshal(e)
+	if err == nil {
+		err = ioutil.WriteFile(s.file(e.Variant), data, 0644)
+	}
+
+	if err != nil {
+		s.onError(err)
+		s.drop(e)
+		return
+	}
+
+	// disk lru keeps entries without bodies
+	meta := *e
+	meta.Body = nil
+
+	for _, evicted := range s.disk.add(&meta) {
+		s.drop(evicted)
+	}
+}
+
+// load moves entry from the disk to the memory.
+func (s *store) load(variant string) *entry {
+	meta := s.disk.remove(variant)
+	if meta == nil {
+		return nil
+	}
+
+	file := s.file(variant)
+	data, err := ioutil.ReadFile(file)
+	if err == nil {
+		err = os.Remove(file)
+	}
+
+	e := &entry{}
+	if err == nil {
+		err = json.ConfigCompatibleWithStandardLibrary.Unmarshal(data, e)
+	}
+
+	if err != nil {
+		s.onError(err)
+		s.drop(meta)
+		return nil
+	}
+
+	if e.Body == nil {
+		e.Body = []byte{}
+	}
+	e.size = meta.size
+
+	for _, evicted := range s.mem.add(e) {
+		s.spill(evicted)
+	}
+
+	return e
+}
+
+// file returns path of the disk cache file of the variant.
+func (s *store) file(variant string) string {
+	sum := sha256.Sum256([]byte(variant))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+fileExt)
+}
+
+// varyOf returns sorted canonical names of the headers listed in the Vary header.
+func varyOf(header http.Header) (vary []string) {
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				vary = append(vary, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	sort.Strings(vary)
+	return vary
+}
+
+// variantOf returns variant of the resource selected by the values of the given request headers.
+func variantOf(key string, vary []string, header http.Header) string {
+	if len(vary) == 0 {
+		return key
+	}
+
+	b := strings.Builder{}
+	b.WriteString(key)
+	for _, name := range vary {
+		b.WriteByte(0)
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(strings.Join(header.Values(name), ","))
+	}
+
+	return b.String()
+}
+
+// equal returns true if both lists contain the same values.
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testEntry(key string, body string, tags ...string) *entry {
+	r, _ := http.NewRequest("GET", "http://"+key, nil)
+	header := http.Header{"Cache-Control": {"max-age=60"}}
+
+	e := newEntry(r, http.StatusOK, header, []byte(body), tags)
+	e.Key = key
+
+	return e
+}
+
+func request(key string, header map[string]string) *http.Request {
+	r, _ := http.NewRequest("GET", "http://"+key, nil)
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+
+	return r
+}
+
+func diskFiles(t *testing.T, dir string) int {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+fileExt))
+	assert.NoError(t, err)
+
+	return len(files)
+}
+
+func Test_Store_Memory(t *testing.T) {
+	s, err := newStore(&Config{MaxSize: 1}, func(err error) { t.Error(err) })
+	assert.NoError(t, err)
+	s.mem.max = 200
+
+	s.add(testEntry("host/a", string(make([]byte, 60))))
+	s.add(testEntry("host/b", string(make([]byte, 60))))
+
+	// a becomes recently used, b is evicted
+	assert.NotNil(t, s.get("host/a", request("host/a", nil)))
+	s.add(testEntry("host/c", string(make([]byte, 60))))
+
+	assert.NotNil(t, s.get("host/a", request("host/a", nil)))
+	assert.Nil(t, s.get("host/b", request("host/b", nil)))
+	assert.NotNil(t, s.get("host/c", request("host/c", nil)))
+	assert.Len(t, s.resources, 2)
+}
+
+func Test_Store_Disk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rr")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// files of the previous run are removed
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "stale"+fileExt), []byte("{}"), 0644))
+
+	s, err := newStore(&Config{MaxSize: 1, Dir: dir, DiskSize: 1}, func(err error) { t.Error(err) })
+	assert.NoError(t, err)
+	assert.Equal(t, 0, diskFiles(t, dir))
+
+	s.mem.max = 200
+	s.disk.max = 200
+
+	s.add(testEntry("host/a", "body-a", "tag"))
+	s.add(testEntry("host/b", string(make([]byte, 60))))
+	s.add(testEntry("host/c", string(make([]byte, 60))))
+	assert.Equal(t, 1, diskFiles(t, dir))
+
+	// a is promoted to the memory, b is moved to the disk
+	e := s.get("host/a", request("host/a", nil))
+	assert.NotNil(t, e)
+	assert.Equal(t, "body-a", string(e.Body))
+	assert.Equal(t, []string{"tag"}, e.Tags)
+	assert.Equal(t, 1, diskFiles(t, dir))
+
+	assert.Equal(t, 1, s.purge(func(e *entry) bool { return e.Key == "host/b" }))
+	assert.Equal(t, 0, diskFiles(t, dir))
+	assert.Len(t, s.resources, 2)
+}
+
+func Test_Store_Vary(t *testing.T) {
+	s, err := newStore(&Config{MaxSize: 1}, func(err error) { t.Error(err) })
+	assert.NoError(t, err)
+
+	for _, lang := range []string{"en", "de"} {
+		r := request("host/", map[string]string{"Accept-Language": lang})
+		e := newEntry(r, http.StatusOK, http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"accept-language"}}, []byte(lang), nil)
+		e.Key = "host/"
+		s.add(e)
+	}
+
+	assert.Equal(t, "en", string(s.get("host/", request("host/", map[string]string{"Accept-Language": "en"})).Body))
+	assert.Equal(t, "de", string(s.get("host/", request("host/", map[string]string{"Accept-Language": "de"})).Body))
+	assert.Nil(t, s.get("host/", request("host/", nil)))
+	assert.Equal(t, 2, s.resources["host/"].variants)
+
+	// variants of the previous Vary header are removed
+	s.add(testEntry("host/", "plain"))
+	assert.Equal(t, "plain", string(s.get("host/", request("host/", map[string]string{"Accept-Language": "en"})).Body))
+	assert.Equal(t, 1, s.resources["host/"].variants)
+}
+
+func Test_Store_Expired(t *testing.T) {
+	s, err := newStore(&Config{MaxSize: 1}, func(err error) { t.Error(err) })
+	assert.NoError(t, err)
+
+	e := testEntry("host/a", "a")
+	e.Expires = time.Now()
+	s.add(e)
+
+	assert.Nil(t, s.get("host/a", request("host/a", nil)))
+	assert.Len(t, s.resources, 0)
+}
@@ -5,11 +5,34 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"reflect"
+	"strings"
 	"sync"
+	"time"
 )
 
 var errNoConfig = fmt.Errorf("no config has been provided")
-var errTempFix223 = fmt.Errorf("temporary error for fix #223") // meant no error here, just shutdown the server
+
+// ErrRestartRequired returned by Reconfigurable services when config changes can not be applied without restart.
+var ErrRestartRequired = errors.New("restart is required")
+
+const (
+	// EventServiceRestart thrown when service is about to be restarted (passed with RestartEvent).
+	EventServiceRestart = iota + 1000
+
+	// EventServiceReady thrown when service is ready to serve, service name passed as context.
+	EventServiceReady
+
+	// EventContainerReady thrown when all serving services are ready.
+	EventContainerReady
+)
+
+const (
+	// RestartSection contains name of the config section with service restart policies keyed by service name.
+	RestartSection = "restart"
+
+	// StartupSection contains name of the config section with container startup options.
+	StartupSection = "startup"
+)
 
 // InitMethod contains name of the method to be automatically invoked while service initialization. Must return
 // (bool, error). Container can be requested as well. Config can be requested in a form
@@ -42,14 +65,26 @@ type Container interface {
 	// as second value.
 	Get(service string) (svc interface{}, status int)
 
-	// Serve all configured services. Non blocking.
+	// Serve all configured services. Services are started after their dependencies are ready.
 	Serve() error
 
+	// Ready returns channel which is closed once all serving services are ready.
+	Ready() <-chan struct{}
+
 	// Close all active services.
 	Stop()
 
 	// List service names.
 	List() []string
+
+	// States returns state of all registered services in registration order.
+	States() []State
+
+	// Reconfigure applies new configuration to the configured services which config has changed.
+	Reconfigure(cfg Config) ([]ReconfigureResult, error)
+
+	// AddListener attaches container event watcher.
+	AddListener(l func(event int, ctx interface{}))
 }
 
 // Config provides ability to slice configuration sections and unmarshal configuration data into
@@ -70,20 +105,80 @@ type HydrateConfig interface {
 	Hydrate(cfg Config) error
 }
 
+// Readiness can be implemented by services which are not ready to serve right after Serve invocation. Container
+// starts dependants of the service only after the service is ready.
+type Readiness interface {
+	// Ready must block until service is ready to serve or return error if service has failed to start. Method is
+	// invoked concurrently with every Serve call, including restarts, and must return once Serve is complete.
+	Ready() error
+}
+
+// Reconfigurable can be implemented by services which are able to apply config changes without restart.
+type Reconfigurable interface {
+	// Reconfigure applies new service config, config has the same type as the config requested by Init method.
+	// Must return ErrRestartRequired if changes can not be applied without restart.
+	Reconfigure(cfg HydrateConfig) error
+}
+
+// ReconfigureResult describes reconfiguration of the service which config has changed.
+type ReconfigureResult struct {
+	// Service name.
+	Service string
+
+	// RestartRequired is true when service can not apply config changes without restart.
+	RestartRequired bool
+
+	// Error contains reconfiguration error.
+	Error error
+}
+
+// Dependent can be implemented by services which can not operate without other services. Container fails to
+// init enabled service if any of it's required services is not registered or disabled.
+type Dependent interface {
+	// Requires returns names of the required services.
+	Requires() []string
+}
+
 // DefaultsConfig declares ability to be initated without config data provided.
 type DefaultsConfig interface {
 	// InitDefaults allows to init blank config with pre-defined set of default values.
 	InitDefaults() error
 }
 
+// SectionConfig can be implemented by configs which unmarshal config section into one of their fields instead of
+// the config itself.
+type SectionConfig interface {
+	// Section returns pointer to the value config section is unmarshalled into.
+	Section() interface{}
+}
+
 type container struct {
 	log      logrus.FieldLogger
 	mu       sync.Mutex
 	services []*entry
-	errc     chan struct {
-		name string
-		err  error
-	}
+
+	// services sorted in dependency order and their dependencies, available after Init
+	order []*entry
+	deps  map[*entry][]*entry
+
+	// restart policies by service name
+	policies map[string]*RestartPolicy
+
+	// startup options
+	startup *StartupConfig
+
+	// config sections applied by the services by service name, available after Init
+	sections map[string]Config
+
+	// serializes configuration reloads
+	reload sync.Mutex
+
+	// closed when container is being stopped
+	stop chan struct{}
+
+	// closed when all services are ready
+	ready chan struct{}
+	lsns  []func(event int, ctx interface{})
 }
 
 // NewContainer creates new service container.
@@ -91,13 +186,21 @@ func NewContainer(log logrus.FieldLogger) Container {
 	return &container{
 		log:      log,
 		services: make([]*entry, 0),
-		errc: make(chan struct {
-			name string
-			err  error
-		}, 1),
+		policies: make(map[string]*RestartPolicy),
+		startup:  &StartupConfig{Timeout: time.Minute},
+		stop:     make(chan struct{}),
+		ready:    make(chan struct{}),
 	}
 }
 
+// AddListener attaches container event watcher.
+func (c *container) AddListener(l func(event int, ctx interface{})) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lsns = append(c.lsns, l)
+}
+
 // Register add new service to the container under given name.
 func (c *container) Register(name string, service interface{}) {
 	c.mu.Lock()
@@ -138,9 +241,39 @@ func (c *container) Get(target string) (svc interface{}, status int) {
 	return nil, StatusUndefined
 }
 
-// Init configures all underlying services with given configuration.
+// Init configures all underlying services with given configuration. Services are initiated after their
+// dependencies.
 func (c *container) Init(cfg Config) error {
-	for _, e := range c.services {
+	order, deps, err := c.sortServices()
+	if err != nil {
+		return err
+	}
+
+	policies, err := c.restartPolicies(cfg.Get(RestartSection))
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("[%s]", RestartSection))
+	}
+
+	startup := &StartupConfig{}
+	if sc := cfg.Get(StartupSection); sc != nil {
+		if err := sc.Unmarshal(startup); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("[%s]", StartupSection))
+		}
+	}
+	startup.InitDefaults()
+
+	c.mu.Lock()
+	c.order = order
+	c.deps = deps
+	c.policies = policies
+	c.startup = startup
+	c.sections = make(map[string]Config, len(order))
+	for _, e := range order {
+		c.sections[e.name] = cfg.Get(e.name)
+	}
+	c.mu.Unlock()
+
+	for _, e := range order {
 		if e.getStatus() >= StatusOK {
 			return fmt.Errorf("service [%s] has already been configured", e.name)
 		}
@@ -155,6 +288,10 @@ func (c *container) Init(cfg Config) error {
 
 			return errors.Wrap(err, fmt.Sprintf("[%s]", e.name))
 		} else if ok {
+			if err := c.checkRequired(e); err != nil {
+				return errors.Wrap(err, fmt.Sprintf("[%s]", e.name))
+			}
+
 			e.setStatus(StatusOK)
 		} else {
 			c.log.Debugf("[%s]: disabled", e.name)
@@ -164,58 +301,223 @@ func (c *container) Init(cfg Config) error {
 	return nil
 }
 
-// Serve all configured services. Non blocking.
+// Reconfigure applies new configuration to the configured services which config has changed. Services implementing
+// Reconfigurable are reconfigured without restart, restart is required for other services. Nothing is applied if
+// new configuration is not valid. Services which have failed to apply the changes keep their previous config, so
+// the changes are applied again on the next reconfiguration.
+func (c *container) Reconfigure(cfg Config) ([]ReconfigureResult, error) {
+	c.reload.Lock()
+	defer c.reload.Unlock()
+
+	c.mu.Lock()
+	sections, order := c.sections, c.order
+	c.mu.Unlock()
+
+	if sections == nil {
+		return nil, errors.New("container is not configured")
+	}
+
+	policies, err := c.restartPolicies(cfg.Get(RestartSection))
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("[%s]", RestartSection))
+	}
+
+	type change struct {
+		e   *entry
+		cfg HydrateConfig
+	}
+
+	var changes []change
+	for _, e := range order {
+		prev, _ := hydrateConfig(e.svc, sections[e.name])
+		next, err := hydrateConfig(e.svc, cfg.Get(e.name))
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("[%s]", e.name))
+		}
+
+		if !reflect.DeepEqual(prev, next) {
+			changes = append(changes, change{e: e, cfg: next})
+		}
+	}
+
+	c.mu.Lock()
+	c.policies = policies
+	c.mu.Unlock()
+
+	results := make([]ReconfigureResult, 0, len(changes))
+	for _, ch := range changes {
+		r := ReconfigureResult{Service: ch.e.name}
+
+		rs, ok := ch.e.svc.(Reconfigurable)
+		switch {
+		case !ok, ch.cfg == nil, !ch.e.hasStatus(StatusOK):
+			r.RestartRequired = true
+		default:
+			if err := rs.Reconfigure(ch.cfg); err != nil {
+				r.RestartRequired = errors.Cause(err) == ErrRestartRequired
+				if !r.RestartRequired {
+					r.Error = err
+				}
+			}
+		}
+
+		if r.Error == nil {
+			c.mu.Lock()
+			c.sections[ch.e.name] = cfg.Get(ch.e.name)
+			c.mu.Unlock()
+		}
+
+		switch {
+		case r.Error != nil:
+			c.log.Errorf("[%s]: unable to apply config changes: %s", ch.e.name, r.Error)
+		case r.RestartRequired:
+			c.log.Warningf("[%s]: config has changed, restart is required", ch.e.name)
+		default:
+			c.log.Infof("[%s]: reconfigured", ch.e.name)
+		}
+
+		results = append(results, r)
+	}
+
+	return results, nil
+}
+
+// Serve all configured services. Services are started after their dependencies are ready. Returns when all
+// services are done or once any of the services fails and can not be restarted, in which case all other services
+// are stopped. Container is stopped if services are not ready within startup timeout.
 func (c *container) Serve() error {
-	var running = 0
-	for _, e := range c.services {
+	c.mu.Lock()
+	order, deps := c.order, c.deps
+	if order == nil {
+		order = c.services
+	}
+	timeout := c.startup.Timeout
+	stop := make(chan struct{})
+	c.stop = stop
+	c.mu.Unlock()
+
+	var serving []*entry
+	ready := make(map[*entry]chan struct{})
+	for _, e := range order {
 		if e.hasStatus(StatusOK) && e.canServe() {
-			running++
-			c.log.Debugf("[%s]: started", e.name)
-			go func(e *entry) {
-				e.setStatus(StatusServing)
-				defer e.setStatus(StatusStopped)
-				if err := e.svc.(Service).Serve(); err != nil {
-					c.errc <- struct {
-						name string
-						err  error
-					}{name: e.name, err: errors.Wrap(err, fmt.Sprintf("[%s]", e.name))}
-				} else {
-					c.errc <- struct {
-						name string
-						err  error
-					}{name: e.name, err: errTempFix223}
-				}
-			}(e)
+			serving = append(serving, e)
+			ready[e] = make(chan struct{})
 		}
 	}
 
 	// simple handler to handle empty configs
-	if running == 0 {
+	if len(serving) == 0 {
+		c.setReady()
 		return nil
 	}
 
-	for fail := range c.errc {
-		if fail.err == errTempFix223 {
-			// if we call stop, then stop all plugins
-			break
-		} else {
-			c.log.Errorf("[%s]: %s", fail.name, fail.err)
-			c.Stop()
-			return fail.err
+	errc := make(chan error, len(serving))
+	for _, e := range serving {
+		go func(e *entry) {
+			for _, d := range deps[e] {
+				if r, ok := ready[d]; ok {
+					select {
+					case <-r:
+					case <-stop:
+						errc <- nil
+						return
+					}
+				}
+			}
+
+			c.log.Debugf("[%s]: started", e.name)
+
+			var once sync.Once
+			errc <- c.serve(e, stop, func() { once.Do(func() { close(ready[e]) }) })
+		}(e)
+	}
+
+	allReady := make(chan struct{})
+	go func() {
+		for _, e := range serving {
+			select {
+			case <-ready[e]:
+			case <-stop:
+				return
+			}
+		}
+		close(allReady)
+	}()
+
+	startup := time.NewTimer(timeout)
+	defer startup.Stop()
+
+	timeoutC := startup.C
+	for done := 0; done < len(serving); {
+		select {
+		case err := <-errc:
+			done++
+			if err != nil {
+				return c.fail(err)
+			}
+
+		case <-allReady:
+			allReady, timeoutC = nil, nil
+			c.setReady()
+
+		case <-timeoutC:
+			timeoutC = nil
+			select {
+			case <-stop:
+				// container is stopping
+				continue
+			default:
+			}
+
+			var pending []string
+			for _, e := range serving {
+				select {
+				case <-ready[e]:
+				default:
+					pending = append(pending, fmt.Sprintf("[%s]", e.name))
+				}
+			}
+
+			return c.fail(fmt.Errorf("startup timeout, services are not ready: %s", strings.Join(pending, ", ")))
 		}
 	}
 
 	return nil
 }
 
-// Detach sends stop command to all running services.
+// Ready returns channel which is closed once all serving services are ready.
+func (c *container) Ready() <-chan struct{} {
+	return c.ready
+}
+
+// Detach sends stop command to all running services. Services are stopped before their dependencies.
 func (c *container) Stop() {
-	for _, e := range c.services {
-		if e.hasStatus(StatusServing) {
+	c.mu.Lock()
+	order := c.order
+	if order == nil {
+		order = c.services
+	}
+
+	select {
+	case <-c.stop:
+	default:
+		close(c.stop)
+	}
+	c.mu.Unlock()
+
+	for i := len(order) - 1; i >= 0; i-- {
+		e := order[i]
+		switch {
+		case e.hasStatus(StatusServing):
 			e.setStatus(StatusStopping)
 			e.svc.(Service).Stop()
 			e.setStatus(StatusStopped)
 
+			c.log.Debugf("[%s]: stopped", e.name)
+
+		case e.hasStatus(StatusRestarting):
+			e.setStatus(StatusStopped)
+
 			c.log.Debugf("[%s]: stopped", e.name)
 		}
 	}
@@ -231,6 +533,330 @@ func (c *container) List() []string {
 	return names
 }
 
+// fail stops all services due to the given error.
+func (c *container) fail(err error) error {
+	c.log.Errorf("%s", err)
+	c.Stop()
+
+	return err
+}
+
+// awaitReady waits for the service to become ready.
+func (c *container) awaitReady(e *entry) error {
+	if r, ok := e.svc.(Readiness); ok {
+		if err := r.Ready(); err != nil {
+			return err
+		}
+	}
+
+	c.log.Debugf("[%s]: ready", e.name)
+	c.throw(EventServiceReady, e.name)
+
+	return nil
+}
+
+// setReady marks container as ready.
+func (c *container) setReady() {
+	c.mu.Lock()
+	select {
+	case <-c.ready:
+		c.mu.Unlock()
+		return
+	default:
+		close(c.ready)
+	}
+	c.mu.Unlock()
+
+	c.log.Debugf("all services are ready")
+	c.throw(EventContainerReady, nil)
+}
+
+// serve runs the service and restarts it according to it's restart policy, onReady is called once any run of the
+// service is ready. Readiness failure is handled as service failure. Returns service error if service has failed
+// and can not be restarted.
+func (c *container) serve(e *entry, stop chan struct{}, onReady func()) error {
+	policy := c.restartPolicy(e.name)
+
+	for {
+		// container must not miss the service which is being restarted while it's stopping
+		c.mu.Lock()
+		select {
+		case <-stop:
+			c.mu.Unlock()
+			e.setStatus(StatusStopped)
+			return nil
+		default:
+			e.setServing()
+		}
+		c.mu.Unlock()
+
+		served := make(chan struct{})
+		readyErr := make(chan error, 1)
+		go func() {
+			err := c.awaitReady(e)
+			if err == nil {
+				onReady()
+			} else {
+				select {
+				case <-served:
+				case <-stop:
+				default:
+					// service has failed to start but keeps serving
+					e.svc.(Service).Stop()
+				}
+			}
+			readyErr <- err
+		}()
+
+		err := e.svc.(Service).Serve()
+		close(served)
+		rErr := <-readyErr
+
+		select {
+		case <-stop:
+			// stopped by the container
+			if err != nil {
+				e.setError(errors.Wrap(err, fmt.Sprintf("[%s]", e.name)))
+			}
+			e.setStatus(StatusStopped)
+			return nil
+		default:
+		}
+
+		// readiness error is reported only when service has no own error
+		if err == nil {
+			err = rErr
+		}
+
+		if err != nil {
+			err = errors.Wrap(err, fmt.Sprintf("[%s]", e.name))
+			e.setError(err)
+		}
+
+		delay, ok := policy.Next(e.getRestarts(), err)
+		if !ok {
+			e.setStatus(StatusStopped)
+			return err
+		}
+
+		e.setStatus(StatusRestarting)
+		restarts := e.restarted()
+
+		if err != nil {
+			c.log.Warningf("[%s]: restarting in %s (%s)", e.name, delay, err)
+		} else {
+			c.log.Warningf("[%s]: restarting in %s", e.name, delay)
+		}
+
+		c.throw(EventServiceRestart, RestartEvent{Service: e.name, Restarts: restarts, Delay: delay, Error: err})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-stop:
+			timer.Stop()
+			e.setStatus(StatusStopped)
+			return nil
+		}
+	}
+}
+
+// restartPolicies reads restart policies from the given config section.
+func (c *container) restartPolicies(cfg Config) (map[string]*RestartPolicy, error) {
+	policies := make(map[string]*RestartPolicy)
+	if cfg == nil {
+		return policies, nil
+	}
+
+	if err := cfg.Unmarshal(&policies); err != nil {
+		return nil, err
+	}
+
+	for name, p := range policies {
+		if p == nil {
+			p = &RestartPolicy{}
+			policies[name] = p
+		}
+
+		p.InitDefaults()
+		if err := p.Valid(); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("[%s]", name))
+		}
+	}
+
+	return policies, nil
+}
+
+// restartPolicy returns restart policy of the given service.
+func (c *container) restartPolicy(name string) *RestartPolicy {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if p, ok := c.policies[name]; ok {
+		return p
+	}
+
+	p := &RestartPolicy{}
+	p.InitDefaults()
+
+	return p
+}
+
+// throw invokes event handler if any.
+func (c *container) throw(event int, ctx interface{}) {
+	c.mu.Lock()
+	lsns := c.lsns
+	c.mu.Unlock()
+
+	for _, l := range lsns {
+		l(event, ctx)
+	}
+}
+
+// States returns state of all registered services in registration order.
+func (c *container) States() []State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	states := make([]State, 0, len(c.services))
+	for _, e := range c.services {
+		states = append(states, e.state())
+	}
+
+	return states
+}
+
+// sortServices returns services sorted in dependency order and dependencies of each service, dependencies are resolved using Init method
+// signatures and Dependent interface. Services without dependencies keep registration order.
+func (c *container) sortServices() ([]*entry, map[*entry][]*entry, error) {
+	c.mu.Lock()
+	services := append([]*entry{}, c.services...)
+	c.mu.Unlock()
+
+	deps := make(map[*entry][]*entry, len(services))
+	for _, e := range services {
+		d, err := c.dependencies(e, services)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, fmt.Sprintf("[%s]", e.name))
+		}
+
+		deps[e] = d
+	}
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	var (
+		order = make([]*entry, 0, len(services))
+		state = make(map[*entry]int, len(services))
+		path  []*entry
+		visit func(e *entry) error
+	)
+
+	visit = func(e *entry) error {
+		switch state[e] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", cyclePath(path, e))
+		}
+
+		state[e] = visiting
+		path = append(path, e)
+		for _, d := range deps[e] {
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+
+		state[e] = visited
+		order = append(order, e)
+		return nil
+	}
+
+	for _, e := range services {
+		if err := visit(e); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return order, deps, nil
+}
+
+// dependencies returns services which must be initiated before the given service.
+func (c *container) dependencies(e *entry, services []*entry) (deps []*entry, err error) {
+	if d, ok := e.svc.(Dependent); ok {
+		for _, name := range d.Requires() {
+			dep := findEntry(services, name)
+			if dep == nil {
+				return nil, fmt.Errorf("missing required dependency [%s] (not registered)", name)
+			}
+
+			deps = append(deps, dep)
+		}
+	}
+
+	m, ok := reflect.TypeOf(e.svc).MethodByName(InitMethod)
+	if !ok || c.verifySignature(m) != nil {
+		// signature errors are reported on init
+		return deps, nil
+	}
+
+	for i := 0; i < m.Type.NumIn(); i++ {
+		v := m.Type.In(i)
+		if !isServiceDependency(v, e.svc, c.log) {
+			continue
+		}
+
+		var dep *entry
+		for _, s := range services {
+			if s == e || !provides(s.svc, v) {
+				continue
+			}
+
+			if dep != nil {
+				return nil, fmt.Errorf("disambiguous dependency `%s`", v)
+			}
+
+			dep = s
+		}
+
+		// unresolved dependencies are injected as nil
+		if dep != nil {
+			deps = append(deps, dep)
+		}
+	}
+
+	return deps, nil
+}
+
+// checkRequired ensures that all services required by the enabled service are enabled.
+func (c *container) checkRequired(e *entry) error {
+	d, ok := e.svc.(Dependent)
+	if !ok {
+		return nil
+	}
+
+	for _, name := range d.Requires() {
+		c.mu.Lock()
+		dep := findEntry(c.services, name)
+		c.mu.Unlock()
+
+		if dep == nil {
+			return fmt.Errorf("missing required dependency [%s] (not registered)", name)
+		}
+
+		if !dep.hasStatus(StatusOK) {
+			return fmt.Errorf("missing required dependency [%s] (disabled)", name)
+		}
+	}
+
+	return nil
+}
+
 // calls Init method with automatically resolved arguments.
 func (c *container) initService(s interface{}, segment Config) (bool, error) {
 	r := reflect.TypeOf(s)
@@ -341,25 +967,15 @@ func (c *container) verifySignature(m reflect.Method) error {
 func (c *container) resolveValue(v reflect.Type) (reflect.Value, error) {
 	value := reflect.Value{}
 	for _, e := range c.services {
-		if !e.hasStatus(StatusOK) {
+		if !e.hasStatus(StatusOK) || !provides(e.svc, v) {
 			continue
 		}
 
-		if v.Kind() == reflect.Interface && reflect.TypeOf(e.svc).Implements(v) {
-			if value.IsValid() {
-				return value, fmt.Errorf("disambiguous dependency `%s`", v)
-			}
-
-			value = reflect.ValueOf(e.svc)
+		if value.IsValid() {
+			return value, fmt.Errorf("disambiguous dependency `%s`", v)
 		}
 
-		if v.ConvertibleTo(reflect.ValueOf(e.svc).Type()) {
-			if value.IsValid() {
-				return value, fmt.Errorf("disambiguous dependency `%s`", v)
-			}
-
-			value = reflect.ValueOf(e.svc)
-		}
+		value = reflect.ValueOf(e.svc)
 	}
 
 	if !value.IsValid() {
@@ -369,3 +985,105 @@ func (c *container) resolveValue(v reflect.Type) (reflect.Value, error) {
 
 	return value, nil
 }
+
+// NewConfig creates config requested by the Init method of the given service, config is initiated with default
+// values when possible. Returns nil if service does not request config struct.
+func NewConfig(svc interface{}) (HydrateConfig, error) {
+	m, ok := reflect.TypeOf(svc).MethodByName(InitMethod)
+	if !ok {
+		return nil, nil
+	}
+
+	for i := 0; i < m.Type.NumIn(); i++ {
+		v := m.Type.In(i)
+		if v.Kind() != reflect.Ptr || !v.Implements(reflect.TypeOf((*HydrateConfig)(nil)).Elem()) {
+			continue
+		}
+
+		sc := reflect.New(v.Elem()).Interface()
+		if dsc, ok := sc.(DefaultsConfig); ok {
+			if err := dsc.InitDefaults(); err != nil {
+				return nil, err
+			}
+		}
+
+		return sc.(HydrateConfig), nil
+	}
+
+	return nil, nil
+}
+
+// hydrateConfig creates config requested by the Init method of the given service and hydrates it using config
+// section. Returns nil if service does not request config struct or service can not be configured without the
+// config section.
+func hydrateConfig(svc interface{}, section Config) (HydrateConfig, error) {
+	cfg, err := NewConfig(svc)
+	if err != nil || cfg == nil {
+		return nil, err
+	}
+
+	if section == nil {
+		if _, ok := cfg.(DefaultsConfig); ok {
+			return cfg, nil
+		}
+
+		return nil, nil
+	}
+
+	if err := cfg.Hydrate(section); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// isServiceDependency returns true if Init argument of the given type is resolved using other services.
+func isServiceDependency(v reflect.Type, svc interface{}, log logrus.FieldLogger) bool {
+	switch {
+	case v.ConvertibleTo(reflect.ValueOf(svc).Type()),
+		v.Implements(reflect.TypeOf((*Container)(nil)).Elem()),
+		v.Implements(reflect.TypeOf((*logrus.StdLogger)(nil)).Elem()),
+		v.Implements(reflect.TypeOf((*logrus.FieldLogger)(nil)).Elem()),
+		v.ConvertibleTo(reflect.ValueOf(log).Type()),
+		v.Implements(reflect.TypeOf((*HydrateConfig)(nil)).Elem()),
+		v.Implements(reflect.TypeOf((*Config)(nil)).Elem()):
+		return false
+	}
+
+	return true
+}
+
+// provides returns true if service can be injected as dependency of the given type.
+func provides(svc interface{}, v reflect.Type) bool {
+	if v.Kind() == reflect.Interface && reflect.TypeOf(svc).Implements(v) {
+		return true
+	}
+
+	return v.ConvertibleTo(reflect.ValueOf(svc).Type())
+}
+
+// findEntry returns service entry by it's name.
+func findEntry(services []*entry, name string) *entry {
+	for _, e := range services {
+		if e.name == name {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// cyclePath describes dependency cycle which ends with the given service.
+func cyclePath(path []*entry, e *entry) string {
+	names := make([]string, 0, len(path)+1)
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == e {
+			for _, p := range path[i:] {
+				names = append(names, p.name)
+			}
+			break
+		}
+	}
+
+	return strings.Join(append(names, e.name), " -> ")
+}
@@ -531,3 +531,566 @@ func TestContainer_InitDependencyEmpty(t *testing.T) {
 
 	assert.Contains(t, c.Init(&testCfg{`{"test2":{"v":"ok"}}`}).Error(), "testInitC")
 }
+
+// orderLog records service init and stop order.
+type orderLog struct {
+	mu    sync.Mutex
+	items []string
+}
+
+func (l *orderLog) add(item string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.items = append(l.items, item)
+}
+
+type orderA struct {
+	log  *orderLog
+	done chan interface{}
+}
+
+func (s *orderA) Init() (bool, error) {
+	s.log.add("init:a")
+	s.done = make(chan interface{})
+	return true, nil
+}
+
+func (s *orderA) Serve() error {
+	<-s.done
+	return nil
+}
+
+func (s *orderA) Stop() {
+	s.log.add("stop:a")
+	close(s.done)
+}
+
+type orderB struct {
+	log  *orderLog
+	a    *orderA
+	done chan interface{}
+}
+
+func (s *orderB) Init(a *orderA) (bool, error) {
+	s.log.add("init:b")
+	s.a = a
+	s.done = make(chan interface{})
+	return true, nil
+}
+
+func (s *orderB) Serve() error {
+	<-s.done
+	return nil
+}
+
+func (s *orderB) Stop() {
+	s.log.add("stop:b")
+	close(s.done)
+}
+
+func TestContainer_InitOrder(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	log := &orderLog{}
+	a, b := &orderA{log: log}, &orderB{log: log}
+
+	// dependent service is registered first
+	c := NewContainer(logger)
+	c.Register("b", b)
+	c.Register("a", a)
+
+	assert.NoError(t, c.Init(&testCfg{`{}`}))
+	assert.Same(t, a, b.a)
+
+	go func() { assert.NoError(t, c.Serve()) }()
+	time.Sleep(time.Millisecond * 50)
+	c.Stop()
+
+	assert.Equal(t, []string{"init:a", "init:b", "stop:b", "stop:a"}, log.items)
+	assert.Equal(t, []string{"b", "a"}, c.List())
+}
+
+type cycleA struct{ a int } //nolint:golint,unused,structcheck
+
+func (s *cycleA) Init(b *cycleB) (bool, error) { return true, nil }
+
+type cycleB struct{ b int } //nolint:golint,unused,structcheck
+
+func (s *cycleB) Init(c *cycleC) (bool, error) { return true, nil }
+
+type cycleC struct{ c int } //nolint:golint,unused,structcheck
+
+func (s *cycleC) Init(a *cycleA) (bool, error) { return true, nil }
+
+func TestContainer_InitCycle(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	c := NewContainer(logger)
+	c.Register("a", &cycleA{})
+	c.Register("b", &cycleB{})
+	c.Register("c", &cycleC{})
+
+	err := c.Init(&testCfg{`{}`})
+	assert.Error(t, err)
+	assert.Equal(t, "dependency cycle: a -> b -> c -> a", err.Error())
+}
+
+type requireA struct {
+	ok bool
+}
+
+func (s *requireA) Init() (bool, error) { return s.ok, nil }
+
+type requireB struct{ b int } //nolint:golint,unused,structcheck
+
+func (s *requireB) Init() (bool, error) { return true, nil }
+
+func (s *requireB) Requires() []string { return []string{"a"} }
+
+func TestContainer_InitRequired(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	c := NewContainer(logger)
+	c.Register("b", &requireB{})
+	c.Register("a", &requireA{ok: true})
+	assert.NoError(t, c.Init(&testCfg{`{}`}))
+
+	c = NewContainer(logger)
+	c.Register("b", &requireB{})
+	err := c.Init(&testCfg{`{}`})
+	assert.Error(t, err)
+	assert.Equal(t, "[b]: missing required dependency [a] (not registered)", err.Error())
+
+	c = NewContainer(logger)
+	c.Register("b", &requireB{})
+	c.Register("a", &requireA{ok: false})
+	err = c.Init(&testCfg{`{}`})
+	assert.Error(t, err)
+	assert.Equal(t, "[b]: missing required dependency [a] (disabled)", err.Error())
+}
+
+type testFlaky struct {
+	mu    sync.Mutex
+	fails int
+	calls int
+	done  chan interface{}
+}
+
+func (t *testFlaky) Serve() error {
+	t.mu.Lock()
+	t.calls++
+	if t.calls <= t.fails {
+		t.mu.Unlock()
+		return errors.New("flaky error")
+	}
+	t.done = make(chan interface{})
+	done := t.done
+	t.mu.Unlock()
+
+	<-done
+	return nil
+}
+
+func (t *testFlaky) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.done != nil {
+		close(t.done)
+		t.done = nil
+	}
+}
+
+func (t *testFlaky) getCalls() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.calls
+}
+
+func TestContainer_ServeRestart(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	svc := &testService{ok: true, waitForServe: make(chan interface{})}
+	flaky := &testFlaky{fails: 2}
+
+	c := NewContainer(logger)
+	c.Register("test", svc)
+	c.Register("flaky", flaky)
+
+	var mu sync.Mutex
+	var events []RestartEvent
+	c.AddListener(func(event int, ctx interface{}) {
+		if event == EventServiceRestart {
+			mu.Lock()
+			events = append(events, ctx.(RestartEvent))
+			mu.Unlock()
+		}
+	})
+
+	assert.NoError(t, c.Init(&testCfg{`{
+		"test":"something",
+		"restart":{"flaky":{"policy":"on-failure","backoff":1000000}}
+	}`}))
+
+	done := make(chan error)
+	go func() { done <- c.Serve() }()
+
+	<-svc.waitChan()
+	for flaky.getCalls() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, st := c.Get("test")
+	assert.Equal(t, StatusServing, st)
+
+	states := c.States()
+	assert.Len(t, states, 2)
+	assert.Equal(t, "test", states[0].Name)
+	assert.Equal(t, StatusServing, states[0].Status)
+	assert.False(t, states[0].Started.IsZero())
+	assert.Equal(t, "flaky", states[1].Name)
+	assert.Equal(t, 2, states[1].Restarts)
+	assert.Error(t, states[1].Error)
+
+	c.Stop()
+	assert.NoError(t, <-done)
+
+	_, st = c.Get("flaky")
+	assert.Equal(t, StatusStopped, st)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Len(t, events, 2)
+	assert.Equal(t, "flaky", events[1].Service)
+	assert.Equal(t, 2, events[1].Restarts)
+	assert.Equal(t, time.Millisecond*2, events[1].Delay)
+	assert.Contains(t, events[1].Error.Error(), "flaky error")
+}
+
+func TestContainer_ServeRestartLimit(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	svc := &testService{ok: true, waitForServe: make(chan interface{})}
+	flaky := &testFlaky{fails: 10}
+
+	c := NewContainer(logger)
+	c.Register("test", svc)
+	c.Register("flaky", flaky)
+
+	assert.NoError(t, c.Init(&testCfg{`{
+		"test":"something",
+		"restart":{"flaky":{"policy":"on-failure","maxRestarts":2,"backoff":1000000}}
+	}`}))
+
+	err := c.Serve()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "flaky error")
+	assert.Equal(t, 3, flaky.getCalls())
+
+	_, st := c.Get("test")
+	assert.Equal(t, StatusStopped, st)
+}
+
+func TestContainer_ServeRestartStop(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	flaky := &testFlaky{fails: 10}
+
+	c := NewContainer(logger)
+	c.Register("flaky", flaky)
+
+	restarting := make(chan interface{}, 1)
+	c.AddListener(func(event int, ctx interface{}) {
+		if event != EventServiceRestart {
+			return
+		}
+
+		select {
+		case restarting <- ctx:
+		default:
+		}
+	})
+
+	assert.NoError(t, c.Init(&testCfg{`{"restart":{"flaky":{"policy":"always","backoff":60}}}`}))
+
+	done := make(chan error)
+	go func() { done <- c.Serve() }()
+
+	<-restarting
+
+	_, st := c.Get("flaky")
+	assert.Equal(t, StatusRestarting, st)
+
+	c.Stop()
+	assert.NoError(t, <-done)
+	assert.Equal(t, 1, flaky.getCalls())
+
+	_, st = c.Get("flaky")
+	assert.Equal(t, StatusStopped, st)
+}
+
+func TestContainer_InitRestartPolicy(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	c := NewContainer(logger)
+	c.Register("flaky", &testFlaky{})
+
+	err := c.Init(&testCfg{`{"restart":{"flaky":{"policy":"sometimes"}}}`})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "sometimes")
+}
+
+type testReady struct {
+	ready chan error
+	done  chan interface{}
+}
+
+func (t *testReady) Init() (bool, error) {
+	t.done = make(chan interface{})
+	return true, nil
+}
+
+func (t *testReady) Serve() error {
+	<-t.done
+	return nil
+}
+
+func (t *testReady) Stop() {
+	close(t.done)
+}
+
+func (t *testReady) Ready() error {
+	select {
+	case err := <-t.ready:
+		return err
+	case <-t.done:
+		return errors.New("stopped")
+	}
+}
+
+type testReadyDependant struct {
+	testService
+	dep *testReady
+}
+
+func (t *testReadyDependant) Init(dep *testReady) (bool, error) {
+	t.dep = dep
+	t.done = make(chan interface{})
+	return true, nil
+}
+
+func TestContainer_ServeReady(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	dep := &testReady{ready: make(chan error)}
+	svc := &testReadyDependant{testService: testService{waitForServe: make(chan interface{})}}
+
+	c := NewContainer(logger)
+	c.Register("dependant", svc)
+	c.Register("dep", dep)
+	assert.NoError(t, c.Init(&testCfg{`{}`}))
+
+	done := make(chan error)
+	go func() { done <- c.Serve() }()
+
+	time.Sleep(time.Millisecond * 50)
+
+	_, st := c.Get("dependant")
+	assert.Equal(t, StatusOK, st)
+
+	select {
+	case <-c.Ready():
+		t.Error("container must not be ready")
+	default:
+	}
+
+	dep.ready <- nil
+
+	<-svc.waitChan()
+	<-c.Ready()
+
+	_, st = c.Get("dependant")
+	assert.Equal(t, StatusServing, st)
+
+	c.Stop()
+	assert.NoError(t, <-done)
+}
+
+func TestContainer_ServeReadyError(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	dep := &testReady{ready: make(chan error, 1)}
+	dep.ready <- errors.New("ready error")
+
+	c := NewContainer(logger)
+	c.Register("dep", dep)
+	assert.NoError(t, c.Init(&testCfg{`{}`}))
+
+	err := c.Serve()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "[dep]: ready error")
+
+	_, st := c.Get("dep")
+	assert.Equal(t, StatusStopped, st)
+}
+
+// testReadyFlaky reports readiness of every run of the flaky service.
+type testReadyFlaky struct {
+	testFlaky
+	ready chan error
+}
+
+func (t *testReadyFlaky) Serve() error {
+	if t.getCalls() < t.fails {
+		t.ready <- errors.New("not started")
+	} else {
+		t.ready <- nil
+	}
+
+	return t.testFlaky.Serve()
+}
+
+func (t *testReadyFlaky) Ready() error {
+	return <-t.ready
+}
+
+func TestContainer_ServeReadyRestart(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	dep := &testReadyFlaky{testFlaky: testFlaky{fails: 1}, ready: make(chan error, 1)}
+
+	c := NewContainer(logger)
+	c.Register("dep", dep)
+	assert.NoError(t, c.Init(&testCfg{`{
+		"restart":{"dep":{"policy":"on-failure","backoff":1000000}},
+		"startup":{"timeout":1000000000}
+	}`}))
+
+	done := make(chan error)
+	go func() { done <- c.Serve() }()
+
+	<-c.Ready()
+	assert.Equal(t, 2, dep.getCalls())
+
+	states := c.States()
+	assert.Equal(t, 1, states[0].Restarts)
+	assert.Equal(t, StatusServing, states[0].Status)
+
+	c.Stop()
+	assert.NoError(t, <-done)
+}
+
+func TestContainer_ServeStartupTimeout(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	dep := &testReady{ready: make(chan error)}
+	svc := &testReadyDependant{testService: testService{waitForServe: make(chan interface{})}}
+
+	c := NewContainer(logger)
+	c.Register("dep", dep)
+	c.Register("dependant", svc)
+	assert.NoError(t, c.Init(&testCfg{`{"startup":{"timeout":10000000}}`}))
+
+	err := c.Serve()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "startup timeout")
+	assert.Contains(t, err.Error(), "[dep], [dependant]")
+
+	_, st := c.Get("dep")
+	assert.Equal(t, StatusStopped, st)
+
+	_, st = c.Get("dependant")
+	assert.Equal(t, StatusOK, st)
+}
+
+func TestContainer_NewConfig(t *testing.T) {
+	cfg, err := NewConfig(&dService{})
+	assert.NoError(t, err)
+	assert.Equal(t, &dConfig{Value: "default"}, cfg)
+
+	cfg, err = NewConfig(&testService{})
+	assert.NoError(t, err)
+	assert.Nil(t, cfg)
+}
+
+type reconfigurable struct {
+	dService
+	err error
+}
+
+func (s *reconfigurable) Reconfigure(cfg HydrateConfig) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.Cfg = cfg.(*dConfig)
+	return nil
+}
+
+func TestContainer_Reconfigure(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	a := &reconfigurable{}
+	b := &reconfigurable{err: ErrRestartRequired}
+	d := &dService{}
+
+	c := NewContainer(logger)
+	c.Register("a", a)
+	c.Register("b", b)
+	c.Register("c", &reconfigurable{})
+	c.Register("d", d)
+
+	_, err := c.Reconfigure(&testCfg{`{}`})
+	assert.Error(t, err)
+
+	assert.NoError(t, c.Init(&testCfg{`{"a":{"value":"a"},"b":{"value":"b"},"c":{"value":"c"},"d":{"value":"d"}}`}))
+
+	res, err := c.Reconfigure(&testCfg{`{"a":{"value":"a2"},"b":{"value":"b2"},"c":{"value":"c"},"d":{"value":"d2"}}`})
+	assert.NoError(t, err)
+	assert.Equal(t, []ReconfigureResult{
+		{Service: "a"},
+		{Service: "b", RestartRequired: true},
+		{Service: "d", RestartRequired: true},
+	}, res)
+
+	assert.Equal(t, "a2", a.Cfg.Value)
+	assert.Equal(t, "b", b.Cfg.Value)
+	assert.Equal(t, "d", d.Cfg.Value)
+
+	// no changes
+	res, err = c.Reconfigure(&testCfg{`{"a":{"value":"a2"},"b":{"value":"b2"},"c":{"value":"c"},"d":{"value":"d2"}}`})
+	assert.NoError(t, err)
+	assert.Len(t, res, 0)
+}
+
+func TestContainer_ReconfigureError(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	a := &reconfigurable{err: errors.New("failure")}
+
+	c := NewContainer(logger)
+	c.Register("a", a)
+	assert.NoError(t, c.Init(&testCfg{`{"a":{"value":"a"}}`}))
+
+	res, err := c.Reconfigure(&testCfg{`{"a":{"value":"a2"}}`})
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.False(t, res[0].RestartRequired)
+	assert.Error(t, res[0].Error)
+
+	// failed changes are applied again
+	a.err = nil
+	res, err = c.Reconfigure(&testCfg{`{"a":{"value":"a2"}}`})
+	assert.NoError(t, err)
+	assert.Equal(t, []ReconfigureResult{{Service: "a"}}, res)
+	assert.Equal(t, "a2", a.Cfg.Value)
+
+	_, err = c.Reconfigure(&testCfg{`{"a":{"value":"a2"},"restart":{"a":{"policy":"sometimes"}}}`})
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"sync"
+	"time"
 )
 
 const (
@@ -22,6 +23,9 @@ const (
 
 	// StatusStopped when service being stopped.
 	StatusStopped
+
+	// StatusRestarting when service is waiting to be restarted.
+	StatusRestarting
 )
 
 // entry creates association between service instance and given name.
@@ -30,6 +34,33 @@ type entry struct {
 	svc    interface{}
 	mu     sync.Mutex
 	status int
+
+	// number of service restarts
+	restarts int
+
+	// time when service has started serving
+	started time.Time
+
+	// last service error
+	err error
+}
+
+// State describes current state of the service.
+type State struct {
+	// Name of the service.
+	Name string
+
+	// Status of the service.
+	Status int
+
+	// Started contains time when service has started serving, zero if service has never been started.
+	Started time.Time
+
+	// Restarts contains number of service restarts.
+	Restarts int
+
+	// Error contains last error returned by the service.
+	Error error
 }
 
 // status returns service status
@@ -47,6 +78,54 @@ func (e *entry) setStatus(status int) {
 	e.status = status
 }
 
+// setServing indicates that service has started serving.
+func (e *entry) setServing() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.status = StatusServing
+	e.started = time.Now()
+}
+
+// setError stores last service error.
+func (e *entry) setError(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.err = err
+}
+
+// state returns current service state.
+func (e *entry) state() State {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return State{
+		Name:     e.name,
+		Status:   e.status,
+		Started:  e.started,
+		Restarts: e.restarts,
+		Error:    e.err,
+	}
+}
+
+// getRestarts returns number of service restarts.
+func (e *entry) getRestarts() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.restarts
+}
+
+// restarted increments number of service restarts.
+func (e *entry) restarted() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.restarts++
+	return e.restarts
+}
+
 // hasStatus checks if entry in specific status
 func (e *entry) hasStatus(status int) bool {
 	return e.getStatus() == status
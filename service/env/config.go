@@ -15,6 +15,11 @@ func (c *Config) Hydrate(cfg service.Config) error {
 	return cfg.Unmarshal(&c.Values)
 }
 
+// Section returns value config section is unmarshalled into.
+func (c *Config) Section() interface{} {
+	return &c.Values
+}
+
 // InitDefaults allows to init blank config with pre-defined set of default values.
 func (c *Config) InitDefaults() error {
 	c.Values = make(map[string]string)
@@ -1,9 +1,12 @@
 package headers
 
 import (
+	"fmt"
+	"github.com/spiral/roadrunner/service"
 	rrhttp "github.com/spiral/roadrunner/service/http"
 	"net/http"
 	"strconv"
+	"sync"
 )
 
 // ID contains default service name.
@@ -12,6 +15,7 @@ const ID = "headers"
 // Service serves headers files. Potentially convert into middleware?
 type Service struct {
 	// server configuration (location, forbidden files and etc)
+	mu  sync.Mutex
 	cfg *Config
 }
 
@@ -28,30 +32,53 @@ func (s *Service) Init(cfg *Config, r *rrhttp.Service) (bool, error) {
 	return true, nil
 }
 
+// Reconfigure replaces service configuration, requests in progress are served using previous configuration.
+func (s *Service) Reconfigure(cfg service.HydrateConfig) error {
+	c, ok := cfg.(*Config)
+	if !ok {
+		return fmt.Errorf("invalid config type %T", cfg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cfg = c
+	return nil
+}
+
+// config returns current service configuration.
+func (s *Service) config() *Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cfg
+}
+
 // middleware must return true if request/response pair is handled within the middleware.
 func (s *Service) middleware(f http.HandlerFunc) http.HandlerFunc {
 	// Define the http.HandlerFunc
 	return func(w http.ResponseWriter, r *http.Request) {
+		cfg := s.config()
 
-		if s.cfg.Request != nil {
-			for k, v := range s.cfg.Request {
+		if cfg.Request != nil {
+			for k, v := range cfg.Request {
 				r.Header.Add(k, v)
 			}
 		}
 
-		if s.cfg.Response != nil {
-			for k, v := range s.cfg.Response {
+		if cfg.Response != nil {
+			for k, v := range cfg.Response {
 				w.Header().Set(k, v)
 			}
 		}
 
-		if s.cfg.CORS != nil {
+		if cfg.CORS != nil {
 			if r.Method == http.MethodOptions {
-				s.preflightRequest(w, r)
+				s.preflightRequest(cfg.CORS, w, r)
 				return
 			}
 
-			s.corsHeaders(w, r)
+			s.corsHeaders(cfg.CORS, w, r)
 		}
 
 		f(w, r)
@@ -59,55 +86,55 @@ func (s *Service) middleware(f http.HandlerFunc) http.HandlerFunc {
 }
 
 // configure OPTIONS response
-func (s *Service) preflightRequest(w http.ResponseWriter, r *http.Request) {
+func (s *Service) preflightRequest(cors *CORSConfig, w http.ResponseWriter, r *http.Request) {
 	headers := w.Header()
 
 	headers.Add("Vary", "Origin")
 	headers.Add("Vary", "Access-Control-Request-Method")
 	headers.Add("Vary", "Access-Control-Request-Headers")
 
-	if s.cfg.CORS.AllowedOrigin != "" {
-		headers.Set("Access-Control-Allow-Origin", s.cfg.CORS.AllowedOrigin)
+	if cors.AllowedOrigin != "" {
+		headers.Set("Access-Control-Allow-Origin", cors.AllowedOrigin)
 	}
 
-	if s.cfg.CORS.AllowedHeaders != "" {
-		headers.Set("Access-Control-Allow-Headers", s.cfg.CORS.AllowedHeaders)
+	if cors.AllowedHeaders != "" {
+		headers.Set("Access-Control-Allow-Headers", cors.AllowedHeaders)
 	}
 
-	if s.cfg.CORS.AllowedMethods != "" {
-		headers.Set("Access-Control-Allow-Methods", s.cfg.CORS.AllowedMethods)
+	if cors.AllowedMethods != "" {
+		headers.Set("Access-Control-Allow-Methods", cors.AllowedMethods)
 	}
 
-	if s.cfg.CORS.AllowCredentials != nil {
-		headers.Set("Access-Control-Allow-Credentials", strconv.FormatBool(*s.cfg.CORS.AllowCredentials))
+	if cors.AllowCredentials != nil {
+		headers.Set("Access-Control-Allow-Credentials", strconv.FormatBool(*cors.AllowCredentials))
 	}
 
-	if s.cfg.CORS.MaxAge > 0 {
-		headers.Set("Access-Control-Max-Age", strconv.Itoa(s.cfg.CORS.MaxAge))
+	if cors.MaxAge > 0 {
+		headers.Set("Access-Control-Max-Age", strconv.Itoa(cors.MaxAge))
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
 // configure CORS headers
-func (s *Service) corsHeaders(w http.ResponseWriter, r *http.Request) {
+func (s *Service) corsHeaders(cors *CORSConfig, w http.ResponseWriter, r *http.Request) {
 	headers := w.Header()
 
 	headers.Add("Vary", "Origin")
 
-	if s.cfg.CORS.AllowedOrigin != "" {
-		headers.Set("Access-Control-Allow-Origin", s.cfg.CORS.AllowedOrigin)
+	if cors.AllowedOrigin != "" {
+		headers.Set("Access-Control-Allow-Origin", cors.AllowedOrigin)
 	}
 
-	if s.cfg.CORS.AllowedHeaders != "" {
-		headers.Set("Access-Control-Allow-Headers", s.cfg.CORS.AllowedHeaders)
+	if cors.AllowedHeaders != "" {
+		headers.Set("Access-Control-Allow-Headers", cors.AllowedHeaders)
 	}
 
-	if s.cfg.CORS.ExposedHeaders != "" {
-		headers.Set("Access-Control-Expose-Headers", s.cfg.CORS.ExposedHeaders)
+	if cors.ExposedHeaders != "" {
+		headers.Set("Access-Control-Expose-Headers", cors.ExposedHeaders)
 	}
 
-	if s.cfg.CORS.AllowCredentials != nil {
-		headers.Set("Access-Control-Allow-Credentials", strconv.FormatBool(*s.cfg.CORS.AllowCredentials))
+	if cors.AllowCredentials != nil {
+		headers.Set("Access-Control-Allow-Credentials", strconv.FormatBool(*cors.AllowCredentials))
 	}
 }
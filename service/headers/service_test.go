@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -338,3 +339,18 @@ func TestCORS_Pass(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func Test_Reconfigure(t *testing.T) {
+	s := &Service{cfg: &Config{Response: map[string]string{"output": "value"}}}
+	h := s.middleware(func(w http.ResponseWriter, r *http.Request) {})
+
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, "value", w.Header().Get("output"))
+
+	assert.NoError(t, s.Reconfigure(&Config{Response: map[string]string{"output": "changed"}}))
+
+	w = httptest.NewRecorder()
+	h(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, "changed", w.Header().Get("output"))
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	rrhttp "github.com/spiral/roadrunner/service/http"
+	"github.com/spiral/roadrunner/util"
 )
 
 const (
@@ -55,7 +56,12 @@ func (s *Service) Serve() error {
 	}
 	s.mu.Unlock()
 
-	err := s.http.ListenAndServe()
+	ln, err := util.Listen("tcp", s.cfg.Address)
+	if err != nil {
+		return err
+	}
+
+	err = s.http.Serve(ln)
 	if err != nil && err != http.ErrServerClosed {
 		return err
 	}
@@ -6,8 +6,11 @@ import (
 	"github.com/spiral/roadrunner"
 	"github.com/spiral/roadrunner/service"
 	"net"
+	"net/http"
 	"os"
+	"reflect"
 	"strings"
+	"time"
 )
 
 // Config configures RoadRunner HTTP server.
@@ -24,9 +27,18 @@ type Config struct {
 	// HTTP2 configuration
 	HTTP2 *HTTP2Config
 
+	// Listeners declares additional http servers, i.e. internal unix socket or separate IPv6 address.
+	Listeners []*ListenerConfig
+
 	// MaxRequestSize specified max size for payload body in megabytes, set 0 to unlimited.
 	MaxRequestSize int64
 
+	// MaxHeaderSize limits size of the request headers in kilobytes, defaults to 1024.
+	MaxHeaderSize int64
+
+	// Timeouts configures timeouts of the http, https, fcgi and additional servers and request execution deadline.
+	Timeouts *TimeoutsConfig
+
 	// TrustedSubnets declare IP subnets which are allowed to set ip using X-Real-Ip and X-Forwarded-For
 	TrustedSubnets []string
 	cidrs          []*net.IPNet
@@ -34,8 +46,21 @@ type Config struct {
 	// Uploads configures uploads configuration.
 	Uploads *UploadsConfig
 
+	// Errors configures error responses.
+	Errors *ErrorsConfig
+
+	// RequestID configures request id assigned to every request.
+	RequestID *RequestIDConfig
+
 	// Workers configures rr server and worker pool.
 	Workers *roadrunner.ServerConfig
+
+	// Pools declares additional named worker pools, requests are directed to the pools using Routes.
+	Pools map[string]*PoolConfig
+
+	// Routes direct matching requests to the named worker pools, routes are matched in order of declaration.
+	// Requests not matching any route are served by the default pool (Workers).
+	Routes []*RouteConfig
 }
 
 // FCGIConfig for FastCGI server.
@@ -82,7 +107,49 @@ type SSLConfig struct {
 	RootCA string
 }
 
-// EnableHTTP is true when http server must run.
+// sameListeners returns true if both configs define the same http, https, fcgi and additional servers.
+func (c *Config) sameListeners(cfg *Config) bool {
+	return c.Address == cfg.Address &&
+		c.MaxHeaderSize == cfg.MaxHeaderSize &&
+		c.Timeouts.sameServer(cfg.Timeouts) &&
+		c.SSL == cfg.SSL &&
+		reflect.DeepEqual(c.FCGI, cfg.FCGI) &&
+		reflect.DeepEqual(c.Listeners, cfg.Listeners) &&
+		reflect.DeepEqual(c.HTTP2, cfg.HTTP2)
+}
+
+// samePools returns true if both configs define the same set of named worker pools.
+func (c *Config) samePools(cfg *Config) bool {
+	if len(c.Pools) != len(cfg.Pools) {
+		return false
+	}
+
+	for name := range c.Pools {
+		if _, ok := cfg.Pools[name]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// drainTimeout returns the longest drain timeout of the worker pools, 0 if any of the pools drains without limit.
+func (c *Config) drainTimeout() time.Duration {
+	timeout := c.Workers.Pool.DrainTimeout
+	for _, p := range c.Pools {
+		if timeout == 0 || p.Workers.Pool.DrainTimeout == 0 {
+			return 0
+		}
+
+		if p.Workers.Pool.DrainTimeout > timeout {
+			timeout = p.Workers.Pool.DrainTimeout
+		}
+	}
+
+	return timeout
+}
+
+// EnableHTTP is true when main http server must run.
 func (c *Config) EnableHTTP() bool {
 	return c.Address != ""
 }
@@ -125,6 +192,22 @@ func (c *Config) Hydrate(cfg service.Config) error {
 		c.Uploads = &UploadsConfig{}
 	}
 
+	if c.Timeouts == nil {
+		c.Timeouts = &TimeoutsConfig{}
+	}
+
+	if c.Errors == nil {
+		c.Errors = &ErrorsConfig{}
+	}
+
+	if c.RequestID == nil {
+		c.RequestID = &RequestIDConfig{}
+	}
+
+	if c.MaxHeaderSize == 0 {
+		c.MaxHeaderSize = 1024
+	}
+
 	if c.SSL.Port == 0 {
 		c.SSL.Port = 443
 	}
@@ -141,12 +224,35 @@ func (c *Config) Hydrate(cfg service.Config) error {
 	if err != nil {
 		return err
 	}
+	err = c.Timeouts.InitDefaults()
+	if err != nil {
+		return err
+	}
+	err = c.Errors.InitDefaults()
+	if err != nil {
+		return err
+	}
+	err = c.RequestID.InitDefaults()
+	if err != nil {
+		return err
+	}
 
 	if err := cfg.Unmarshal(c); err != nil {
 		return err
 	}
 
 	c.Workers.UpscaleDurations()
+	c.Timeouts.UpscaleDurations()
+
+	if err := c.hydratePools(cfg.Get("pools")); err != nil {
+		return err
+	}
+
+	for i, r := range c.Routes {
+		if err := r.Compile(); err != nil {
+			return fmt.Errorf("route #%v: %s", i, err)
+		}
+	}
 
 	if c.TrustedSubnets == nil {
 		// @see https://en.wikipedia.org/wiki/Reserved_IP_addresses
@@ -165,25 +271,90 @@ func (c *Config) Hydrate(cfg service.Config) error {
 		return err
 	}
 
-	return c.Valid()
+	for _, l := range c.Listeners {
+		if err := l.hydrate(); err != nil {
+			return fmt.Errorf("listener `%s`: %s", l.Address, err)
+		}
+	}
+
+	if err := c.Valid(); err != nil {
+		return err
+	}
+
+	return c.Errors.parseTemplates()
 }
 
-func (c *Config) parseCIDRs() error {
-	for _, cidr := range c.TrustedSubnets {
-		_, cr, err := net.ParseCIDR(cidr)
-		if err != nil {
-			return err
+// hydratePools populates pool configs using their own config sections, missing pool values are set to their
+// defaults.
+func (c *Config) hydratePools(cfg service.Config) error {
+	for name := range c.Pools {
+		var section service.Config
+		if cfg != nil {
+			section = cfg.Get(name)
+		}
+
+		if section == nil {
+			return fmt.Errorf("malformed config of the `%s` pool", name)
 		}
 
-		c.cidrs = append(c.cidrs, cr)
+		p := &PoolConfig{}
+		if err := p.Hydrate(section); err != nil {
+			return fmt.Errorf("pool `%s`: %s", name, err)
+		}
+
+		c.Pools[name] = p
 	}
 
 	return nil
 }
 
+// MaxRequestSizeOf returns max payload size in megabytes for the requests served by the given pool, 0 if unlimited.
+func (c *Config) MaxRequestSizeOf(pool string) int64 {
+	if p, ok := c.Pools[pool]; ok && p.MaxRequestSize != 0 {
+		return p.MaxRequestSize
+	}
+
+	return c.MaxRequestSize
+}
+
+// Route returns name of the pool which must serve the request, DefaultPool when request does not match any route.
+func (c *Config) Route(r *http.Request) string {
+	for _, route := range c.Routes {
+		if route.Match(r) {
+			return route.Pool
+		}
+	}
+
+	return DefaultPool
+}
+
+func (c *Config) parseCIDRs() (err error) {
+	c.cidrs, err = parseCIDRs(c.TrustedSubnets)
+	return err
+}
+
 // IsTrusted if api can be trusted to use X-Real-Ip, X-Forwarded-For
 func (c *Config) IsTrusted(ip string) bool {
-	if c.cidrs == nil {
+	return isTrusted(c.cidrs, ip)
+}
+
+// parseCIDRs parses list of subnets, returns nil if list is empty.
+func parseCIDRs(subnets []string) (cidrs []*net.IPNet, err error) {
+	for _, cidr := range subnets {
+		_, cr, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+
+		cidrs = append(cidrs, cr)
+	}
+
+	return cidrs, nil
+}
+
+// isTrusted returns true if ip belongs to any of the given subnets.
+func isTrusted(cidrs []*net.IPNet, ip string) bool {
+	if cidrs == nil {
 		return false
 	}
 
@@ -192,7 +363,7 @@ func (c *Config) IsTrusted(ip string) bool {
 		return false
 	}
 
-	for _, cird := range c.cidrs {
+	for _, cird := range cidrs {
 		if cird.Contains(i) {
 			return true
 		}
@@ -215,6 +386,22 @@ func (c *Config) Valid() error {
 		return errors.New("malformed workers config")
 	}
 
+	if c.Timeouts != nil {
+		if err := c.Timeouts.Valid(); err != nil {
+			return err
+		}
+	}
+
+	if c.Errors != nil {
+		if err := c.Errors.Valid(); err != nil {
+			return err
+		}
+	}
+
+	if c.MaxHeaderSize < 0 {
+		return errors.New("max header size must not be negative")
+	}
+
 	if c.Workers.Pool == nil {
 		return errors.New("malformed workers config (pool config is missing)")
 	}
@@ -223,8 +410,28 @@ func (c *Config) Valid() error {
 		return err
 	}
 
-	if !c.EnableHTTP() && !c.EnableTLS() && !c.EnableFCGI() {
-		return errors.New("unable to run http service, no method has been specified (http, https, http/2 or FastCGI)")
+	if _, ok := c.Pools[DefaultPool]; ok {
+		return fmt.Errorf("pool name `%s` is reserved for the default pool", DefaultPool)
+	}
+
+	for i, r := range c.Routes {
+		if r.Pool == "" {
+			return fmt.Errorf("route #%v: pool is missing", i)
+		}
+
+		if _, ok := c.Pools[r.Pool]; !ok && r.Pool != DefaultPool {
+			return fmt.Errorf("route #%v: undefined pool `%s`", i, r.Pool)
+		}
+	}
+
+	if !c.EnableHTTP() && !c.EnableTLS() && !c.EnableFCGI() && len(c.Listeners) == 0 {
+		return errors.New("unable to run http service, no method has been specified (http, https, http/2, FastCGI or listeners)")
+	}
+
+	for _, l := range c.Listeners {
+		if err := l.Valid(); err != nil {
+			return err
+		}
 	}
 
 	if c.Address != "" && !strings.Contains(c.Address, ":") {
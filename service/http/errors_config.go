@@ -0,0 +1,200 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"text/template"
+
+	json "github.com/json-iterator/go"
+)
+
+const (
+	// ErrorFormatAuto selects error response format using request Accept header.
+	ErrorFormatAuto = "auto"
+
+	// ErrorFormatText renders errors as plain text.
+	ErrorFormatText = "text"
+
+	// ErrorFormatHTML renders errors as html page.
+	ErrorFormatHTML = "html"
+
+	// ErrorFormatJSON renders errors as json object.
+	ErrorFormatJSON = "json"
+)
+
+// default error templates by format
+var defaultErrorTemplates = map[string]string{
+	ErrorFormatText: `{{.Message}}{{if .Error}}: {{.Error}}{{end}}`,
+	ErrorFormatJSON: `{"status":{{.Status}},"message":{{json .Message}}{{if .Error}},"error":{{json .Error}}{{end}}}`,
+	ErrorFormatHTML: `<!DOCTYPE html>
+<html>
+<head><title>{{.Status}} {{.Message}}</title></head>
+<body>
+<h1>{{.Status}} {{.Message}}</h1>
+{{if .Error}}<pre>{{.Error}}</pre>{{end}}
+</body>
+</html>
+`,
+}
+
+// content types of the error responses by format
+var errorContentTypes = map[string]string{
+	ErrorFormatText: "text/plain; charset=utf-8",
+	ErrorFormatJSON: "application/json",
+	ErrorFormatHTML: "text/html; charset=utf-8",
+}
+
+// ErrorsConfig configures error responses sent to the clients when request can not be served by the workers.
+type ErrorsConfig struct {
+	// Debug includes original error message into the response, must not be enabled in production since errors
+	// might contain worker output, file paths and other internal details.
+	Debug bool
+
+	// Format of the error responses: auto (default, by Accept header), text, html or json.
+	Format string
+
+	// Templates overwrite built-in error templates by format, files are parsed as Go templates with .Status,
+	// .Message and .Error (debug only) values, html templates are escaped automatically.
+	Templates map[string]string
+
+	// parsed templates by format
+	templates map[string]errorTemplate
+}
+
+// errorTemplate renders error page.
+type errorTemplate interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// errorPage contains values available in error templates.
+type errorPage struct {
+	// Status code of the response.
+	Status int
+
+	// Message is the status text.
+	Message string
+
+	// Error contains original error message, empty unless debug is enabled.
+	Error string
+}
+
+// InitDefaults sets missing values to their default values.
+func (cfg *ErrorsConfig) InitDefaults() error {
+	cfg.Format = ErrorFormatAuto
+	return nil
+}
+
+// Valid validates the configuration.
+func (cfg *ErrorsConfig) Valid() error {
+	switch cfg.Format {
+	case ErrorFormatAuto, ErrorFormatText, ErrorFormatHTML, ErrorFormatJSON:
+	default:
+		return fmt.Errorf("invalid error format `%s`", cfg.Format)
+	}
+
+	for format := range cfg.Templates {
+		if _, ok := defaultErrorTemplates[format]; !ok {
+			return fmt.Errorf("invalid error template format `%s`", format)
+		}
+	}
+
+	return nil
+}
+
+// parseTemplates loads custom and built-in error templates.
+func (cfg *ErrorsConfig) parseTemplates() error {
+	cfg.templates = make(map[string]errorTemplate, len(defaultErrorTemplates))
+	for format, text := range defaultErrorTemplates {
+		if file, ok := cfg.Templates[format]; ok {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				return err
+			}
+
+			text = string(data)
+		}
+
+		tpl, err := parseErrorTemplate(format, text)
+		if err != nil {
+			return fmt.Errorf("%s error template: %s", format, err)
+		}
+
+		cfg.templates[format] = tpl
+	}
+
+	return nil
+}
+
+// write sends error response using format requested by the client, error details are included in debug mode only.
+func (cfg *ErrorsConfig) write(w http.ResponseWriter, r *http.Request, status int, err error) error {
+	page := &errorPage{Status: status, Message: http.StatusText(status)}
+	if cfg != nil && cfg.Debug {
+		page.Error = err.Error()
+	}
+
+	format := cfg.format(r)
+
+	buf := &bytes.Buffer{}
+	if e := cfg.template(format).Execute(buf, page); e != nil {
+		// broken custom template, fallback to the status text
+		format = ErrorFormatText
+		buf.Reset()
+		buf.WriteString(page.Message)
+	}
+
+	w.Header().Set("Content-Type", errorContentTypes[format])
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+
+	_, e := w.Write(buf.Bytes())
+	return e
+}
+
+// format returns error response format for the given request.
+func (cfg *ErrorsConfig) format(r *http.Request) string {
+	if cfg != nil && cfg.Format != "" && cfg.Format != ErrorFormatAuto {
+		return cfg.Format
+	}
+
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "application/json"), strings.Contains(accept, "+json"):
+		return ErrorFormatJSON
+	case strings.Contains(accept, "text/html"):
+		return ErrorFormatHTML
+	default:
+		return ErrorFormatText
+	}
+}
+
+// template returns parsed template of the given format, built-in template is used when configuration has not
+// been hydrated.
+func (cfg *ErrorsConfig) template(format string) errorTemplate {
+	if cfg != nil && cfg.templates != nil {
+		return cfg.templates[format]
+	}
+
+	tpl, _ := parseErrorTemplate(format, defaultErrorTemplates[format])
+	return tpl
+}
+
+// parseErrorTemplate parses template of the given format.
+func parseErrorTemplate(format, text string) (errorTemplate, error) {
+	if format == ErrorFormatHTML {
+		return htmltemplate.New(format).Parse(text)
+	}
+
+	return template.New(format).Funcs(template.FuncMap{"json": jsonValue}).Parse(text)
+}
+
+// jsonValue encodes value as json.
+func jsonValue(v interface{}) (string, error) {
+	j := json.ConfigCompatibleWithStandardLibrary
+	data, err := j.Marshal(v)
+	return string(data), err
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/spiral/roadrunner"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ErrorStatus(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{errors.New("relay error"), http.StatusInternalServerError},
+		{roadrunner.JobError("php error"), http.StatusInternalServerError},
+		{roadrunner.ErrPoolStopped, http.StatusServiceUnavailable},
+		{pkgerrors.Wrap(roadrunner.ErrExecAborted, "aborted"), http.StatusServiceUnavailable},
+		{pkgerrors.Wrap(roadrunner.AllocateTimeoutError{}, "unable to allocate worker"), http.StatusServiceUnavailable},
+		{ErrExecTimeout, http.StatusGatewayTimeout},
+		{ErrRequestTooLarge, http.StatusRequestEntityTooLarge},
+		{roadrunner.ErrMalformedResponse, http.StatusBadGateway},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.status, errorStatus(c.err), c.err.Error())
+	}
+
+	_, err := NewResponse(&roadrunner.Payload{Context: []byte("{")})
+	assert.Equal(t, http.StatusBadGateway, errorStatus(err))
+}
+
+func Test_Errors_Write(t *testing.T) {
+	cfg := &ErrorsConfig{}
+	assert.NoError(t, cfg.InitDefaults())
+	assert.NoError(t, cfg.parseTemplates())
+
+	secret := roadrunner.AllocateTimeoutError{Timeout: time.Minute}
+
+	cases := []struct{ accept, contentType, body string }{
+		{"", "text/plain; charset=utf-8", "Service Unavailable"},
+		{"application/json", "application/json", `{"status":503,"message":"Service Unavailable"}`},
+		{"text/html,*/*", "text/html; charset=utf-8", "<h1>503 Service Unavailable</h1>"},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept", c.accept)
+		w := httptest.NewRecorder()
+
+		assert.NoError(t, cfg.write(w, r, http.StatusServiceUnavailable, secret))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, c.contentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), c.body)
+		assert.NotContains(t, w.Body.String(), "worker timeout")
+	}
+}
+
+func Test_Errors_Debug(t *testing.T) {
+	cfg := &ErrorsConfig{Debug: true, Format: ErrorFormatJSON}
+	assert.NoError(t, cfg.parseTemplates())
+
+	w := httptest.NewRecorder()
+	assert.NoError(t, cfg.write(w, httptest.NewRequest("GET", "/", nil), 500, errors.New(`"quoted" error`)))
+	assert.Equal(t, `{"status":500,"message":"Internal Server Error","error":"\"quoted\" error"}`, w.Body.String())
+
+	cfg = &ErrorsConfig{Debug: true, Format: ErrorFormatHTML}
+	assert.NoError(t, cfg.parseTemplates())
+
+	w = httptest.NewRecorder()
+	assert.NoError(t, cfg.write(w, httptest.NewRequest("GET", "/", nil), 500, errors.New("<script>")))
+	assert.Contains(t, w.Body.String(), "<pre>&lt;script&gt;</pre>")
+}
+
+func Test_Errors_NotHydrated(t *testing.T) {
+	var cfg *ErrorsConfig
+
+	w := httptest.NewRecorder()
+	assert.NoError(t, cfg.write(w, httptest.NewRequest("GET", "/", nil), 500, errors.New("secret")))
+	assert.Equal(t, 500, w.Code)
+	assert.Equal(t, "Internal Server Error", w.Body.String())
+}
+
+func Test_Errors_Templates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rr")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "error.html")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`<p>Oops, {{.Status}}</p>`), 0644))
+
+	c := &Config{}
+	assert.NoError(t, c.Hydrate(&mockCfg{fmt.Sprintf(`{
+		"address": ":8080",
+		"errors": {"format": "html", "templates": {"html": %q}}
+	}`, file)}))
+
+	w := httptest.NewRecorder()
+	assert.NoError(t, c.Errors.write(w, httptest.NewRequest("GET", "/", nil), 502, errors.New("secret")))
+	assert.Equal(t, "<p>Oops, 502</p>", w.Body.String())
+
+	for _, cfg := range []string{
+		`{"address": ":8080", "errors": {"format": "xml"}}`,
+		`{"address": ":8080", "errors": {"templates": {"xml": "error.xml"}}}`,
+		`{"address": ":8080", "errors": {"templates": {"html": "missing.html"}}}`,
+	} {
+		c := &Config{}
+		assert.Error(t, c.Hydrate(&mockCfg{cfg}), cfg)
+	}
+
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{{.Missing`), 0644))
+	c = &Config{}
+	assert.Error(t, c.Hydrate(&mockCfg{fmt.Sprintf(`{"address": ":8080", "errors": {"templates": {"html": %q}}}`, file)}))
+}
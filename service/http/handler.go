@@ -12,6 +12,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spiral/roadrunner"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -20,6 +22,17 @@ const (
 
 	// EventError thrown on any non job error provided by road runner server.
 	EventError
+
+	// EventTimeout thrown when request has not been served within the execution deadline. See ErrorEvent as payload.
+	EventTimeout
+)
+
+var (
+	// ErrExecTimeout is returned when worker has not served the request within the execution deadline.
+	ErrExecTimeout = errors.New("request execution timeout")
+
+	// ErrRequestTooLarge is returned when request body exceeds max request size.
+	ErrRequestTooLarge = errors.New("request body max size is exceeded")
 )
 
 // ErrorEvent represents singular http error event.
@@ -30,6 +43,15 @@ type ErrorEvent struct {
 	// Error - associated error, if any.
 	Error error
 
+	// Status of the error response sent to the client.
+	Status int
+
+	// Pool contains name of the worker pool which has served the request.
+	Pool string
+
+	// RequestID contains id assigned to the request.
+	RequestID string
+
 	// event timings
 	start   time.Time
 	elapsed time.Duration
@@ -48,6 +70,12 @@ type ResponseEvent struct {
 	// Response contains service response.
 	Response *Response
 
+	// Pool contains name of the worker pool which has served the request.
+	Pool string
+
+	// RequestID contains id assigned to the request.
+	RequestID string
+
 	// event timings
 	start   time.Time
 	elapsed time.Duration
@@ -61,11 +89,32 @@ func (e *ResponseEvent) Elapsed() time.Duration {
 // Handler serves http connections to underlying PHP application using PSR-7 protocol. Context will include request headers,
 // parsed files and query, payload will include parsed form dataTree (if any).
 type Handler struct {
-	cfg *Config
-	log *logrus.Logger
-	rr  *roadrunner.Server
-	mul sync.Mutex
-	lsn func(event int, ctx interface{})
+	mu   sync.Mutex
+	cfg  *Config
+	log  *logrus.Logger
+	rr   *roadrunner.Server
+	pool string
+	mul  sync.Mutex
+	lsn  func(event int, ctx interface{})
+
+	// tr traces request processing, no-op tracer when not set
+	tr trace.Tracer
+}
+
+// config returns current handler configuration.
+func (h *Handler) config() *Config {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.cfg
+}
+
+// setConfig changes handler configuration, requests in progress are served using previous configuration.
+func (h *Handler) setConfig(cfg *Config) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.cfg = cfg
 }
 
 // Listen attaches handler event controller.
@@ -79,80 +128,165 @@ func (h *Handler) Listen(l func(event int, ctx interface{})) {
 // mdwr serve using PSR-7 requests passed to underlying application. Attempts to serve static files first if enabled.
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
+	cfg := h.config()
 
 	// validating request size
-	if h.cfg.MaxRequestSize != 0 {
+	if maxSize := cfg.MaxRequestSizeOf(h.pool); maxSize != 0 {
 		if length := r.Header.Get("content-length"); length != "" {
 			if size, err := strconv.ParseInt(length, 10, 64); err != nil {
 				h.handleError(w, r, err, start)
 				return
-			} else if size > h.cfg.MaxRequestSize*1024*1024 {
-				h.handleError(w, r, errors.New("request body max size is exceeded"), start)
+			} else if size > maxSize*1024*1024 {
+				h.handleError(w, r, ErrRequestTooLarge, start)
 				return
 			}
 		}
 	}
 
-	req, err := NewRequest(r, h.cfg.Uploads)
+	ctx := r.Context()
+
+	_, span := h.tracer().Start(ctx, "rr.http.parse")
+	req, err := NewRequest(r, cfg.Uploads)
+	finishSpan(span, err)
 	if err != nil {
 		h.handleError(w, r, err, start)
 		return
 	}
 
 	// proxy IP resolution
-	h.resolveIP(req)
+	h.resolveIP(trustedBy(cfg, r), req)
 
+	info := serveInfoOf(r)
+	if info != nil {
+		info.RemoteAddr = req.RemoteAddr
+		info.Pool = h.poolName()
+	}
+
+	_, span = h.tracer().Start(ctx, "rr.http.uploads")
 	req.Open(h.log)
-	defer req.Close(h.log)
+	span.End()
 
+	injectTrace(ctx, req)
+
+	_, span = h.tracer().Start(ctx, "rr.http.payload")
 	p, err := req.Payload()
+	finishSpan(span, err)
 	if err != nil {
+		req.Close(h.log)
 		h.handleError(w, r, err, start)
 		return
 	}
+	p.Tag = RequestID(r)
+
+	execCtx, span := h.tracer().Start(ctx, "rr.pool.exec", trace.WithAttributes(attribute.String("rr.pool", h.poolName())))
+	if span.IsRecording() {
+		p.Timings = &roadrunner.Timings{}
+	}
+
+	// uploaded files are removed once worker completes the request, even if the client has already been responded
+	rsp, err := h.exec(p, cfg.Timeouts.execTimeout(), func() { req.Close(h.log) })
+
+	// timings are still being populated by the pool when request has not been served in time
+	if p.Timings != nil && err != ErrExecTimeout {
+		traceTimings(execCtx, h.tracer(), p.Timings, err)
+	}
+	finishSpan(span, err)
 
-	rsp, err := h.rr.Exec(p)
 	if err != nil {
 		h.handleError(w, r, err, start)
 		return
 	}
 
+	if info != nil {
+		info.Pid = rsp.Pid()
+	}
+
 	resp, err := NewResponse(rsp)
 	if err != nil {
 		h.handleError(w, r, err, start)
 		return
 	}
 
-	h.handleResponse(req, resp, start)
+	h.handleResponse(r, req, resp, start)
+
+	_, span = h.tracer().Start(ctx, "rr.http.write")
 	err = resp.Write(w)
+	finishSpan(span, err)
 	if err != nil {
 		h.handleError(w, r, err, start)
 	}
 }
 
+// exec sends payload to the worker pool and waits for the response no longer than given timeout (0 to wait
+// indefinitely). Done is called once worker has completed the request.
+func (h *Handler) exec(p *roadrunner.Payload, timeout time.Duration, done func()) (*roadrunner.Payload, error) {
+	if timeout == 0 {
+		defer done()
+		return h.rr.Exec(p)
+	}
+
+	type result struct {
+		rsp *roadrunner.Payload
+		err error
+	}
+
+	res := make(chan result, 1)
+	go func() {
+		defer done()
+
+		rsp, err := h.rr.Exec(p)
+		res <- result{rsp: rsp, err: err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-res:
+		return r.rsp, r.err
+	case <-timer.C:
+		return nil, ErrExecTimeout
+	}
+}
+
 // handleError sends error.
 func (h *Handler) handleError(w http.ResponseWriter, r *http.Request, err error, start time.Time) {
+	status := errorStatus(err)
+
 	// if pipe is broken, there is no sense to write the header
 	// in this case we just report about error
 	if err == errEPIPE {
-		h.throw(EventError, &ErrorEvent{Request: r, Error: err, start: start, elapsed: time.Since(start)})
+		h.throw(EventError, &ErrorEvent{Request: r, Error: err, Status: status, Pool: h.poolName(), RequestID: RequestID(r), start: start, elapsed: time.Since(start)})
 		return
 	}
-	// ResponseWriter is ok, write the error code
-	w.WriteHeader(500)
-	_, err2 := w.Write([]byte(err.Error()))
+	// ResponseWriter is ok, write the error response, original error is reported using event
+	err2 := h.config().Errors.write(w, r, status, err)
 	// error during the writing to the ResponseWriter
 	if err2 != nil {
 		// concat original error with ResponseWriter error
-		h.throw(EventError, &ErrorEvent{Request: r, Error: errors.New(fmt.Sprintf("error: %v, during handle this error, ResponseWriter error occurred: %v", err, err2)), start: start, elapsed: time.Since(start)})
+		h.throw(EventError, &ErrorEvent{Request: r, Error: errors.New(fmt.Sprintf("error: %v, during handle this error, ResponseWriter error occurred: %v", err, err2)), Status: status, Pool: h.poolName(), RequestID: RequestID(r), start: start, elapsed: time.Since(start)})
+		return
+	}
+
+	if err == ErrExecTimeout {
+		h.throw(EventTimeout, &ErrorEvent{Request: r, Error: err, Status: status, Pool: h.poolName(), RequestID: RequestID(r), start: start, elapsed: time.Since(start)})
 		return
 	}
-	h.throw(EventError, &ErrorEvent{Request: r, Error: err, start: start, elapsed: time.Since(start)})
+	h.throw(EventError, &ErrorEvent{Request: r, Error: err, Status: status, Pool: h.poolName(), RequestID: RequestID(r), start: start, elapsed: time.Since(start)})
 }
 
 // handleResponse triggers response event.
-func (h *Handler) handleResponse(req *Request, resp *Response, start time.Time) {
-	h.throw(EventResponse, &ResponseEvent{Request: req, Response: resp, start: start, elapsed: time.Since(start)})
+func (h *Handler) handleResponse(r *http.Request, req *Request, resp *Response, start time.Time) {
+	h.throw(EventResponse, &ResponseEvent{Request: req, Response: resp, Pool: h.poolName(), RequestID: RequestID(r), start: start, elapsed: time.Since(start)})
+}
+
+// poolName returns name of the worker pool serving the handler requests.
+func (h *Handler) poolName() string {
+	if h.pool == "" {
+		return DefaultPool
+	}
+
+	return h.pool
 }
 
 // throw invokes event handler if any.
@@ -165,25 +299,40 @@ func (h *Handler) throw(event int, ctx interface{}) {
 	}
 }
 
+// trustedBy returns function which checks if remote ip is allowed to set client ip using headers, subnets of the
+// listener which has received the request take precedence over the service subnets.
+func trustedBy(cfg *Config, r *http.Request) func(ip string) bool {
+	if l := listenerOf(r); l != nil && l.TrustedSubnets != nil {
+		return l.IsTrusted
+	}
+
+	return cfg.IsTrusted
+}
+
 // get real ip passing multiple proxy
-func (h *Handler) resolveIP(r *Request) {
-	if !h.cfg.IsTrusted(r.RemoteAddr) {
-		return
+func (h *Handler) resolveIP(trusted func(ip string) bool, r *Request) {
+	r.RemoteAddr = clientIP(trusted, r.RemoteAddr, r.Header)
+}
+
+// clientIP returns client ip provided by the proxy headers when remote ip is trusted, remote ip is returned
+// otherwise.
+func clientIP(trusted func(ip string) bool, remoteIP string, header http.Header) string {
+	if !trusted(remoteIP) {
+		return remoteIP
 	}
 
-	if r.Header.Get("X-Forwarded-For") != "" {
-		ips := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	if header.Get("X-Forwarded-For") != "" {
+		ips := strings.Split(header.Get("X-Forwarded-For"), ",")
 		ipCount := len(ips)
 
 		for i := ipCount - 1; i >= 0; i-- {
 			addr := strings.TrimSpace(ips[i])
 			if net.ParseIP(addr) != nil {
-				r.RemoteAddr = addr
-				return
+				return addr
 			}
 		}
 
-		return
+		return remoteIP
 	}
 
 	// The logic here is the following:
@@ -192,17 +341,39 @@ func (h *Handler) resolveIP(r *Request) {
 	// True-Client-IP is a general CF header in which copied information from X-Real-Ip in CF.
 	// CF-Connecting-IP is an Enterprise feature and we check it last in order.
 	// This operations are near O(1) because Headers struct are the map type -> type MIMEHeader map[string][]string
-	if r.Header.Get("X-Real-Ip") != "" {
-		r.RemoteAddr = fetchIP(r.Header.Get("X-Real-Ip"))
-		return
+	if header.Get("X-Real-Ip") != "" {
+		return fetchIP(header.Get("X-Real-Ip"))
 	}
 
-	if r.Header.Get("True-Client-IP") != "" {
-		r.RemoteAddr = fetchIP(r.Header.Get("True-Client-IP"))
-		return
+	if header.Get("True-Client-IP") != "" {
+		return fetchIP(header.Get("True-Client-IP"))
 	}
 
-	if r.Header.Get("CF-Connecting-IP") != "" {
-		r.RemoteAddr = fetchIP(r.Header.Get("CF-Connecting-IP"))
+	if header.Get("CF-Connecting-IP") != "" {
+		return fetchIP(header.Get("CF-Connecting-IP"))
 	}
+
+	return remoteIP
+}
+
+// errorStatus returns 503 when request was rejected or aborted because worker pool is being destroyed or has
+// no free workers, 504 when request has not been served in time, 413 for too large requests, 502 for malformed
+// worker responses and 500 for any other error.
+func errorStatus(err error) int {
+	switch cause := errors.Cause(err); cause {
+	case roadrunner.ErrPoolStopped, roadrunner.ErrExecAborted:
+		return http.StatusServiceUnavailable
+	case ErrExecTimeout:
+		return http.StatusGatewayTimeout
+	case ErrRequestTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case roadrunner.ErrMalformedResponse:
+		return http.StatusBadGateway
+	default:
+		if _, ok := cause.(roadrunner.AllocateTimeoutError); ok {
+			return http.StatusServiceUnavailable
+		}
+	}
+
+	return http.StatusInternalServerError
 }
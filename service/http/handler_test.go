@@ -1429,6 +1429,13 @@ func TestHandler_Error3(t *testing.T) {
 		}),
 	}
 
+	status := make(chan int, 1)
+	h.Listen(func(event int, ctx interface{}) {
+		if event == EventError {
+			status <- ctx.(*ErrorEvent).Status
+		}
+	})
+
 	assert.NoError(t, h.rr.Start())
 	defer h.rr.Stop()
 
@@ -1467,7 +1474,8 @@ func TestHandler_Error3(t *testing.T) {
 	}()
 
 	assert.NoError(t, err)
-	assert.Equal(t, 500, r.StatusCode)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, r.StatusCode)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, <-status)
 }
 
 func TestHandler_ResponseDuration(t *testing.T) {
@@ -1959,3 +1967,9 @@ func BenchmarkHandler_Listen_Echo(b *testing.B) {
 		}
 	}
 }
+
+func Test_Handler_ErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusServiceUnavailable, errorStatus(roadrunner.ErrPoolStopped))
+	assert.Equal(t, http.StatusServiceUnavailable, errorStatus(roadrunner.ErrExecAborted))
+	assert.Equal(t, http.StatusInternalServerError, errorStatus(roadrunner.JobError("error")))
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// ListenerConfig configures additional http server, all servers handle requests using the same middleware and
+// worker pools.
+type ListenerConfig struct {
+	// Address to listen on: "host:port", "tcp://host:port", "tcp4://host:port", "tcp6://host:port" or
+	// "unix://file.sock".
+	Address string
+
+	// SSL enables https on the listener when key and cert are set.
+	SSL ListenerSSLConfig
+
+	// H2C enables HTTP/2 over plain connections.
+	H2C bool
+
+	// TrustedSubnets overwrite subnets which are allowed to set ip using X-Real-Ip and X-Forwarded-For
+	// on this listener.
+	TrustedSubnets []string
+	cidrs          []*net.IPNet
+
+	// ProxyProtocol requires every connection to start with PROXY protocol (v1 or v2) header, client address
+	// provided by the header is used as request remote address.
+	ProxyProtocol bool
+
+	// ProxyTimeout defines for how long listener waits for the PROXY header, defaults to 10 seconds.
+	ProxyTimeout time.Duration
+}
+
+// ListenerSSLConfig defines https settings of the listener.
+type ListenerSSLConfig struct {
+	// Key defined private server key.
+	Key string
+
+	// Cert is https certificate.
+	Cert string
+
+	// Root CA file
+	RootCA string
+}
+
+// EnableTLS returns true if listener must accept TLS connections.
+func (c *ListenerConfig) EnableTLS() bool {
+	return c.SSL.Key != "" || c.SSL.Cert != ""
+}
+
+// Network returns network and address to listen on, addresses without network listen on tcp.
+func (c *ListenerConfig) Network() (string, string) {
+	for _, network := range []string{"unix", "tcp", "tcp4", "tcp6"} {
+		if strings.HasPrefix(c.Address, network+"://") {
+			return network, strings.TrimPrefix(c.Address, network+"://")
+		}
+	}
+
+	return "tcp", c.Address
+}
+
+// IsTrusted returns true if listener trusts X-Real-Ip and X-Forwarded-For headers sent from the given ip.
+func (c *ListenerConfig) IsTrusted(ip string) bool {
+	return isTrusted(c.cidrs, ip)
+}
+
+// hydrate parses listener values.
+func (c *ListenerConfig) hydrate() (err error) {
+	if c.ProxyTimeout == 0 {
+		c.ProxyTimeout = 10 * time.Second
+	} else if c.ProxyTimeout < time.Microsecond {
+		c.ProxyTimeout = time.Second * time.Duration(c.ProxyTimeout.Nanoseconds())
+	}
+
+	c.cidrs, err = parseCIDRs(c.TrustedSubnets)
+	return err
+}
+
+// Valid validates the configuration.
+func (c *ListenerConfig) Valid() error {
+	network, address := c.Network()
+	if address == "" {
+		return errors.New("listener address is missing")
+	}
+
+	if strings.Contains(address, "://") {
+		return fmt.Errorf("unsupported listener network `%s`", c.Address)
+	}
+
+	if network != "unix" && !strings.Contains(address, ":") {
+		return fmt.Errorf("malformed listener address `%s`", c.Address)
+	}
+
+	if !c.EnableTLS() {
+		return nil
+	}
+
+	if c.SSL.Key == "" || c.SSL.Cert == "" {
+		return fmt.Errorf("listener `%s`: both key and cert are required", c.Address)
+	}
+
+	for _, file := range []string{c.SSL.Key, c.SSL.Cert, c.SSL.RootCA} {
+		if file == "" {
+			continue
+		}
+
+		if _, err := os.Stat(file); err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("listener `%s`: file '%s' does not exists", c.Address, file)
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
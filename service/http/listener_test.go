@@ -0,0 +1,153 @@
+package http_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spiral/roadrunner/rrtest"
+	rrhttp "github.com/spiral/roadrunner/service/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// remoteWorker responds with request remote address.
+func remoteWorker() *rrtest.HTTP {
+	return rrtest.NewHTTP(rrtest.HTTPWorker(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.RemoteAddr))
+	})))
+}
+
+func Test_Listeners_Unix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rr")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "rr.sock")
+
+	h := remoteWorker()
+	h.Start(t, rrtest.Config{"http": `{
+		"workers": {"pool": {"numWorkers": 1}},
+		"listeners": [{"address": "unix://` + socket + `"}]
+	}`})
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return net.Dial("unix", socket)
+		},
+	}}
+
+	rsp, err := client.Get("http://unix/")
+	assert.NoError(t, err)
+	defer rsp.Body.Close()
+	assert.Equal(t, 200, rsp.StatusCode)
+
+	// main server is still running
+	r, _ := http.NewRequest("GET", h.URL("/"), nil)
+	assert.Equal(t, "127.0.0.1", get(t, r))
+}
+
+func Test_Listeners_TCP4(t *testing.T) {
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := ln.Addr().String()
+	assert.NoError(t, ln.Close())
+
+	h := remoteWorker()
+	h.Start(t, rrtest.Config{"http": `{
+		"workers": {"pool": {"numWorkers": 1}},
+		"listeners": [{"address": "tcp4://` + address + `"}]
+	}`})
+
+	r, _ := http.NewRequest("GET", "http://"+address+"/", nil)
+	assert.Equal(t, "127.0.0.1", get(t, r))
+}
+
+func Test_Listeners_ProxyProtocol(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := ln.Addr().String()
+	assert.NoError(t, ln.Close())
+
+	h := remoteWorker()
+	h.Start(t, rrtest.Config{"http": `{
+		"workers": {"pool": {"numWorkers": 1}},
+		"listeners": [{"address": "tcp://` + address + `", "proxyProtocol": true}]
+	}`})
+
+	conn, err := net.Dial("tcp", address)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 80\r\nGET / HTTP/1.0\r\n\r\n"))
+	assert.NoError(t, err)
+
+	rsp, err := ioutil.ReadAll(conn)
+	assert.NoError(t, err)
+	assert.Contains(t, string(rsp), "192.168.0.1")
+}
+
+func Test_Listeners_TrustedSubnets(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := ln.Addr().String()
+	assert.NoError(t, ln.Close())
+
+	h := remoteWorker()
+	h.Start(t, rrtest.Config{"http": `{
+		"workers": {"pool": {"numWorkers": 1}},
+		"listeners": [{"address": "` + address + `", "trustedSubnets": ["10.0.0.0/8"]}]
+	}`})
+
+	r, _ := http.NewRequest("GET", h.URL("/"), nil)
+	r.Header.Set("X-Real-Ip", "10.0.0.1")
+	assert.Equal(t, "10.0.0.1", get(t, r))
+
+	r, _ = http.NewRequest("GET", "http://"+address+"/", nil)
+	r.Header.Set("X-Real-Ip", "10.0.0.1")
+	assert.Equal(t, "127.0.0.1", get(t, r))
+}
+
+func Test_Listeners_Config(t *testing.T) {
+	cfg := &rrhttp.Config{}
+	assert.NoError(t, cfg.Hydrate(rrtest.Config{"http": `{
+		"listeners": [
+			{"address": "unix://rr.sock"},
+			{"address": "[::1]:8080", "h2c": true},
+			{"address": "tcp4://127.0.0.1:8080"},
+			{"address": "tcp6://[::1]:8080"}
+		]
+	}`}.Get("http")))
+
+	network, address := cfg.Listeners[0].Network()
+	assert.Equal(t, "unix", network)
+	assert.Equal(t, "rr.sock", address)
+
+	network, address = cfg.Listeners[1].Network()
+	assert.Equal(t, "tcp", network)
+	assert.Equal(t, "[::1]:8080", address)
+
+	network, address = cfg.Listeners[2].Network()
+	assert.Equal(t, "tcp4", network)
+	assert.Equal(t, "127.0.0.1:8080", address)
+
+	network, address = cfg.Listeners[3].Network()
+	assert.Equal(t, "tcp6", network)
+	assert.Equal(t, "[::1]:8080", address)
+
+	for _, c := range []string{
+		`{"listeners": [{"address": ""}]}`,
+		`{"listeners": [{"address": "localhost"}]}`,
+		`{"listeners": [{"address": "tcp4://localhost"}]}`,
+		`{"listeners": [{"address": "udp://:8080"}]}`,
+		`{"listeners": [{"address": ":8080", "ssl": {"key": "key.pem"}}]}`,
+		`{"listeners": [{"address": ":8080", "ssl": {"key": "missing.key", "cert": "missing.crt"}}]}`,
+		`{"listeners": [{"address": ":8080", "trustedSubnets": ["invalid"]}]}`,
+	} {
+		cfg := &rrhttp.Config{}
+		assert.Error(t, cfg.Hydrate(rrtest.Config{"http": c}.Get("http")), c)
+	}
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/spiral/roadrunner"
+	"github.com/spiral/roadrunner/service"
+)
+
+// DefaultPool is name of the pool configured by the http workers section.
+const DefaultPool = "default"
+
+// PoolConfig configures named worker pool.
+type PoolConfig struct {
+	// MaxRequestSize overwrites max payload size in megabytes for the requests served by the pool, 0 to inherit
+	// http setting.
+	MaxRequestSize int64
+
+	// Workers configures rr server and worker pool.
+	Workers *roadrunner.ServerConfig
+}
+
+// Hydrate must populate Config values using given Config source. Must return error if Config is not valid.
+func (cfg *PoolConfig) Hydrate(c service.Config) error {
+	cfg.Workers = &roadrunner.ServerConfig{}
+	if err := cfg.Workers.InitDefaults(); err != nil {
+		return err
+	}
+
+	if err := c.Unmarshal(cfg); err != nil {
+		return err
+	}
+
+	cfg.Workers.UpscaleDurations()
+
+	return cfg.Valid()
+}
+
+// Valid validates the configuration.
+func (cfg *PoolConfig) Valid() error {
+	if cfg.Workers == nil {
+		return errors.New("malformed workers config")
+	}
+
+	if cfg.Workers.Pool == nil {
+		return errors.New("malformed workers config (pool config is missing)")
+	}
+
+	return cfg.Workers.Pool.Valid()
+}
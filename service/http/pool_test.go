@@ -0,0 +1,189 @@
+package http_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spiral/roadrunner"
+	"github.com/spiral/roadrunner/rrtest"
+	"github.com/spiral/roadrunner/service"
+	rrhttp "github.com/spiral/roadrunner/service/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// poolWorkers creates http service workers which respond with the name of their pool.
+func poolWorkers(h *rrtest.HTTP) {
+	h.Service.ProduceFactory(func(cfg *roadrunner.ServerConfig) (roadrunner.Factory, error) {
+		pool := ""
+		for _, v := range cfg.GetEnv() {
+			if strings.HasPrefix(v, "RR_HTTP_POOL=") {
+				pool = strings.TrimPrefix(v, "RR_HTTP_POOL=")
+			}
+		}
+
+		return roadrunner.NewInProcessFactory(rrtest.HTTPWorker(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(pool))
+			},
+		))), nil
+	})
+}
+
+func get(t *testing.T, r *http.Request) string {
+	rsp, err := http.DefaultClient.Do(r)
+	assert.NoError(t, err)
+	defer rsp.Body.Close()
+
+	b, err := ioutil.ReadAll(rsp.Body)
+	assert.NoError(t, err)
+
+	return string(b)
+}
+
+func Test_Pools_Routes(t *testing.T) {
+	h := rrtest.NewHTTP(nil)
+	poolWorkers(h)
+
+	h.Start(t, rrtest.Config{"http": `{
+		"workers": {"pool": {"numWorkers": 1}},
+		"pools": {
+			"reports": {"workers": {"pool": {"numWorkers": 2}}},
+			"admin": {"workers": {"pool": {"numWorkers": 1}}}
+		},
+		"routes": [
+			{"pool": "default", "path": "/reports/fast"},
+			{"pool": "reports", "path": "/reports"},
+			{"pool": "admin", "regex": "^/admin/[0-9]+$", "methods": ["POST"]},
+			{"pool": "admin", "headers": {"X-Admin": "yes"}}
+		]
+	}`})
+
+	r, _ := http.NewRequest("GET", h.URL("/"), nil)
+	assert.Equal(t, "default", get(t, r))
+
+	r, _ = http.NewRequest("GET", h.URL("/reports/monthly"), nil)
+	assert.Equal(t, "reports", get(t, r))
+
+	r, _ = http.NewRequest("GET", h.URL("/reports/fast"), nil)
+	assert.Equal(t, "default", get(t, r))
+
+	r, _ = http.NewRequest("POST", h.URL("/admin/1"), nil)
+	assert.Equal(t, "admin", get(t, r))
+
+	r, _ = http.NewRequest("GET", h.URL("/admin/1"), nil)
+	assert.Equal(t, "default", get(t, r))
+
+	r, _ = http.NewRequest("GET", h.URL("/"), nil)
+	r.Header.Set("X-Admin", "yes")
+	assert.Equal(t, "admin", get(t, r))
+
+	assert.Equal(t, []string{"default", "admin", "reports"}, h.Service.Pools())
+	assert.Len(t, h.Service.Pool("default").Workers(), 1)
+	assert.Len(t, h.Service.Pool("reports").Workers(), 2)
+	assert.Nil(t, h.Service.Pool("undefined"))
+}
+
+func Test_Pools_Events(t *testing.T) {
+	h := rrtest.NewHTTP(nil)
+	poolWorkers(h)
+
+	pools := make(chan string, 1)
+	h.Service.AddListener(func(event int, ctx interface{}) {
+		if event == rrhttp.EventResponse {
+			pools <- ctx.(*rrhttp.ResponseEvent).Pool
+		}
+	})
+
+	h.Start(t, rrtest.Config{"http": `{
+		"workers": {"pool": {"numWorkers": 1}},
+		"pools": {"reports": {"workers": {"pool": {"numWorkers": 1}}}},
+		"routes": [{"pool": "reports", "path": "/reports"}]
+	}`})
+
+	r, _ := http.NewRequest("GET", h.URL("/reports"), nil)
+	get(t, r)
+	assert.Equal(t, "reports", <-pools)
+
+	r, _ = http.NewRequest("GET", h.URL("/"), nil)
+	get(t, r)
+	assert.Equal(t, "default", <-pools)
+}
+
+func Test_Pools_MaxRequestSize(t *testing.T) {
+	h := rrtest.NewHTTP(nil)
+	poolWorkers(h)
+
+	h.Start(t, rrtest.Config{"http": `{
+		"maxRequestSize": 1,
+		"workers": {"pool": {"numWorkers": 1}},
+		"pools": {"uploads": {"maxRequestSize": 10, "workers": {"pool": {"numWorkers": 1}}}},
+		"routes": [{"pool": "uploads", "path": "/uploads"}]
+	}`})
+
+	body := strings.Repeat("a", 2*1024*1024)
+
+	rsp, err := http.Post(h.URL("/"), "text/plain", strings.NewReader(body))
+	assert.NoError(t, err)
+	rsp.Body.Close()
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rsp.StatusCode)
+
+	r, _ := http.NewRequest("POST", h.URL("/uploads"), strings.NewReader(body))
+	assert.Equal(t, "uploads", get(t, r))
+}
+
+func Test_Pools_Config(t *testing.T) {
+	cfg := &rrhttp.Config{}
+	assert.NoError(t, cfg.Hydrate(rrtest.Config{"http": `{
+		"address": ":8080",
+		"pools": {"reports": {"workers": {"command": "php reports.php", "pool": {"numWorkers": 2}}}}
+	}`}.Get("http")))
+
+	assert.Equal(t, "php reports.php", cfg.Pools["reports"].Workers.Command)
+	assert.Equal(t, "pipes", cfg.Pools["reports"].Workers.Relay)
+	assert.Equal(t, int64(2), cfg.Pools["reports"].Workers.Pool.NumWorkers)
+	assert.NotZero(t, cfg.Pools["reports"].Workers.Pool.AllocateTimeout)
+
+	for _, c := range []string{
+		`{"address": ":8080", "pools": {"default": {}}}`,
+		`{"address": ":8080", "routes": [{"path": "/"}]}`,
+		`{"address": ":8080", "routes": [{"pool": "undefined", "path": "/"}]}`,
+		`{"address": ":8080", "pools": {"reports": {}}, "routes": [{"pool": "reports", "regex": "("}]}`,
+	} {
+		cfg := &rrhttp.Config{}
+		assert.Error(t, cfg.Hydrate(rrtest.Config{"http": c}.Get("http")), c)
+	}
+}
+
+func Test_Pools_Reconfigure(t *testing.T) {
+	h := rrtest.NewHTTP(nil)
+	poolWorkers(h)
+
+	h.Start(t, rrtest.Config{"http": `{
+		"workers": {"pool": {"numWorkers": 1}},
+		"pools": {"reports": {"workers": {"pool": {"numWorkers": 1}}}}
+	}`})
+
+	cfg := &rrhttp.Config{}
+	assert.NoError(t, cfg.Hydrate(rrtest.Config{"http": `{
+		"address": "` + h.Address + `",
+		"workers": {"pool": {"numWorkers": 1}},
+		"pools": {"reports": {"workers": {"pool": {"numWorkers": 3}}}},
+		"routes": [{"pool": "reports", "path": "/reports"}]
+	}`}.Get("http")))
+
+	assert.NoError(t, h.Service.Reconfigure(cfg))
+	assert.Len(t, h.Service.Pool("reports").Workers(), 3)
+
+	r, _ := http.NewRequest("GET", h.URL("/reports"), nil)
+	assert.Equal(t, "reports", get(t, r))
+
+	cfg = &rrhttp.Config{}
+	assert.NoError(t, cfg.Hydrate(rrtest.Config{"http": `{
+		"address": "` + h.Address + `",
+		"workers": {"pool": {"numWorkers": 1}}
+	}`}.Get("http")))
+
+	assert.Equal(t, service.ErrRestartRequired, h.Service.Reconfigure(cfg))
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// maxRequestIDLength limits length of the request id accepted from the client.
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestIDConfig configures request id assigned to every request.
+type RequestIDConfig struct {
+	// Header contains request id provided by the client or proxy, new request id is generated when header is
+	// missing or invalid. Request id is sent back using the same response header. Defaults to X-Request-Id.
+	Header string
+
+	// Attribute is the name of PSR-7 request attribute containing request id, defaults to "requestId".
+	Attribute string
+}
+
+// InitDefaults sets missing values to their default values.
+func (cfg *RequestIDConfig) InitDefaults() error {
+	cfg.Header = "X-Request-Id"
+	cfg.Attribute = "requestId"
+
+	return nil
+}
+
+// header returns name of the request id header.
+func (cfg *RequestIDConfig) header() string {
+	if cfg == nil || cfg.Header == "" {
+		return "X-Request-Id"
+	}
+
+	return cfg.Header
+}
+
+// attribute returns name of the request id attribute.
+func (cfg *RequestIDConfig) attribute() string {
+	if cfg == nil || cfg.Attribute == "" {
+		return "requestId"
+	}
+
+	return cfg.Attribute
+}
+
+// assign returns request carrying request id received from the client or generated one, request id is copied into
+// the request header (replacing invalid value) and the response header.
+func (cfg *RequestIDConfig) assign(w http.ResponseWriter, r *http.Request) *http.Request {
+	header := cfg.header()
+
+	id := r.Header.Get(header)
+	if !validRequestID(id) {
+		id = newRequestID()
+		r.Header.Set(header, id)
+	}
+
+	w.Header().Set(header, id)
+
+	return r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id))
+}
+
+// RequestID returns id assigned to the request by the http service, empty if request has not been served by
+// the service.
+func RequestID(r *http.Request) string {
+	id, _ := r.Context().Value(requestIDKey{}).(string)
+	return id
+}
+
+// validRequestID returns true if request id received from the client is safe to be logged and passed to the
+// workers.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == '/', c == '+', c == '=', c == '@':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// newRequestID generates random (version 4) UUID.
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,90 @@
+package http_test
+
+import (
+	"net/http"
+	"testing"
+
+	json "github.com/json-iterator/go"
+	"github.com/spiral/roadrunner"
+	"github.com/spiral/roadrunner/rrtest"
+	rrhttp "github.com/spiral/roadrunner/service/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// attributeWorker responds with the request id attribute.
+func attributeWorker(attribute string) *rrtest.HTTP {
+	return rrtest.NewHTTP(func(rqs *roadrunner.Payload) (*roadrunner.Payload, error) {
+		ctx := struct {
+			Attributes map[string]interface{} `json:"attributes"`
+		}{}
+
+		j := json.ConfigCompatibleWithStandardLibrary
+		if err := j.Unmarshal(rqs.Context, &ctx); err != nil {
+			return nil, err
+		}
+
+		id, _ := ctx.Attributes[attribute].(string)
+		return &roadrunner.Payload{
+			Context: []byte(`{"status": 200, "headers": {}}`),
+			Body:    []byte(id),
+		}, nil
+	})
+}
+
+func Test_RequestID_Generated(t *testing.T) {
+	h := attributeWorker("requestId")
+
+	events := make(chan *rrhttp.ResponseEvent, 2)
+	h.Service.AddListener(func(event int, ctx interface{}) {
+		if event == rrhttp.EventResponse {
+			events <- ctx.(*rrhttp.ResponseEvent)
+		}
+	})
+
+	h.Start(t, rrtest.Config{"http": `{"workers": {"pool": {"numWorkers": 1}}}`})
+
+	rsp, err := http.Get(h.URL("/"))
+	assert.NoError(t, err)
+	defer rsp.Body.Close()
+
+	id := rsp.Header.Get("X-Request-Id")
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id)
+
+	r, _ := http.NewRequest("GET", h.URL("/"), nil)
+	r.Header.Set("X-Request-Id", id)
+	assert.Equal(t, id, get(t, r))
+
+	<-events
+	e := <-events
+	assert.Equal(t, id, e.RequestID)
+	assert.Equal(t, id, e.Request.Header.Get("X-Request-Id"))
+}
+
+func Test_RequestID_Incoming(t *testing.T) {
+	h := attributeWorker("traceId")
+	h.Start(t, rrtest.Config{"http": `{
+		"workers": {"pool": {"numWorkers": 1}},
+		"requestId": {"header": "X-Trace-Id", "attribute": "traceId"}
+	}`})
+
+	r, _ := http.NewRequest("GET", h.URL("/"), nil)
+	r.Header.Set("X-Trace-Id", "proxy-generated:42")
+
+	rsp, err := http.DefaultClient.Do(r)
+	assert.NoError(t, err)
+	defer rsp.Body.Close()
+	assert.Equal(t, "proxy-generated:42", rsp.Header.Get("X-Trace-Id"))
+
+	r, _ = http.NewRequest("GET", h.URL("/"), nil)
+	r.Header.Set("X-Trace-Id", "proxy-generated:42")
+	assert.Equal(t, "proxy-generated:42", get(t, r))
+
+	// unsafe ids are replaced
+	r, _ = http.NewRequest("GET", h.URL("/"), nil)
+	r.Header.Set("X-Trace-Id", "<script>")
+
+	rsp, err = http.DefaultClient.Do(r)
+	assert.NoError(t, err)
+	defer rsp.Body.Close()
+	assert.Len(t, rsp.Header.Get("X-Trace-Id"), 36)
+}
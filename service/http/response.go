@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	json "github.com/json-iterator/go"
+	"github.com/pkg/errors"
 
 	"github.com/spiral/roadrunner"
 )
@@ -28,7 +29,7 @@ func NewResponse(p *roadrunner.Payload) (*Response, error) {
 	r := &Response{body: p.Body}
 	j := json.ConfigCompatibleWithStandardLibrary
 	if err := j.Unmarshal(p.Context, r); err != nil {
-		return nil, err
+		return nil, errors.Wrapf(roadrunner.ErrMalformedResponse, "invalid response context (%s)", err)
 	}
 
 	return r, nil
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// RouteConfig directs matching requests to the named worker pool. Request must match all of the specified
+// conditions.
+type RouteConfig struct {
+	// Pool serving the matched requests, use "default" to direct requests to the default pool.
+	Pool string
+
+	// Host matches request host without port, "*.domain.com" matches any subdomain.
+	Host string
+
+	// Path matches request path prefix.
+	Path string
+
+	// Regex matches request path using regular expression.
+	Regex string
+	regex *regexp.Regexp
+
+	// Methods matches request methods.
+	Methods []string
+
+	// Headers matches request header values.
+	Headers map[string]string
+}
+
+// Compile prepares route regular expression, routes declared outside of the http service config must be compiled
+// before matching.
+func (cfg *RouteConfig) Compile() (err error) {
+	if cfg.Regex != "" {
+		cfg.regex, err = regexp.Compile(cfg.Regex)
+	}
+
+	return err
+}
+
+// Match returns true if request matches the route.
+func (cfg *RouteConfig) Match(r *http.Request) bool {
+	if cfg.Host != "" && !cfg.matchHost(r.Host) {
+		return false
+	}
+
+	if cfg.Path != "" && !strings.HasPrefix(r.URL.Path, cfg.Path) {
+		return false
+	}
+
+	if cfg.regex != nil && !cfg.regex.MatchString(r.URL.Path) {
+		return false
+	}
+
+	if len(cfg.Methods) != 0 && !cfg.matchMethod(r.Method) {
+		return false
+	}
+
+	for k, v := range cfg.Headers {
+		if r.Header.Get(k) != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchHost returns true if request host matches the route host.
+func (cfg *RouteConfig) matchHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	host = strings.ToLower(host)
+	pattern := strings.ToLower(cfg.Host)
+
+	if strings.HasPrefix(pattern, "*.") {
+		return strings.HasSuffix(host, pattern[1:])
+	}
+
+	return host == pattern
+}
+
+// matchMethod returns true if request method is one of the route methods.
+func (cfg *RouteConfig) matchMethod(method string) bool {
+	for _, m := range cfg.Methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+
+	return false
+}
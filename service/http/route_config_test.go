@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Route_Match(t *testing.T) {
+	route := &RouteConfig{
+		Pool:    "api",
+		Host:    "*.example.com",
+		Path:    "/api",
+		Regex:   "^/api/v[0-9]+/",
+		Methods: []string{"get", "POST"},
+		Headers: map[string]string{"X-Api": "1"},
+	}
+	assert.NoError(t, route.Compile())
+
+	r, _ := http.NewRequest("GET", "http://api.example.com:8080/api/v1/users", nil)
+	r.Header.Set("X-Api", "1")
+	assert.True(t, route.Match(r))
+
+	r.Method = "DELETE"
+	assert.False(t, route.Match(r))
+
+	r, _ = http.NewRequest("GET", "http://example.org/api/v1/users", nil)
+	r.Header.Set("X-Api", "1")
+	assert.False(t, route.Match(r))
+
+	r, _ = http.NewRequest("GET", "http://api.example.com/api/users", nil)
+	r.Header.Set("X-Api", "1")
+	assert.False(t, route.Match(r))
+
+	r, _ = http.NewRequest("GET", "http://api.example.com/api/v1/users", nil)
+	assert.False(t, route.Match(r))
+}
+
+func Test_Config_Route(t *testing.T) {
+	cfg := &Config{Routes: []*RouteConfig{
+		{Pool: "admin", Host: "admin.example.com"},
+		{Pool: "reports", Path: "/reports"},
+	}}
+
+	r, _ := http.NewRequest("GET", "http://admin.example.com/reports", nil)
+	assert.Equal(t, "admin", cfg.Route(r))
+
+	r, _ = http.NewRequest("GET", "http://example.com/reports", nil)
+	assert.Equal(t, "reports", cfg.Route(r))
+
+	r, _ = http.NewRequest("GET", "http://example.com/", nil)
+	assert.Equal(t, DefaultPool, cfg.Route(r))
+}
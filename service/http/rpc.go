@@ -1,7 +1,10 @@
 package http
 
 import (
+	"fmt"
+
 	"github.com/pkg/errors"
+	"github.com/spiral/roadrunner"
 	"github.com/spiral/roadrunner/util"
 )
 
@@ -23,6 +26,31 @@ func (rpc *rpcServer) Reset(reset bool, r *string) error {
 	return rpc.svc.Server().Reset()
 }
 
+// ResetPool resets the named worker pool and restarts all of it's workers.
+func (rpc *rpcServer) ResetPool(pool string, r *string) error {
+	srv, err := rpc.pool(pool)
+	if err != nil {
+		return err
+	}
+
+	*r = "OK"
+	return srv.Reset()
+}
+
+// Resize changes number of workers in the underlying RR worker pool without restarting active workers.
+func (rpc *rpcServer) Resize(numWorkers int64, r *string) error {
+	if rpc.svc == nil || rpc.svc.handler == nil {
+		return errors.New("http server is not running")
+	}
+
+	if err := rpc.svc.Server().Resize(numWorkers); err != nil {
+		return err
+	}
+
+	*r = "OK"
+	return nil
+}
+
 // Workers returns list of active workers and their stats.
 func (rpc *rpcServer) Workers(list bool, r *WorkerList) (err error) {
 	if rpc.svc == nil || rpc.svc.handler == nil {
@@ -32,3 +60,38 @@ func (rpc *rpcServer) Workers(list bool, r *WorkerList) (err error) {
 	r.Workers, err = util.ServerState(rpc.svc.Server())
 	return err
 }
+
+// PoolWorkers returns list of active workers of the named worker pool and their stats.
+func (rpc *rpcServer) PoolWorkers(pool string, r *WorkerList) error {
+	srv, err := rpc.pool(pool)
+	if err != nil {
+		return err
+	}
+
+	r.Workers, err = util.ServerState(srv)
+	return err
+}
+
+// Pools returns names of all configured worker pools.
+func (rpc *rpcServer) Pools(list bool, r *[]string) error {
+	if rpc.svc == nil || rpc.svc.handler == nil {
+		return errors.New("http server is not running")
+	}
+
+	*r = rpc.svc.Pools()
+	return nil
+}
+
+// pool returns rr server of the named worker pool.
+func (rpc *rpcServer) pool(name string) (*roadrunner.Server, error) {
+	if rpc.svc == nil || rpc.svc.handler == nil {
+		return nil, errors.New("http server is not running")
+	}
+
+	srv := rpc.svc.Pool(name)
+	if srv == nil {
+		return nil, fmt.Errorf("undefined pool `%s`", name)
+	}
+
+	return srv, nil
+}
@@ -213,9 +213,68 @@ func Test_Workers(t *testing.T) {
 	c.Stop()
 }
 
+func Test_Resize(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	c := service.NewContainer(logger)
+	c.Register(rpc.ID, &rpc.Service{})
+	c.Register(ID, &Service{})
+
+	assert.NoError(t, c.Init(&testCfg{
+		rpcCfg: `{"enable":true, "listen":"tcp://:5006"}`,
+		httpCfg: `{
+			"enable": true,
+			"address": ":6034",
+			"maxRequestSize": 1024,
+			"uploads": {
+				"dir": ` + tmpDir() + `,
+				"forbid": []
+			},
+			"workers":{
+				"command": "php ../../tests/http/client.php pid pipes",
+				"relay": "pipes",
+				"pool": {
+					"numWorkers": 1,
+					"allocateTimeout": 10000000,
+					"destroyTimeout": 10000000
+				}
+			}
+	}`}))
+
+	s, _ := c.Get(ID)
+	ss := s.(*Service)
+
+	s2, _ := c.Get(rpc.ID)
+	rs := s2.(*rpc.Service)
+
+	go func() {
+		err := c.Serve()
+		if err != nil {
+			t.Errorf("error during the Serve: error %v", err)
+		}
+	}()
+	time.Sleep(time.Millisecond * 500)
+	defer c.Stop()
+
+	cl, err := rs.Client()
+	assert.NoError(t, err)
+
+	pid := *ss.rr.Workers()[0].Pid
+
+	r := ""
+	assert.NoError(t, cl.Call("http.Resize", int64(3), &r))
+	assert.Equal(t, "OK", r)
+	assert.Len(t, ss.rr.Workers(), 3)
+	assert.Equal(t, pid, *ss.rr.Workers()[0].Pid)
+
+	assert.Error(t, cl.Call("http.Resize", int64(0), &r))
+}
+
 func Test_Errors(t *testing.T) {
 	r := &rpcServer{nil}
 
 	assert.Error(t, r.Reset(true, nil))
+	assert.Error(t, r.Resize(1, nil))
 	assert.Error(t, r.Workers(true, nil))
 }
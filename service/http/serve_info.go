@@ -0,0 +1,34 @@
+package http
+
+import (
+	"context"
+	"net/http"
+)
+
+type serveInfoKey struct{}
+
+// ServeInfo collects details of the request execution by the worker pool, fields stay empty if request has not
+// reached the workers (served by middleware or rejected before execution).
+type ServeInfo struct {
+	// RemoteAddr contains client ip resolved using trusted proxy headers.
+	RemoteAddr string
+
+	// Pool contains name of the worker pool which has served the request.
+	Pool string
+
+	// Pid of the worker which has served the request, 0 if worker has failed to respond.
+	Pid int
+}
+
+// WithServeInfo returns request which collects execution details into returned ServeInfo, info must only be read
+// once request has been served.
+func WithServeInfo(r *http.Request) (*http.Request, *ServeInfo) {
+	info := &ServeInfo{}
+	return r.WithContext(context.WithValue(r.Context(), serveInfoKey{}, info)), info
+}
+
+// serveInfoOf returns ServeInfo attached to the request, nil if none.
+func serveInfoOf(r *http.Request) *ServeInfo {
+	info, _ := r.Context().Value(serveInfoKey{}).(*ServeInfo)
+	return info
+}
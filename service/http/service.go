@@ -7,18 +7,23 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/fcgi"
 	"net/url"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spiral/roadrunner"
+	"github.com/spiral/roadrunner/service"
 	"github.com/spiral/roadrunner/service/env"
 	"github.com/spiral/roadrunner/service/http/attributes"
 	"github.com/spiral/roadrunner/service/rpc"
 	"github.com/spiral/roadrunner/util"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 	"golang.org/x/sys/cpu"
@@ -34,9 +39,21 @@ const (
 
 var couldNotAppendPemError = errors.New("could not append Certs from PEM")
 
+// errNotStarted returned by Ready when service has stopped before becoming ready.
+var errNotStarted = errors.New("service has stopped before becoming ready")
+
 // http middleware type.
 type middleware func(f http.HandlerFunc) http.HandlerFunc
 
+// listenerKey is request context key of the additional listener config.
+type listenerKey struct{}
+
+// listener is additional http server.
+type listener struct {
+	cfg *ListenerConfig
+	srv *http.Server
+}
+
 // Service manages rr, http servers.
 type Service struct {
 	sync.Mutex
@@ -45,22 +62,74 @@ type Service struct {
 	cfg   *Config
 	log   *logrus.Logger
 	cprod roadrunner.CommandProducer
+	fprod roadrunner.FactoryProducer
 	env   env.Environment
 	lsns  []func(event int, ctx interface{})
 	mdwr  []middleware
+	tp    trace.TracerProvider
 
 	rr         *roadrunner.Server
 	controller roadrunner.Controller
 	handler    *Handler
 
+	// named worker pools and their own controllers
+	pools       map[string]*Handler
+	controllers map[string]roadrunner.Controller
+
 	http  *http.Server
 	https *http.Server
 	fcgi  *http.Server
+
+	// additional servers configured by listeners
+	listeners []*listener
+
+	// readiness of the current run of the service
+	ready *readiness
 }
 
-// Attach attaches controller. Currently only one controller is supported.
+// readiness is signalled once workers are started and all servers are listening or service has failed to start.
+type readiness struct {
+	once sync.Once
+	done chan struct{}
+	err  error
+}
+
+// Attach attaches controller. Currently only one controller is supported, controller of the running server is
+// replaced. Controller is attached to all worker pools which have no own controller.
 func (s *Service) Attach(w roadrunner.Controller) {
+	s.Lock()
+	defer s.Unlock()
+
 	s.controller = w
+	if s.rr != nil {
+		s.rr.Attach(w)
+	}
+
+	for name, h := range s.pools {
+		if _, ok := s.controllers[name]; !ok {
+			h.rr.Attach(w)
+		}
+	}
+}
+
+// AttachPool attaches controller to the named worker pool, controller of the running pool is replaced.
+func (s *Service) AttachPool(pool string, w roadrunner.Controller) {
+	if pool == DefaultPool {
+		s.Attach(w)
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.controllers == nil {
+		s.controllers = make(map[string]roadrunner.Controller)
+	}
+	s.controllers[pool] = w
+
+	if h, ok := s.pools[pool]; ok {
+		h.rr.Attach(w)
+	}
 }
 
 // ProduceCommands changes the default command generator method
@@ -68,6 +137,11 @@ func (s *Service) ProduceCommands(producer roadrunner.CommandProducer) {
 	s.cprod = producer
 }
 
+// ProduceFactory changes the default worker factory, relay configuration is ignored when set.
+func (s *Service) ProduceFactory(producer roadrunner.FactoryProducer) {
+	s.fprod = producer
+}
+
 // AddMiddleware adds new net/http mdwr.
 func (s *Service) AddMiddleware(m middleware) {
 	s.mdwr = append(s.mdwr, m)
@@ -84,6 +158,7 @@ func (s *Service) Init(cfg *Config, r *rpc.Service, e env.Environment, log *logr
 	s.cfg = cfg
 	s.log = log
 	s.env = e
+	s.ready = &readiness{done: make(chan struct{})}
 
 	if r != nil {
 		if err := r.Register(ID, &rpcServer{s}); err != nil {
@@ -91,25 +166,78 @@ func (s *Service) Init(cfg *Config, r *rpc.Service, e env.Environment, log *logr
 		}
 	}
 
-	if !cfg.EnableHTTP() && !cfg.EnableTLS() && !cfg.EnableFCGI() {
+	if !cfg.EnableHTTP() && !cfg.EnableTLS() && !cfg.EnableFCGI() && len(cfg.Listeners) == 0 {
 		return false, nil
 	}
 
 	return true, nil
 }
 
-// Serve serves the svc.
-func (s *Service) Serve() error {
+// Reconfigure applies new config to the service. Worker pool and request handling options are changed without
+// restart, changes of the listeners require restart.
+func (s *Service) Reconfigure(cfg service.HydrateConfig) error {
+	c, ok := cfg.(*Config)
+	if !ok {
+		return fmt.Errorf("invalid config type %T", cfg)
+	}
+
 	s.Lock()
+	defer s.Unlock()
 
-	if s.env != nil {
-		if err := s.env.Copy(s.cfg.Workers); err != nil {
-			return nil
+	if !s.cfg.sameListeners(c) || !s.cfg.samePools(c) {
+		return service.ErrRestartRequired
+	}
+
+	if s.rr == nil {
+		s.cfg = c
+		return nil
+	}
+
+	if err := s.configureWorkers(c.Workers, DefaultPool); err != nil {
+		return err
+	}
+
+	if err := s.rr.Reconfigure(c.Workers); err != nil {
+		return err
+	}
+
+	for name, h := range s.pools {
+		if err := s.configureWorkers(c.Pools[name].Workers, name); err != nil {
+			return err
 		}
+
+		if err := h.rr.Reconfigure(c.Pools[name].Workers); err != nil {
+			return fmt.Errorf("pool `%s`: %s", name, err)
+		}
+	}
+
+	s.cfg = c
+	s.handler.setConfig(c)
+	for _, h := range s.pools {
+		h.setConfig(c)
 	}
 
-	s.cfg.Workers.CommandProducer = s.cprod
-	s.cfg.Workers.SetEnv("RR_HTTP", "true")
+	return nil
+}
+
+// Serve serves the svc.
+func (s *Service) Serve() error {
+	s.Lock()
+
+	// every run signals readiness, restarted service waits for the new run
+	ready := s.ready
+	defer func() {
+		ready.set(errNotStarted)
+
+		s.Lock()
+		s.ready = &readiness{done: make(chan struct{})}
+		s.Unlock()
+	}()
+
+	if err := s.configureWorkers(s.cfg.Workers, DefaultPool); err != nil {
+		s.Unlock()
+		return err
+	}
 
 	s.rr = roadrunner.NewServer(s.cfg.Workers)
 	s.rr.Listen(s.throw)
@@ -118,9 +246,29 @@ func (s *Service) Serve() error {
 		s.rr.Attach(s.controller)
 	}
 
-	s.handler = &Handler{cfg: s.cfg, rr: s.rr}
+	s.handler = &Handler{cfg: s.cfg, rr: s.rr, tr: s.tracer()}
 	s.handler.Listen(s.throw)
 
+	s.pools = make(map[string]*Handler, len(s.cfg.Pools))
+	for name, p := range s.cfg.Pools {
+		if err := s.configureWorkers(p.Workers, name); err != nil {
+			s.Unlock()
+			return err
+		}
+
+		rr := roadrunner.NewServer(p.Workers)
+		rr.Listen(s.throw)
+
+		if c, ok := s.controllers[name]; ok {
+			rr.Attach(c)
+		} else if s.controller != nil {
+			rr.Attach(s.controller)
+		}
+
+		s.pools[name] = &Handler{cfg: s.cfg, rr: rr, pool: name, tr: s.tracer()}
+		s.pools[name].Listen(s.throw)
+	}
+
 	if s.cfg.EnableHTTP() {
 		if s.cfg.EnableH2C() {
 			s.http = &http.Server{Addr: s.cfg.Address, Handler: h2c.NewHandler(s, &http2.Server{})}
@@ -134,12 +282,14 @@ func (s *Service) Serve() error {
 		if s.cfg.SSL.RootCA != "" {
 			err := s.appendRootCa()
 			if err != nil {
+				s.Unlock()
 				return err
 			}
 		}
 
 		if s.cfg.EnableHTTP2() {
 			if err := s.initHTTP2(); err != nil {
+				s.Unlock()
 				return err
 			}
 		}
@@ -149,6 +299,23 @@ func (s *Service) Serve() error {
 		s.fcgi = &http.Server{Handler: s}
 	}
 
+	s.listeners = make([]*listener, 0, len(s.cfg.Listeners))
+	for _, l := range s.cfg.Listeners {
+		srv, err := s.initListener(l)
+		if err != nil {
+			s.Unlock()
+			return err
+		}
+
+		s.listeners = append(s.listeners, &listener{cfg: l, srv: srv})
+	}
+
+	for _, srv := range s.servers() {
+		if srv != nil {
+			s.cfg.Timeouts.configure(srv, s.cfg.MaxHeaderSize)
+		}
+	}
+
 	s.Unlock()
 
 	if err := s.rr.Start(); err != nil {
@@ -156,11 +323,24 @@ func (s *Service) Serve() error {
 	}
 	defer s.rr.Stop()
 
-	err := make(chan error, 3)
+	for name, h := range s.pools {
+		if err := h.rr.Start(); err != nil {
+			return fmt.Errorf("pool `%s`: %s", name, err)
+		}
+		defer h.rr.Stop()
+	}
+
+	lns, lErr := s.listen()
+	if lErr != nil {
+		return lErr
+	}
+	ready.set(nil)
+
+	err := make(chan error, 3+len(s.listeners))
 
 	if s.http != nil {
 		go func() {
-			httpErr := s.http.ListenAndServe()
+			httpErr := s.serve(s.http, lns[s.http], "", "")
 			if httpErr != nil && httpErr != http.ErrServerClosed {
 				err <- httpErr
 			} else {
@@ -171,7 +351,9 @@ func (s *Service) Serve() error {
 
 	if s.https != nil {
 		go func() {
-			httpErr := s.https.ListenAndServeTLS(
+			httpErr := s.serve(
+				s.https,
+				lns[s.https],
 				s.cfg.SSL.Cert,
 				s.cfg.SSL.Key,
 			)
@@ -186,7 +368,7 @@ func (s *Service) Serve() error {
 
 	if s.fcgi != nil {
 		go func() {
-			httpErr := s.serveFCGI()
+			httpErr := s.serveFCGI(lns[s.fcgi])
 			if httpErr != nil && httpErr != http.ErrServerClosed {
 				err <- httpErr
 				return
@@ -194,6 +376,18 @@ func (s *Service) Serve() error {
 			err <- nil
 		}()
 	}
+
+	for _, l := range s.listeners {
+		go func(l *listener) {
+			httpErr := s.serve(l.srv, lns[l.srv], l.cfg.SSL.Cert, l.cfg.SSL.Key)
+			if httpErr != nil && httpErr != http.ErrServerClosed {
+				err <- httpErr
+				return
+			}
+			err <- nil
+		}(l)
+	}
+
 	return <-err
 }
 
@@ -206,7 +400,7 @@ func (s *Service) Stop() {
 		s.Add(1)
 		go func() {
 			defer s.Done()
-			err := s.fcgi.Shutdown(context.Background())
+			err := s.shutdown(s.fcgi)
 			if err != nil && err != http.ErrServerClosed {
 				// Stop() error
 				// push error from goroutines to the channel and block unil error or success shutdown or timeout
@@ -220,7 +414,7 @@ func (s *Service) Stop() {
 		s.Add(1)
 		go func() {
 			defer s.Done()
-			err := s.https.Shutdown(context.Background())
+			err := s.shutdown(s.https)
 			if err != nil && err != http.ErrServerClosed {
 				s.log.Error(fmt.Errorf("error shutting down the https server, error: %v", err))
 				return
@@ -232,7 +426,7 @@ func (s *Service) Stop() {
 		s.Add(1)
 		go func() {
 			defer s.Done()
-			err := s.http.Shutdown(context.Background())
+			err := s.shutdown(s.http)
 			if err != nil && err != http.ErrServerClosed {
 				s.log.Error(fmt.Errorf("error shutting down the http server, error: %v", err))
 				return
@@ -240,9 +434,57 @@ func (s *Service) Stop() {
 		}()
 	}
 
+	for _, l := range s.listeners {
+		s.Add(1)
+		go func(l *listener) {
+			defer s.Done()
+			err := s.shutdown(l.srv)
+			if err != nil && err != http.ErrServerClosed {
+				s.log.Error(fmt.Errorf("error shutting down the %s server, error: %v", l.cfg.Address, err))
+				return
+			}
+		}(l)
+	}
+
 	s.Wait()
 }
 
+// shutdown gracefully stops given server, the server is closed forcibly if active connections
+// are not complete within pool drain timeout.
+func (s *Service) shutdown(srv *http.Server) error {
+	ctx := context.Background()
+	if timeout := s.cfg.drainTimeout(); timeout != 0 {
+		var cancel context.CancelFunc
+
+		// leave time to respond to aborted requests
+		ctx, cancel = context.WithTimeout(ctx, timeout+time.Second)
+		defer cancel()
+	}
+
+	if err := srv.Shutdown(ctx); err != context.DeadlineExceeded {
+		return err
+	}
+
+	return srv.Close()
+}
+
+// Ready blocks until workers are started and all servers are listening, returns error if service has
+// failed to start.
+func (s *Service) Ready() error {
+	s.Lock()
+	ready := s.ready
+	s.Unlock()
+
+	<-ready.done
+	return ready.err
+}
+
+// ClientIP returns ip of the client which has sent the request, X-Real-Ip, X-Forwarded-For and similar headers
+// are only used when request is received from the trusted subnets.
+func (s *Service) ClientIP(r *http.Request) string {
+	return clientIP(trustedBy(s.handler.config(), r), fetchIP(r.RemoteAddr), r.Header)
+}
+
 // Server returns associated rr server (if any).
 func (s *Service) Server() *roadrunner.Server {
 	s.Lock()
@@ -251,12 +493,43 @@ func (s *Service) Server() *roadrunner.Server {
 	return s.rr
 }
 
+// Pool returns rr server of the named worker pool, nil if pool is not defined or not running.
+func (s *Service) Pool(name string) *roadrunner.Server {
+	if name == DefaultPool {
+		return s.Server()
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if h, ok := s.pools[name]; ok {
+		return h.rr
+	}
+
+	return nil
+}
+
+// Pools returns names of all configured worker pools, the default pool goes first.
+func (s *Service) Pools() []string {
+	s.Lock()
+	defer s.Unlock()
+
+	names := make([]string, 0, len(s.cfg.Pools))
+	for name := range s.cfg.Pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return append([]string{DefaultPool}, names...)
+}
+
 // ServeHTTP handles connection using set of middleware and rr PSR-7 server.
 func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if s.https != nil && r.TLS == nil && s.cfg.SSL.Redirect {
+	cfg := s.handler.config()
+	if s.https != nil && r.TLS == nil && cfg.SSL.Redirect && listenerOf(r) == nil {
 		target := &url.URL{
 			Scheme:   "https",
-			Host:     s.tlsAddr(r.Host, false),
+			Host:     tlsAddr(r.Host, false, cfg.SSL.Port),
 			Path:     r.URL.Path,
 			RawQuery: r.URL.RawQuery,
 		}
@@ -265,20 +538,55 @@ func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if s.https != nil && r.TLS != nil {
+	if r.TLS != nil {
 		w.Header().Add("Strict-Transport-Security", "max-age=31536000; includeSubDomains; preload")
 	}
 
 	r = attributes.Init(r)
 
+	// attribute bag has just been initialized, setting the value never fails
+	r = cfg.RequestID.assign(w, r)
+	_ = attributes.Set(r, cfg.RequestID.attribute(), RequestID(r))
+
 	// chaining middleware
-	f := s.handler.ServeHTTP
+	f := s.route
 	for _, m := range s.mdwr {
 		f = m(f)
 	}
 	f(w, r)
 }
 
+// route serves request using the worker pool selected by the route rules.
+func (s *Service) route(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	handler, pools := s.handler, s.pools
+	s.Unlock()
+
+	if h, ok := pools[handler.config().Route(r)]; ok {
+		h.ServeHTTP(w, r)
+		return
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// configureWorkers prepares workers config of the named pool to be used by the rr server.
+func (s *Service) configureWorkers(cfg *roadrunner.ServerConfig, pool string) error {
+	if s.env != nil {
+		if err := s.env.Copy(cfg); err != nil {
+			return err
+		}
+	}
+
+	cfg.CommandProducer = s.cprod
+	cfg.FactoryProducer = s.fprod
+	cfg.ListenerProducer = util.CreateRelayListener
+	cfg.SetEnv("RR_HTTP", "true")
+	cfg.SetEnv("RR_HTTP_POOL", pool)
+
+	return nil
+}
+
 // append RootCA to the https server TLS config
 func (s *Service) appendRootCa() error {
 	rootCAs, err := x509.SystemCertPool()
@@ -310,8 +618,8 @@ func (s *Service) appendRootCa() error {
 	return nil
 }
 
-// Init https server
-func (s *Service) initSSL() *http.Server {
+// tlsConfig creates TLS configuration of the https servers.
+func tlsConfig() *tls.Config {
 	var topCipherSuites []uint16
 	var defaultCipherSuitesTLS13 []uint16
 
@@ -360,26 +668,75 @@ func (s *Service) initSSL() *http.Server {
 	DefaultCipherSuites = append(DefaultCipherSuites, topCipherSuites...)
 	DefaultCipherSuites = append(DefaultCipherSuites, defaultCipherSuitesTLS13...)
 
-	server := &http.Server{
-		Addr:    s.tlsAddr(s.cfg.Address, true),
-		Handler: s,
-		TLSConfig: &tls.Config{
-			CurvePreferences: []tls.CurveID{
-				tls.CurveP256,
-				tls.CurveP384,
-				tls.CurveP521,
-				tls.X25519,
-			},
-			CipherSuites:             DefaultCipherSuites,
-			MinVersion:               tls.VersionTLS12,
-			PreferServerCipherSuites: true,
+	return &tls.Config{
+		CurvePreferences: []tls.CurveID{
+			tls.CurveP256,
+			tls.CurveP384,
+			tls.CurveP521,
+			tls.X25519,
 		},
+		CipherSuites:             DefaultCipherSuites,
+		MinVersion:               tls.VersionTLS12,
+		PreferServerCipherSuites: true,
+	}
+}
+
+// Init https server
+func (s *Service) initSSL() *http.Server {
+	server := &http.Server{
+		Addr:      tlsAddr(s.cfg.Address, true, s.cfg.SSL.Port),
+		Handler:   s,
+		TLSConfig: tlsConfig(),
 	}
 	s.throw(EventInitSSL, server)
 
 	return server
 }
 
+// initListener creates server of the additional listener, requests served by the server carry listener config
+// in their context.
+func (s *Service) initListener(l *ListenerConfig) (*http.Server, error) {
+	var handler http.Handler = s
+	if l.H2C {
+		handler = h2c.NewHandler(s, &http2.Server{})
+	}
+
+	server := &http.Server{
+		Handler: handler,
+		BaseContext: func(net.Listener) context.Context {
+			return context.WithValue(context.Background(), listenerKey{}, l)
+		},
+	}
+
+	if !l.EnableTLS() {
+		return server, nil
+	}
+
+	server.TLSConfig = tlsConfig()
+	if l.SSL.RootCA != "" {
+		CA, err := ioutil.ReadFile(l.SSL.RootCA)
+		if err != nil {
+			return nil, err
+		}
+
+		server.TLSConfig.RootCAs = x509.NewCertPool()
+		if !server.TLSConfig.RootCAs.AppendCertsFromPEM(CA) {
+			return nil, couldNotAppendPemError
+		}
+	}
+
+	if s.cfg.EnableHTTP2() {
+		if err := http2.ConfigureServer(server, &http2.Server{
+			MaxConcurrentStreams: s.cfg.HTTP2.MaxConcurrentStreams,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	s.throw(EventInitSSL, server)
+
+	return server, nil
+}
+
 // init http/2 server
 func (s *Service) initHTTP2() error {
 	return http2.ConfigureServer(s.https, &http2.Server{
@@ -388,13 +745,8 @@ func (s *Service) initHTTP2() error {
 }
 
 // serveFCGI starts FastCGI server.
-func (s *Service) serveFCGI() error {
-	l, err := util.CreateListener(s.cfg.FCGI.Address)
-	if err != nil {
-		return err
-	}
-
-	err = fcgi.Serve(l, s.fcgi.Handler)
+func (s *Service) serveFCGI(l net.Listener) error {
+	err := fcgi.Serve(l, s.fcgi.Handler)
 	if err != nil {
 		return err
 	}
@@ -414,13 +766,112 @@ func (s *Service) throw(event int, ctx interface{}) {
 	}
 }
 
+// listen creates listeners for all enabled servers, listeners inherited from the parent process are used
+// when available.
+func (s *Service) listen() (map[*http.Server]net.Listener, error) {
+	lns := make(map[*http.Server]net.Listener, 3+len(s.listeners))
+
+	for i, srv := range s.servers() {
+		if srv == nil {
+			continue
+		}
+
+		var (
+			ln  net.Listener
+			err error
+		)
+
+		switch {
+		case srv == s.fcgi:
+			ln, err = util.CreateListener(s.cfg.FCGI.Address)
+			if err == nil {
+				ln = s.cfg.Timeouts.listener(ln)
+			}
+		case i >= 3:
+			ln, err = createListener(s.listeners[i-3].cfg)
+		default:
+			ln, err = util.Listen("tcp", srv.Addr)
+		}
+
+		if err != nil {
+			for _, l := range lns {
+				_ = l.Close()
+			}
+
+			return nil, err
+		}
+
+		lns[srv] = ln
+	}
+
+	return lns, nil
+}
+
+// servers returns http, https, fcgi and additional servers in this order, disabled servers are nil.
+func (s *Service) servers() []*http.Server {
+	servers := []*http.Server{s.http, s.https, s.fcgi}
+	for _, l := range s.listeners {
+		servers = append(servers, l.srv)
+	}
+
+	return servers
+}
+
+// set marks service run as ready or failed, only the first call takes effect.
+func (r *readiness) set(err error) {
+	r.once.Do(func() {
+		r.err = err
+		close(r.done)
+	})
+}
+
+// serve serves http server using given listener, TLS is enabled when certificate files are provided.
+func (s *Service) serve(srv *http.Server, ln net.Listener, certFile, keyFile string) error {
+	if certFile != "" {
+		return srv.ServeTLS(ln, certFile, keyFile)
+	}
+
+	return srv.Serve(ln)
+}
+
+// createListener creates socket listener of the additional server.
+func createListener(cfg *ListenerConfig) (net.Listener, error) {
+	var (
+		ln  net.Listener
+		err error
+	)
+
+	if network, address := cfg.Network(); network == "unix" {
+		ln, err = util.CreateListener(cfg.Address)
+	} else {
+		ln, err = util.Listen(network, address)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.ProxyProtocol {
+		return util.ProxyListener(ln, cfg.ProxyTimeout), nil
+	}
+
+	return ln, nil
+}
+
+// listenerOf returns config of the additional listener which has received the request, nil if request was received
+// by the main http, https or fcgi server.
+func listenerOf(r *http.Request) *ListenerConfig {
+	l, _ := r.Context().Value(listenerKey{}).(*ListenerConfig)
+	return l
+}
+
 // tlsAddr replaces listen or host port with port configured by SSL config.
-func (s *Service) tlsAddr(host string, forcePort bool) string {
+func tlsAddr(host string, forcePort bool, port int) string {
 	// remove current forcePort first
 	host = strings.Split(host, ":")[0]
 
-	if forcePort || s.cfg.SSL.Port != 443 {
-		host = fmt.Sprintf("%s:%v", host, s.cfg.SSL.Port)
+	if forcePort || port != 443 {
+		host = fmt.Sprintf("%s:%v", host, port)
 	}
 
 	return host
@@ -0,0 +1,227 @@
+package http
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TimeoutsConfig configures http server timeouts and request execution deadline. All durations are in seconds,
+// set 0 to disable the timeout.
+type TimeoutsConfig struct {
+	// Read is the maximum duration for reading the entire request including the body.
+	Read time.Duration
+
+	// ReadHeader is the amount of time allowed to read request headers, defaults to 10 seconds.
+	ReadHeader time.Duration
+
+	// Write is the maximum duration before timing out writes of the response.
+	Write time.Duration
+
+	// Idle is the maximum amount of time to wait for the next request on keep-alive connections, defaults to
+	// 2 minutes.
+	Idle time.Duration
+
+	// Exec is the maximum duration of the request execution by the worker, requests which are not served in time
+	// are responded with 504 Gateway Timeout. Worker continues the execution, use limit service execTTL to stop it.
+	Exec time.Duration
+}
+
+// InitDefaults sets missing values to their default values.
+func (cfg *TimeoutsConfig) InitDefaults() error {
+	cfg.ReadHeader = 10 * time.Second
+	cfg.Idle = 2 * time.Minute
+
+	return nil
+}
+
+// UpscaleDurations converts duration values from nanoseconds to seconds.
+func (cfg *TimeoutsConfig) UpscaleDurations() {
+	for _, d := range []*time.Duration{&cfg.Read, &cfg.ReadHeader, &cfg.Write, &cfg.Idle, &cfg.Exec} {
+		if *d < time.Microsecond {
+			*d = time.Second * time.Duration(d.Nanoseconds())
+		}
+	}
+}
+
+// Valid validates the configuration.
+func (cfg *TimeoutsConfig) Valid() error {
+	if cfg.Read < 0 || cfg.ReadHeader < 0 || cfg.Write < 0 || cfg.Idle < 0 || cfg.Exec < 0 {
+		return errors.New("timeouts must not be negative")
+	}
+
+	return nil
+}
+
+// execTimeout returns request execution deadline, 0 if not limited.
+func (cfg *TimeoutsConfig) execTimeout() time.Duration {
+	if cfg == nil {
+		return 0
+	}
+
+	return cfg.Exec
+}
+
+// sameServer returns true if both configs define the same server timeouts, execution deadline is not compared
+// since it can be changed without restarting the servers.
+func (cfg *TimeoutsConfig) sameServer(other *TimeoutsConfig) bool {
+	if cfg == nil || other == nil {
+		return cfg == other
+	}
+
+	return cfg.Read == other.Read &&
+		cfg.ReadHeader == other.ReadHeader &&
+		cfg.Write == other.Write &&
+		cfg.Idle == other.Idle
+}
+
+// configure applies timeouts and max header size (in kilobytes) to the given server.
+func (cfg *TimeoutsConfig) configure(srv *http.Server, maxHeaderSize int64) {
+	if cfg == nil {
+		return
+	}
+
+	srv.ReadTimeout = cfg.Read
+	srv.ReadHeaderTimeout = cfg.ReadHeader
+	srv.WriteTimeout = cfg.Write
+	srv.IdleTimeout = cfg.Idle
+	srv.MaxHeaderBytes = int(maxHeaderSize * 1024)
+}
+
+// FastCGI record types which start and complete the request.
+const (
+	fcgiBeginRequest = 1
+	fcgiEndRequest   = 3
+)
+
+// timeoutListener sets read and write deadlines on the accepted connections, used by the FastCGI server which
+// does not support timeouts on it's own.
+type timeoutListener struct {
+	net.Listener
+	read, write time.Duration
+}
+
+// listener wraps FastCGI listener to close connections which stay idle or do not accept response for too long,
+// listener is returned as is when timeouts are not set. Read deadline applies only while connection has no
+// requests in flight, FastCGI server keeps reading the connection while request is being served.
+func (cfg *TimeoutsConfig) listener(ln net.Listener) net.Listener {
+	if cfg == nil {
+		return ln
+	}
+
+	read := cfg.Idle
+	if read == 0 || (cfg.Read != 0 && cfg.Read > read) {
+		read = cfg.Read
+	}
+
+	if read == 0 && cfg.Write == 0 {
+		return ln
+	}
+
+	return &timeoutListener{Listener: ln, read: read, write: cfg.Write}
+}
+
+// Accept waits for and returns the next connection.
+func (l *timeoutListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return &timeoutConn{Conn: conn, read: l.read, write: l.write}, nil
+}
+
+// timeoutConn extends write deadline before every write and read deadline before every read of the idle
+// connection.
+type timeoutConn struct {
+	net.Conn
+	read, write time.Duration
+
+	mu       sync.Mutex
+	requests int
+	in, out  records
+}
+
+// Read reads data from the connection.
+func (c *timeoutConn) Read(b []byte) (int, error) {
+	c.mu.Lock()
+	if c.read != 0 && c.requests == 0 {
+		_ = c.Conn.SetReadDeadline(time.Now().Add(c.read))
+	}
+	c.mu.Unlock()
+
+	n, err := c.Conn.Read(b)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.in.scan(b[:n], func(typ byte) {
+		if typ == fcgiBeginRequest {
+			c.requests++
+		}
+	})
+
+	if c.read != 0 && c.requests != 0 {
+		_ = c.Conn.SetReadDeadline(time.Time{})
+	}
+
+	return n, err
+}
+
+// Write writes data to the connection.
+func (c *timeoutConn) Write(b []byte) (int, error) {
+	if c.write != 0 {
+		_ = c.Conn.SetWriteDeadline(time.Now().Add(c.write))
+	}
+
+	n, err := c.Conn.Write(b)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.out.scan(b[:n], func(typ byte) {
+		if typ == fcgiEndRequest && c.requests > 0 {
+			c.requests--
+			if c.read != 0 && c.requests == 0 {
+				_ = c.Conn.SetReadDeadline(time.Now().Add(c.read))
+			}
+		}
+	})
+
+	return n, err
+}
+
+// records follows FastCGI record stream to find the record types.
+type records struct {
+	header [8]byte
+	size   int
+	skip   int
+}
+
+// scan consumes next chunk of the stream and calls f with type of every record.
+func (r *records) scan(b []byte, f func(typ byte)) {
+	for len(b) > 0 {
+		if r.skip > 0 {
+			n := r.skip
+			if n > len(b) {
+				n = len(b)
+			}
+
+			r.skip -= n
+			b = b[n:]
+			continue
+		}
+
+		n := copy(r.header[r.size:], b)
+		r.size += n
+		b = b[n:]
+
+		if r.size == len(r.header) {
+			// content length and padding length
+			r.size, r.skip = 0, int(r.header[4])<<8|int(r.header[5])+int(r.header[6])
+			f(r.header[1])
+		}
+	}
+}
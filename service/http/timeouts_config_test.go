@@ -0,0 +1,61 @@
+package http
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fcgiRecord(typ byte, content []byte) []byte {
+	return append([]byte{1, typ, 0, 1, byte(len(content) >> 8), byte(len(content)), 0, 0}, content...)
+}
+
+func Test_TimeoutsListener_InFlight(t *testing.T) {
+	cfg := &TimeoutsConfig{Idle: time.Millisecond * 50}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	ln = cfg.listener(ln)
+	defer ln.Close()
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	conn, err := ln.Accept()
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	// request is split between writes to check record parsing
+	req := append(fcgiRecord(fcgiBeginRequest, make([]byte, 8)), fcgiRecord(4, []byte("params"))...)
+	_, _ = client.Write(req[:5])
+	_, _ = client.Write(req[5:])
+
+	buf := make([]byte, len(req))
+	for n := 0; n < len(req); {
+		m, err := conn.Read(buf[n:])
+		assert.NoError(t, err)
+		n += m
+	}
+
+	// request is served longer than idle timeout
+	go func() {
+		time.Sleep(time.Millisecond * 100)
+		_, _ = conn.Write(fcgiRecord(6, []byte("hello")))
+		_, _ = conn.Write(fcgiRecord(fcgiEndRequest, make([]byte, 8)))
+	}()
+
+	start := time.Now()
+	_, err = conn.Read(buf)
+	assert.Error(t, err)
+	assert.True(t, err.(net.Error).Timeout())
+	assert.True(t, time.Since(start) >= time.Millisecond*150)
+
+	rsp := make([]byte, 8+5+8+8)
+	_, err = io.ReadFull(client, rsp)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(rsp[8:13]))
+}
@@ -0,0 +1,106 @@
+package http_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spiral/roadrunner/rrtest"
+	rrhttp "github.com/spiral/roadrunner/service/http"
+	"github.com/stretchr/testify/assert"
+)
+
+// slowWorker responds after the delay given by the request path.
+func slowWorker() *rrtest.HTTP {
+	return rrtest.NewHTTP(rrtest.HTTPWorker(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if d, err := time.ParseDuration(strings.TrimPrefix(r.URL.Path, "/")); err == nil {
+			time.Sleep(d)
+		}
+
+		_, _ = w.Write([]byte("done"))
+	})))
+}
+
+func Test_Timeouts_Exec(t *testing.T) {
+	h := slowWorker()
+
+	timeouts := make(chan *rrhttp.ErrorEvent, 1)
+	h.Service.AddListener(func(event int, ctx interface{}) {
+		if event == rrhttp.EventTimeout {
+			timeouts <- ctx.(*rrhttp.ErrorEvent)
+		}
+	})
+
+	h.Start(t, rrtest.Config{"http": `{
+		"workers": {"pool": {"numWorkers": 1}},
+		"timeouts": {"exec": 200000000}
+	}`})
+
+	rsp, err := http.Get(h.URL("/1s"))
+	assert.NoError(t, err)
+	rsp.Body.Close()
+	assert.Equal(t, http.StatusGatewayTimeout, rsp.StatusCode)
+
+	e := <-timeouts
+	assert.Equal(t, rrhttp.ErrExecTimeout, e.Error)
+	assert.Equal(t, http.StatusGatewayTimeout, e.Status)
+	assert.Equal(t, "default", e.Pool)
+	assert.True(t, e.Elapsed() < time.Second)
+
+	// wait for the worker to complete the previous request
+	time.Sleep(time.Second)
+
+	r, _ := http.NewRequest("GET", h.URL("/10ms"), nil)
+	assert.Equal(t, "done", get(t, r))
+}
+
+func Test_Timeouts_MaxHeaderSize(t *testing.T) {
+	h := slowWorker()
+	h.Start(t, rrtest.Config{"http": `{
+		"workers": {"pool": {"numWorkers": 1}},
+		"maxHeaderSize": 1
+	}`})
+
+	r, _ := http.NewRequest("GET", h.URL("/"), nil)
+	r.Header.Set("X-Large", strings.Repeat("x", 10*1024))
+
+	rsp, err := http.DefaultClient.Do(r)
+	assert.NoError(t, err)
+	rsp.Body.Close()
+	assert.Equal(t, http.StatusRequestHeaderFieldsTooLarge, rsp.StatusCode)
+
+	r, _ = http.NewRequest("GET", h.URL("/"), nil)
+	assert.Equal(t, "done", get(t, r))
+}
+
+func Test_Timeouts_Config(t *testing.T) {
+	cfg := &rrhttp.Config{}
+	assert.NoError(t, cfg.Hydrate(rrtest.Config{"http": `{"address": ":8080"}`}.Get("http")))
+
+	assert.Equal(t, int64(1024), cfg.MaxHeaderSize)
+	assert.Equal(t, 10*time.Second, cfg.Timeouts.ReadHeader)
+	assert.Equal(t, 2*time.Minute, cfg.Timeouts.Idle)
+	assert.Equal(t, time.Duration(0), cfg.Timeouts.Read)
+	assert.Equal(t, time.Duration(0), cfg.Timeouts.Write)
+	assert.Equal(t, time.Duration(0), cfg.Timeouts.Exec)
+
+	cfg = &rrhttp.Config{}
+	assert.NoError(t, cfg.Hydrate(rrtest.Config{"http": `{
+		"address": ":8080",
+		"timeouts": {"read": 30, "write": 60, "exec": 5}
+	}`}.Get("http")))
+
+	assert.Equal(t, 30*time.Second, cfg.Timeouts.Read)
+	assert.Equal(t, 10*time.Second, cfg.Timeouts.ReadHeader)
+	assert.Equal(t, 60*time.Second, cfg.Timeouts.Write)
+	assert.Equal(t, 5*time.Second, cfg.Timeouts.Exec)
+
+	for _, c := range []string{
+		`{"address": ":8080", "timeouts": {"exec": -1}}`,
+		`{"address": ":8080", "maxHeaderSize": -1}`,
+	} {
+		cfg := &rrhttp.Config{}
+		assert.Error(t, cfg.Hydrate(rrtest.Config{"http": c}.Get("http")), c)
+	}
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"context"
+
+	"github.com/spiral/roadrunner"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName identifies spans produced by the http service.
+const TracerName = "github.com/spiral/roadrunner/service/http"
+
+// SetTracerProvider sets provider of the tracer used to trace requests served by the workers, requests are not
+// traced by default. Must be called before service is started.
+func (s *Service) SetTracerProvider(tp trace.TracerProvider) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.tp = tp
+}
+
+// tracer returns tracer of the configured provider, no-op tracer if tracing is disabled.
+func (s *Service) tracer() trace.Tracer {
+	if s.tp == nil {
+		return trace.NewNoopTracerProvider().Tracer(TracerName)
+	}
+
+	return s.tp.Tracer(TracerName)
+}
+
+// tracer returns handler tracer, no-op tracer if tracing is disabled.
+func (h *Handler) tracer() trace.Tracer {
+	if h.tr == nil {
+		return trace.NewNoopTracerProvider().Tracer(TracerName)
+	}
+
+	return h.tr
+}
+
+// injectTrace passes trace context of the request to the worker using W3C traceparent and tracestate headers,
+// spans created by the application become children of the request span. Headers are left untouched when request
+// is not traced.
+func injectTrace(ctx context.Context, req *Request) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(req.Header))
+}
+
+// traceTimings records worker allocation and worker execution spans using timings reported by the pool.
+func traceTimings(ctx context.Context, tr trace.Tracer, t *roadrunner.Timings, err error) {
+	if t.Allocate.IsZero() {
+		return
+	}
+
+	_, span := tr.Start(ctx, "rr.pool.allocate", trace.WithTimestamp(t.Allocate))
+	if t.Exec.IsZero() {
+		// worker has never been allocated
+		finishSpan(span, err)
+		return
+	}
+	span.End(trace.WithTimestamp(t.Exec))
+
+	_, span = tr.Start(
+		ctx,
+		"rr.worker.exec",
+		trace.WithTimestamp(t.Exec),
+		trace.WithAttributes(attribute.Int("rr.worker.pid", t.Pid)),
+	)
+	finishSpan(span, err, trace.WithTimestamp(t.Done))
+}
+
+// finishSpan ends the span, span is marked as failed when error is not nil.
+func finishSpan(span trace.Span, err error, options ...trace.SpanEndOption) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End(options...)
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// ResponseWriter records status and size of the response, used by middlewares to observe responses of the
+// wrapped handlers.
+type ResponseWriter struct {
+	http.ResponseWriter
+
+	// Status of the response, 200 unless status is set explicitly.
+	Status int
+
+	// Bytes contains size of the written response body.
+	Bytes int64
+
+	// Wrote is true once response headers are sent.
+	Wrote bool
+
+	// Hijacked is true if handler has taken over the connection.
+	Hijacked bool
+
+	// called once before response headers are sent
+	onHeader func(status int)
+
+	// receives every chunk of the written body
+	onWrite func(b []byte)
+}
+
+// pushWriter exposes server push of the underlying writer.
+type pushWriter struct {
+	*ResponseWriter
+}
+
+// WrapWriter wraps given response writer.
+func WrapWriter(w http.ResponseWriter) *ResponseWriter {
+	return &ResponseWriter{ResponseWriter: w, Status: http.StatusOK}
+}
+
+// Writer returns response writer to be passed to the handler, writer implements http.Pusher only when
+// underlying writer does.
+func (w *ResponseWriter) Writer() http.ResponseWriter {
+	if _, ok := w.ResponseWriter.(http.Pusher); ok {
+		return &pushWriter{w}
+	}
+
+	return w
+}
+
+// OnHeader registers function called once before response headers are sent, headers can still be modified.
+func (w *ResponseWriter) OnHeader(f func(status int)) {
+	w.onHeader = f
+}
+
+// OnWrite registers function receiving every chunk of the written response body.
+func (w *ResponseWriter) OnWrite(f func(b []byte)) {
+	w.onWrite = f
+}
+
+// WriteHeader sends response status code.
+func (w *ResponseWriter) WriteHeader(status int) {
+	if !w.Wrote {
+		if w.onHeader != nil {
+			w.onHeader(status)
+		}
+
+		w.Status, w.Wrote = status, true
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write writes response body.
+func (w *ResponseWriter) Write(b []byte) (int, error) {
+	if !w.Wrote {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.Bytes += int64(n)
+
+	if w.onWrite != nil && n > 0 {
+		w.onWrite(b[:n])
+	}
+
+	return n, err
+}
+
+// Flush sends buffered data to the client.
+func (w *ResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack lets the caller take over the connection.
+func (w *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		conn, rw, err := h.Hijack()
+		w.Hijacked = err == nil
+
+		return conn, rw, err
+	}
+
+	return nil, nil, errors.New("connection can not be hijacked")
+}
+
+// Push initiates HTTP/2 server push.
+func (w *pushWriter) Push(target string, opts *http.PushOptions) error {
+	return w.ResponseWriter.ResponseWriter.(http.Pusher).Push(target, opts)
+}
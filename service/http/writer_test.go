@@ -0,0 +1,80 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pusherRecorder is response recorder supporting server push.
+type pusherRecorder struct {
+	*httptest.ResponseRecorder
+	pushed []string
+}
+
+func (r *pusherRecorder) Push(target string, opts *http.PushOptions) error {
+	r.pushed = append(r.pushed, target)
+	return nil
+}
+
+func TestResponseWriter_Status(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rw := WrapWriter(rec)
+
+	w := rw.Writer()
+	_, ok := w.(http.Pusher)
+	assert.False(t, ok)
+
+	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("hello"))
+
+	assert.True(t, rw.Wrote)
+	assert.Equal(t, http.StatusCreated, rw.Status)
+	assert.Equal(t, int64(5), rw.Bytes)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestResponseWriter_ImplicitStatus(t *testing.T) {
+	rw := WrapWriter(httptest.NewRecorder())
+	assert.Equal(t, http.StatusOK, rw.Status)
+	assert.False(t, rw.Wrote)
+
+	_, _ = rw.Writer().Write([]byte("hello"))
+	assert.True(t, rw.Wrote)
+	assert.Equal(t, http.StatusOK, rw.Status)
+}
+
+func TestResponseWriter_Push(t *testing.T) {
+	rec := &pusherRecorder{ResponseRecorder: httptest.NewRecorder()}
+	rw := WrapWriter(rec)
+
+	p, ok := rw.Writer().(http.Pusher)
+	assert.True(t, ok)
+	assert.NoError(t, p.Push("/style.css", nil))
+	assert.Equal(t, []string{"/style.css"}, rec.pushed)
+}
+
+func TestResponseWriter_Hooks(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rw := WrapWriter(rec)
+
+	var status int
+	var body []byte
+
+	rw.OnHeader(func(s int) {
+		status = s
+		rw.Header().Set("X-Hook", "header")
+	})
+	rw.OnWrite(func(b []byte) { body = append(body, b...) })
+
+	w := rw.Writer()
+	_, _ = w.Write([]byte("hello "))
+	_, _ = w.Write([]byte("world"))
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "hello world", string(body))
+	assert.Equal(t, "header", rec.Header().Get("X-Hook"))
+}
@@ -46,3 +46,20 @@ func (c *Config) Controllers(l listener) (controllers map[string]roadrunner.Cont
 
 	return controllers
 }
+
+// PoolControllers returns list of controllers defined for the named worker pools of the watched services.
+func (c *Config) PoolControllers(l listener) (controllers map[string]map[string]roadrunner.Controller) {
+	controllers = make(map[string]map[string]roadrunner.Controller)
+
+	for name, cfg := range c.Services {
+		for pool, pcfg := range cfg.Pools {
+			if controllers[name] == nil {
+				controllers[name] = make(map[string]roadrunner.Controller)
+			}
+
+			controllers[name][pool] = &controller{lsn: l, tick: c.Interval, cfg: pcfg}
+		}
+	}
+
+	return controllers
+}
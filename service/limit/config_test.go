@@ -49,3 +49,28 @@ func Test_Controller_Default(t *testing.T) {
 
 	assert.Equal(t, time.Second, sc.(*controller).tick)
 }
+
+func Test_Controller_Pools(t *testing.T) {
+	cfg := &mockCfg{`
+{
+	"services":{
+		"http": {
+			"TTL": 1,
+			"pools": {"reports": {"maxMemory": 300}}
+		}
+	}
+}
+`}
+	c := &Config{}
+	assert.NoError(t, c.InitDefaults())
+	assert.NoError(t, c.Hydrate(cfg))
+
+	list := c.PoolControllers(func(event int, ctx interface{}) {
+	})
+
+	assert.Len(t, list, 1)
+	pc := list["http"]["reports"]
+
+	assert.Equal(t, uint64(300), pc.(*controller).cfg.MaxMemory)
+	assert.Equal(t, int64(0), pc.(*controller).cfg.TTL)
+}
@@ -37,6 +37,10 @@ type controllerConfig struct {
 
 	// ExecTTL defines maximum lifetime per job.
 	ExecTTL int64
+
+	// Pools overwrites limits of the named worker pools of the service, pools without own limits use limits
+	// of the service.
+	Pools map[string]*controllerConfig
 }
 
 type controller struct {
@@ -66,13 +70,14 @@ func (c *controller) control(p roadrunner.Pool) {
 
 			// make sure worker still on initial request
 			if p.Remove(w, err) && w.State().NumExecs() == eID {
-				go func() {
+				// reported prior to kill to capture tag of the request being executed
+				c.report(EventExecTTL, w, err)
+				go func(w *roadrunner.Worker) {
 					err := w.Kill()
 					if err != nil {
 						fmt.Printf("error killing worker with PID number: %d, created: %s", w.Pid, w.Created)
 					}
-				}()
-				c.report(EventExecTTL, w, err)
+				}(w)
 			}
 		}
 	}
@@ -131,7 +136,7 @@ func (c *controller) loadWorkers(p roadrunner.Pool) {
 // throw controller event
 func (c *controller) report(event int, worker *roadrunner.Worker, caused error) {
 	if c.lsn != nil {
-		c.lsn(event, roadrunner.WorkerError{Worker: worker, Caused: caused})
+		c.lsn(event, roadrunner.WorkerError{Worker: worker, Caused: caused, Tag: worker.Tag()})
 	}
 }
 
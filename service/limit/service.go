@@ -1,6 +1,9 @@
 package limit
 
 import (
+	"fmt"
+	"sync"
+
 	"github.com/spiral/roadrunner"
 	"github.com/spiral/roadrunner/service"
 )
@@ -10,20 +13,49 @@ const ID = "limit"
 
 // Service to control the state of rr service inside other services.
 type Service struct {
+	c    service.Container
+	mu   sync.Mutex
+	cfg  *Config
 	lsns []func(event int, ctx interface{})
 }
 
 // Init controller service
 func (s *Service) Init(cfg *Config, c service.Container) (bool, error) {
+	s.c = c
+	s.cfg = cfg
+
 	// mount Services to designated services
-	for id, watcher := range cfg.Controllers(s.throw) {
-		svc, _ := c.Get(id)
-		if ctrl, ok := svc.(roadrunner.Attacher); ok {
-			ctrl.Attach(watcher)
+	s.attach(cfg)
+
+	return true, nil
+}
+
+// Reconfigure replaces controllers of the watched services. Restart is required to stop watching the service.
+func (s *Service) Reconfigure(cfg service.HydrateConfig) error {
+	c, ok := cfg.(*Config)
+	if !ok {
+		return fmt.Errorf("invalid config type %T", cfg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, cfg := range s.cfg.Services {
+		if _, ok := c.Services[id]; !ok {
+			return service.ErrRestartRequired
+		}
+
+		for pool := range cfg.Pools {
+			if _, ok := c.Services[id].Pools[pool]; !ok {
+				return service.ErrRestartRequired
+			}
 		}
 	}
 
-	return true, nil
+	s.cfg = c
+	s.attach(c)
+
+	return nil
 }
 
 // AddListener attaches server event controller.
@@ -31,6 +63,25 @@ func (s *Service) AddListener(l func(event int, ctx interface{})) {
 	s.lsns = append(s.lsns, l)
 }
 
+// attach attaches controllers to the watched services and their worker pools.
+func (s *Service) attach(cfg *Config) {
+	for id, watcher := range cfg.Controllers(s.throw) {
+		svc, _ := s.c.Get(id)
+		if ctrl, ok := svc.(roadrunner.Attacher); ok {
+			ctrl.Attach(watcher)
+		}
+	}
+
+	for id, watchers := range cfg.PoolControllers(s.throw) {
+		svc, _ := s.c.Get(id)
+		if ctrl, ok := svc.(roadrunner.PoolAttacher); ok {
+			for pool, watcher := range watchers {
+				ctrl.AttachPool(pool, watcher)
+			}
+		}
+	}
+}
+
 // throw handles service, server and pool events.
 func (s *Service) throw(event int, ctx interface{}) {
 	for _, l := range s.lsns {
@@ -14,6 +14,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 	"github.com/spiral/roadrunner/service/rpc"
+	"github.com/spiral/roadrunner/util"
 	"golang.org/x/sys/cpu"
 )
 
@@ -155,7 +156,12 @@ func (s *Service) Serve() error {
 	}
 	s.mu.Unlock()
 
-	err = s.http.ListenAndServe()
+	ln, err := util.Listen("tcp", s.cfg.Address)
+	if err != nil {
+		return err
+	}
+
+	err = s.http.Serve(ln)
 	if err != nil && err != http.ErrServerClosed {
 		return err
 	}
@@ -0,0 +1,97 @@
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spiral/roadrunner/service"
+)
+
+// Config defines external plugins.
+type Config struct {
+	// Plugins by their names.
+	Plugins map[string]*PluginConfig
+}
+
+// PluginConfig describes single plugin process.
+type PluginConfig struct {
+	// Command includes plugin executable with all the parameters, example: "./bin/plugin --debug".
+	Command string
+
+	// Env contains additional environment variables passed to the plugin, keys are automatically uppercase-d.
+	Env map[string]string
+
+	// Timeout defines for how long RR waits for the plugin to respond. Defaults to 10 seconds.
+	Timeout time.Duration
+
+	// Restart defines when plugin process must be restarted. Plugins are restarted on failure by default.
+	Restart *service.RestartPolicy
+
+	// Config is passed to the plugin Init method as JSON.
+	Config map[string]interface{}
+}
+
+// Hydrate must populate Config values using given Config source. Must return error if Config is not valid.
+func (c *Config) Hydrate(cfg service.Config) error {
+	if err := cfg.Unmarshal(&c.Plugins); err != nil {
+		return err
+	}
+
+	for name, p := range c.Plugins {
+		if p == nil {
+			return fmt.Errorf("[%s]: missing plugin command", name)
+		}
+
+		p.InitDefaults()
+		if err := p.Valid(); err != nil {
+			return fmt.Errorf("[%s]: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Section returns value config section is unmarshalled into.
+func (c *Config) Section() interface{} {
+	return &c.Plugins
+}
+
+// InitDefaults sets missing values to their default values.
+func (c *PluginConfig) InitDefaults() {
+	if c.Timeout == 0 {
+		c.Timeout = time.Second * 10
+	}
+
+	// timeout is defined in seconds
+	if c.Timeout < time.Microsecond {
+		c.Timeout = time.Second * time.Duration(c.Timeout.Nanoseconds())
+	}
+
+	if c.Restart == nil {
+		c.Restart = &service.RestartPolicy{Policy: service.RestartOnFailure}
+	}
+
+	c.Restart.InitDefaults()
+}
+
+// Valid returns nil if config is valid.
+func (c *PluginConfig) Valid() error {
+	if strings.TrimSpace(c.Command) == "" {
+		return errors.New("missing plugin command")
+	}
+
+	return c.Restart.Valid()
+}
+
+// env returns environment of the plugin process.
+func (c *PluginConfig) env(name string) []string {
+	env := append(os.Environ(), fmt.Sprintf("%s=%s", PluginEnv, name))
+	for k, v := range c.Env {
+		env = append(env, fmt.Sprintf("%s=%s", strings.ToUpper(k), v))
+	}
+
+	return env
+}
@@ -0,0 +1,352 @@
+package plugins
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/rpc"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spiral/goridge/v2"
+	"github.com/spiral/roadrunner/osutil"
+)
+
+// errDisabled returned when plugin reports that it must not be served.
+var errDisabled = errors.New("plugin is disabled")
+
+// plugin supervises external plugin process.
+type plugin struct {
+	name string
+	cfg  *PluginConfig
+	log  *logrus.Logger
+
+	mu      sync.Mutex
+	stopped chan struct{}
+	cmd     *exec.Cmd
+	exited  chan struct{}
+	client  *rpc.Client
+	info    *InitReply
+}
+
+// newPlugin creates plugin supervisor.
+func newPlugin(name string, cfg *PluginConfig, log *logrus.Logger) *plugin {
+	return &plugin{name: name, cfg: cfg, log: log, stopped: make(chan struct{})}
+}
+
+// reset prepares stopped plugin to be served again, must not be called while plugin is running.
+func (p *plugin) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stopped = make(chan struct{})
+}
+
+// run serves plugin process and restarts it according to the restart policy until plugin is stopped. Plugin
+// failures are logged and never passed to the caller.
+func (p *plugin) run() {
+	for restarts := 0; ; restarts++ {
+		err := p.serve()
+		if p.isStopped() {
+			return
+		}
+
+		if err == errDisabled {
+			p.log.Debugf("[%s]: plugin is disabled", p.name)
+			return
+		}
+
+		delay, ok := p.cfg.Restart.Next(restarts, err)
+		if !ok {
+			if err != nil {
+				p.log.Errorf("[%s]: plugin has failed: %s", p.name, err)
+			}
+			return
+		}
+
+		if err != nil {
+			p.log.Warningf("[%s]: restarting plugin in %s (%s)", p.name, delay, err)
+		} else {
+			p.log.Warningf("[%s]: restarting plugin in %s", p.name, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-p.stopped:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// serve starts plugin process and blocks until plugin is done.
+func (p *plugin) serve() error {
+	client, err := p.start()
+	if err != nil || client == nil {
+		return err
+	}
+
+	err = p.session(client)
+	if exitErr := p.release(); exitErr != nil && err != errDisabled {
+		return exitErr
+	}
+
+	return err
+}
+
+// session configures and serves the connected plugin.
+func (p *plugin) session(client *rpc.Client) error {
+	cfg, err := json.Marshal(p.cfg.Config)
+	if err != nil {
+		return err
+	}
+
+	info := &InitReply{}
+	if err := p.call(client, "Init", InitArgs{Name: p.name, Config: cfg}, info); err != nil {
+		return fmt.Errorf("plugin init: %v", err)
+	}
+
+	if !info.Enabled {
+		return errDisabled
+	}
+
+	p.mu.Lock()
+	p.info = info
+	p.mu.Unlock()
+
+	p.log.Debugf("[%s]: plugin started", p.name)
+
+	var r bool
+	call := client.Go(pluginService+".Serve", true, &r, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		if call.Error != nil && !p.isStopped() {
+			return call.Error
+		}
+
+	case <-p.stopped:
+		// plugin must stop serving within the timeout, connection is closed otherwise
+		timer := time.NewTimer(p.cfg.Timeout)
+		defer timer.Stop()
+
+		select {
+		case <-call.Done:
+		case <-timer.C:
+			p.log.Warningf("[%s]: plugin has not stopped in %s", p.name, p.cfg.Timeout)
+		}
+	}
+
+	return nil
+}
+
+// start spawns plugin process and connects to it.
+func (p *plugin) start() (*rpc.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	select {
+	case <-p.stopped:
+		return nil, nil
+	default:
+	}
+
+	args := strings.Split(p.cfg.Command, " ")
+	cmd := exec.Command(args[0], args[1:]...)
+	osutil.IsolateProcess(cmd)
+	cmd.Env = p.cfg.env(p.name)
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		_ = stdinR.Close()
+		_ = stdinW.Close()
+		return nil, err
+	}
+
+	stderr := p.log.WithField("plugin", p.name).WriterLevel(logrus.InfoLevel)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdinR, stdoutW, stderr
+
+	err = cmd.Start()
+
+	// child process owns it's ends of the pipes
+	_ = stdinR.Close()
+	_ = stdoutW.Close()
+
+	if err != nil {
+		_ = stdinW.Close()
+		_ = stdoutR.Close()
+		_ = stderr.Close()
+		return nil, err
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		_ = stderr.Close()
+		close(exited)
+	}()
+
+	p.cmd, p.exited = cmd, exited
+	p.client = rpc.NewClientWithCodec(goridge.NewClientCodec(&pipe{ReadCloser: stdoutR, WriteCloser: stdinW}))
+
+	return p.client, nil
+}
+
+// release closes the connection and waits for the plugin process to exit, process is killed if it does
+// not exit within the timeout. Returns error if process has exited with non zero status.
+func (p *plugin) release() error {
+	p.mu.Lock()
+	client, cmd, exited := p.client, p.cmd, p.exited
+	p.client, p.info = nil, nil
+	p.mu.Unlock()
+
+	// plugin must exit once connection is closed
+	_ = client.Close()
+
+	timer := time.NewTimer(p.cfg.Timeout)
+	defer timer.Stop()
+
+	select {
+	case <-exited:
+		if !cmd.ProcessState.Success() {
+			return fmt.Errorf("plugin process has exited: %s", cmd.ProcessState)
+		}
+
+		return nil
+	case <-timer.C:
+		p.log.Warningf("[%s]: plugin has not exited in %s, killing", p.name, p.cfg.Timeout)
+		_ = cmd.Process.Kill()
+		<-exited
+
+		return nil
+	}
+}
+
+// stop requests plugin to stop, plugin won't be restarted.
+func (p *plugin) stop() {
+	p.mu.Lock()
+	select {
+	case <-p.stopped:
+	default:
+		close(p.stopped)
+	}
+	client := p.client
+	p.mu.Unlock()
+
+	if client == nil {
+		return
+	}
+
+	var r bool
+	if err := p.call(client, "Stop", true, &r); err != nil {
+		p.log.Warningf("[%s]: unable to stop plugin: %s", p.name, err)
+
+		// closing the connection forces plugin to exit
+		_ = client.Close()
+	}
+}
+
+// invoke calls RPC method exposed by the plugin.
+func (p *plugin) invoke(method string, params []byte) ([]byte, error) {
+	client, info := p.state()
+	if client == nil || !contains(info.Methods, method) {
+		return nil, fmt.Errorf("rpc: can't find method %s.%s", p.name, method)
+	}
+
+	if len(params) == 0 {
+		params = []byte("null")
+	}
+
+	reply := &CallReply{}
+	if err := p.call(client, "Call", CallArgs{Method: method, Params: params}, reply); err != nil {
+		return nil, err
+	}
+
+	if reply.Error != "" {
+		return nil, errors.New(reply.Error)
+	}
+
+	return reply.Result, nil
+}
+
+// handle passes HTTP request to the plugin, returns nil if plugin does not handle HTTP requests.
+func (p *plugin) handle(r *Request) (*Response, error) {
+	client, info := p.state()
+	if client == nil || !info.Middleware {
+		return nil, nil
+	}
+
+	resp := &Response{}
+	if err := p.call(client, "Handle", r, resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+
+	return resp, nil
+}
+
+// middleware returns true if serving plugin handles HTTP requests.
+func (p *plugin) middleware() bool {
+	_, info := p.state()
+	return info != nil && info.Middleware
+}
+
+// state returns plugin connection and capabilities, connection is nil if plugin is not serving.
+func (p *plugin) state() (*rpc.Client, *InitReply) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.info == nil {
+		return nil, nil
+	}
+
+	return p.client, p.info
+}
+
+// call invokes plugin method, call is aborted after configured timeout. Reply must not be used if call
+// has failed.
+func (p *plugin) call(client *rpc.Client, method string, args, reply interface{}) error {
+	timer := time.NewTimer(p.cfg.Timeout)
+	defer timer.Stop()
+
+	call := client.Go(pluginService+"."+method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		return call.Error
+	case <-timer.C:
+		return fmt.Errorf("plugin method `%s` timeout", method)
+	}
+}
+
+// isStopped returns true if plugin has been stopped.
+func (p *plugin) isStopped() bool {
+	select {
+	case <-p.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// contains returns true if list contains given value.
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,269 @@
+package plugins
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/rpc"
+	"os"
+	"sync"
+
+	"github.com/spiral/goridge/v2"
+)
+
+const (
+	// PluginEnv contains name of the plugin, variable is set for all plugin processes.
+	PluginEnv = "RR_PLUGIN"
+
+	// pluginService contains name of the net/rpc service exposed by the plugin process.
+	pluginService = "Plugin"
+)
+
+// InitArgs passed to the plugin Init method.
+type InitArgs struct {
+	// Name of the plugin.
+	Name string `json:"name"`
+
+	// Config contains plugin configuration.
+	Config json.RawMessage `json:"config"`
+}
+
+// InitReply describes plugin capabilities.
+type InitReply struct {
+	// Enabled must be false if plugin must not be served.
+	Enabled bool `json:"enabled"`
+
+	// Methods contains RPC methods exposed by the plugin under the plugin name.
+	Methods []string `json:"methods"`
+
+	// Middleware must be true if plugin handles HTTP requests.
+	Middleware bool `json:"middleware"`
+}
+
+// CallArgs passed to the plugin Call method.
+type CallArgs struct {
+	// Method name without plugin prefix.
+	Method string `json:"method"`
+
+	// Params contains JSON encoded method params.
+	Params json.RawMessage `json:"params"`
+}
+
+// CallReply contains result of the plugin RPC method.
+type CallReply struct {
+	// Result contains JSON encoded method result.
+	Result json.RawMessage `json:"result"`
+
+	// Error contains method error, if any.
+	Error string `json:"error"`
+}
+
+// Request describes HTTP request passed to the plugin Handle method.
+type Request struct {
+	// Method of the request.
+	Method string `json:"method"`
+
+	// URI contains request uri with query string.
+	URI string `json:"uri"`
+
+	// RemoteAddr of the client.
+	RemoteAddr string `json:"remoteAddr"`
+
+	// Header contains request headers.
+	Header http.Header `json:"header"`
+
+	// Body contains up to 1MB of the request body.
+	Body []byte `json:"body"`
+
+	// BodyTruncated is true if request body is larger than passed Body.
+	BodyTruncated bool `json:"bodyTruncated"`
+}
+
+// Response describes plugin reaction on HTTP request. Request is passed to the next handler when Status is 0,
+// headers are added to the response in both cases, including when the following plugins fail.
+type Response struct {
+	// Status of the response.
+	Status int `json:"status"`
+
+	// Header contains response headers.
+	Header http.Header `json:"header"`
+
+	// Body of the response.
+	Body string `json:"body"`
+
+	// Error contains handler error, request is passed to the next handler.
+	Error string `json:"error"`
+}
+
+// Plugin must be implemented by the external plugin served using Run. Plugin logs must be written to stderr,
+// stdout is used to communicate with RR.
+type Plugin interface {
+	// Init configures the plugin, must return false if plugin must be disabled.
+	Init(name string, cfg json.RawMessage) (bool, error)
+
+	// Serve serves the plugin, must block until plugin is stopped.
+	Serve() error
+
+	// Stop stops the plugin.
+	Stop()
+}
+
+// Caller can be implemented by plugins which expose RPC methods.
+type Caller interface {
+	// Methods returns names of the exposed methods.
+	Methods() []string
+
+	// Call invokes RPC method, result is encoded as JSON.
+	Call(method string, params json.RawMessage) (interface{}, error)
+}
+
+// Handler can be implemented by plugins which handle HTTP requests.
+type Handler interface {
+	// Handle must return response or nil if request must be passed to the next handler.
+	Handle(r *Request) (*Response, error)
+}
+
+// Run serves the plugin over stdin and stdout of the current process. Returns once RR closes the connection,
+// plugin is stopped if connection is closed while plugin is serving.
+func Run(p Plugin) error {
+	e := &endpoint{p: p}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName(pluginService, e); err != nil {
+		return err
+	}
+
+	server.ServeCodec(goridge.NewCodec(&pipe{ReadCloser: &lossReader{ReadCloser: os.Stdin, lost: e.stop}, WriteCloser: os.Stdout}))
+	return nil
+}
+
+// endpoint exposes plugin methods over net/rpc.
+type endpoint struct {
+	p Plugin
+
+	mu      sync.Mutex
+	serving bool
+	stopped bool
+}
+
+// Init configures the plugin.
+func (e *endpoint) Init(in InitArgs, out *InitReply) error {
+	enabled, err := e.p.Init(in.Name, in.Config)
+	if err != nil {
+		return err
+	}
+
+	out.Enabled = enabled
+	if c, ok := e.p.(Caller); ok {
+		out.Methods = c.Methods()
+	}
+	_, out.Middleware = e.p.(Handler)
+
+	return nil
+}
+
+// Serve serves the plugin.
+func (e *endpoint) Serve(in bool, out *bool) error {
+	*out = true
+
+	e.mu.Lock()
+	if e.stopped {
+		e.mu.Unlock()
+		return nil
+	}
+	e.serving = true
+	e.mu.Unlock()
+
+	return e.p.Serve()
+}
+
+// Stop stops the plugin.
+func (e *endpoint) Stop(in bool, out *bool) error {
+	e.stop()
+	*out = true
+	return nil
+}
+
+// Call invokes plugin RPC method, errors are passed in reply.
+func (e *endpoint) Call(in CallArgs, out *CallReply) error {
+	c, ok := e.p.(Caller)
+	if !ok {
+		out.Error = "plugin does not expose RPC methods"
+		return nil
+	}
+
+	result, err := c.Call(in.Method, in.Params)
+	if err == nil {
+		out.Result, err = json.Marshal(result)
+	}
+
+	if err != nil {
+		out.Error = err.Error()
+	}
+
+	return nil
+}
+
+// Handle passes HTTP request to the plugin, errors are passed in reply.
+func (e *endpoint) Handle(in Request, out *Response) error {
+	h, ok := e.p.(Handler)
+	if !ok {
+		return nil
+	}
+
+	r, err := h.Handle(&in)
+	if err != nil {
+		out.Error = err.Error()
+		return nil
+	}
+
+	if r != nil {
+		*out = *r
+	}
+
+	return nil
+}
+
+// stop stops serving plugin, plugin is stopped only once.
+func (e *endpoint) stop() {
+	e.mu.Lock()
+	serving, stopped := e.serving, e.stopped
+	e.stopped = true
+	e.mu.Unlock()
+
+	if serving && !stopped {
+		e.p.Stop()
+	}
+}
+
+// lossReader notifies about lost connection.
+type lossReader struct {
+	io.ReadCloser
+	lost func()
+}
+
+// Read reads data from the connection.
+func (r *lossReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err != nil {
+		r.lost()
+	}
+
+	return n, err
+}
+
+// pipe combines read and write ends of the connection.
+type pipe struct {
+	io.ReadCloser
+	io.WriteCloser
+}
+
+// Close closes both ends of the connection.
+func (p *pipe) Close() error {
+	werr := p.WriteCloser.Close()
+	if err := p.ReadCloser.Close(); err != nil {
+		return err
+	}
+
+	return werr
+}
@@ -0,0 +1,187 @@
+package plugins
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	rrhttp "github.com/spiral/roadrunner/service/http"
+	"github.com/spiral/roadrunner/service/rpc"
+)
+
+// ID contains default service name.
+const ID = "plugins"
+
+// maxBodySize limits size of the request body passed to the plugins.
+const maxBodySize = 1 << 20
+
+// Service launches and supervises external plugin processes. Plugins can expose RPC methods under their names
+// and handle HTTP requests as middleware. Plugin failures never stop the server.
+type Service struct {
+	log     *logrus.Logger
+	plugins []*plugin
+
+	mu   sync.Mutex
+	stop chan interface{}
+	wg   sync.WaitGroup
+}
+
+// Init must return configure service and return true if service hasStatus enabled. Must return error in case of
+// misconfiguration. Services must not be used without proper configuration pushed first.
+func (s *Service) Init(cfg *Config, log *logrus.Logger, r *rpc.Service, h *rrhttp.Service) (bool, error) {
+	if len(cfg.Plugins) == 0 {
+		return false, nil
+	}
+
+	names := make([]string, 0, len(cfg.Plugins))
+	for name := range cfg.Plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	s.log = log
+	s.plugins = make([]*plugin, 0, len(names))
+	for _, name := range names {
+		p := newPlugin(name, cfg.Plugins[name], log)
+		if r != nil {
+			if err := r.RegisterProxy(name, p.invoke); err != nil {
+				return false, err
+			}
+		}
+
+		s.plugins = append(s.plugins, p)
+	}
+
+	if h != nil {
+		h.AddMiddleware(s.middleware)
+	}
+
+	return true, nil
+}
+
+// Serve serves plugins until service is stopped.
+func (s *Service) Serve() error {
+	s.mu.Lock()
+	s.stop = make(chan interface{})
+	for _, p := range s.plugins {
+		p.reset()
+
+		s.wg.Add(1)
+		go func(p *plugin) {
+			defer s.wg.Done()
+			p.run()
+		}(p)
+	}
+	stop := s.stop
+	s.mu.Unlock()
+
+	<-stop
+	s.wg.Wait()
+
+	return nil
+}
+
+// Stop stops all plugins.
+func (s *Service) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop == nil {
+		return
+	}
+
+	for _, p := range s.plugins {
+		p.stop()
+	}
+
+	close(s.stop)
+	s.stop = nil
+}
+
+// middleware passes HTTP requests to the plugins, request is passed to the next handler unless any of the
+// plugins responds to it. Plugins receive up to maxBodySize bytes of the request body, the whole body is still
+// passed to the next handler. Headers of the plugins passing the request are added to the response even when
+// following plugins fail. Plugin errors are logged and ignored.
+func (s *Service) middleware(f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req *Request
+		for _, p := range s.plugins {
+			if !p.middleware() {
+				continue
+			}
+
+			if req == nil {
+				var err error
+				if req, err = newRequest(r); err != nil {
+					s.log.Errorf("[%s]: %s", ID, err)
+					http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+					return
+				}
+			}
+
+			resp, err := p.handle(req)
+			if err != nil {
+				s.log.Errorf("[%s]: %s", p.name, err)
+				continue
+			}
+
+			if resp == nil {
+				continue
+			}
+
+			for k, v := range resp.Header {
+				for _, h := range v {
+					w.Header().Add(k, h)
+				}
+			}
+
+			if resp.Status != 0 {
+				w.WriteHeader(resp.Status)
+				_, _ = w.Write([]byte(resp.Body))
+				return
+			}
+		}
+
+		f(w, r)
+	}
+}
+
+// newRequest creates plugin request, body is read up to maxBodySize bytes and restored for the next handler.
+func newRequest(r *http.Request) (*Request, error) {
+	req := &Request{
+		Method:     r.Method,
+		URI:        r.URL.RequestURI(),
+		RemoteAddr: r.RemoteAddr,
+		Header:     r.Header,
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return req, nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	// rest of the body stays unread
+	r.Body = &readCloser{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+
+	if len(body) > maxBodySize {
+		req.BodyTruncated = true
+		body = body[:maxBodySize]
+	}
+	req.Body = body
+
+	return req, nil
+}
+
+// readCloser combines reader and closer of the request body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
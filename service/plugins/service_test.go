@@ -0,0 +1,323 @@
+package plugins
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiral/roadrunner/rrtest"
+	"github.com/spiral/roadrunner/service"
+	"github.com/spiral/roadrunner/service/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// testPlugin is served by the test binary when RR_TEST_PLUGIN is set.
+type testPlugin struct {
+	name string
+	done chan interface{}
+}
+
+func (p *testPlugin) Init(name string, cfg json.RawMessage) (bool, error) {
+	var c struct{ Disabled bool }
+	if err := json.Unmarshal(cfg, &c); err != nil {
+		return false, err
+	}
+
+	p.name = name
+	p.done = make(chan interface{})
+	return !c.Disabled, nil
+}
+
+func (p *testPlugin) Serve() error {
+	<-p.done
+	return nil
+}
+
+func (p *testPlugin) Stop() {
+	close(p.done)
+}
+
+func (p *testPlugin) Methods() []string {
+	return []string{"Echo", "Fail", "Crash"}
+}
+
+func (p *testPlugin) Call(method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case "Echo":
+		var v interface{}
+		err := json.Unmarshal(params, &v)
+		return v, err
+	case "Crash":
+		os.Exit(1)
+	}
+
+	return nil, errors.New("plugin error")
+}
+
+func (p *testPlugin) Handle(r *Request) (*Response, error) {
+	switch r.Header.Get("X-Plugin") {
+	case "block":
+		return &Response{Status: 403, Body: "blocked by " + p.name}, nil
+	case "fail":
+		return nil, errors.New("plugin error")
+	case "echo":
+		return &Response{Status: 200, Body: string(r.Body)}, nil
+	}
+
+	return &Response{Header: http.Header{"X-Plugin": {p.name}}}, nil
+}
+
+func TestHelperPlugin(t *testing.T) {
+	if os.Getenv("RR_TEST_PLUGIN") == "" {
+		return
+	}
+
+	if err := Run(&testPlugin{}); err != nil {
+		os.Exit(2)
+	}
+	os.Exit(0)
+}
+
+// pluginConfig returns config of the plugin served by the test binary.
+func pluginConfig(name, cfg string) string {
+	return fmt.Sprintf(
+		`{"%s":{"command":"%s -test.run=^TestHelperPlugin$","env":{"rr_test_plugin":"1"},"restart":{"policy":"on-failure","backoff":1000000},"config":%s}}`,
+		name,
+		os.Args[0],
+		cfg,
+	)
+}
+
+func Test_Config_Hydrate(t *testing.T) {
+	cfg := &Config{}
+	assert.NoError(t, cfg.Hydrate(rrtest.Config{ID: `{"echo":{"command":"./plugin"}}`}.Get(ID)))
+
+	p := cfg.Plugins["echo"]
+	assert.Equal(t, time.Second*10, p.Timeout)
+	assert.Equal(t, service.RestartOnFailure, p.Restart.Policy)
+
+	cfg = &Config{}
+	assert.Error(t, cfg.Hydrate(rrtest.Config{ID: `{"echo":{"timeout":1}}`}.Get(ID)))
+
+	cfg = &Config{}
+	assert.Error(t, cfg.Hydrate(rrtest.Config{ID: `{"echo":{"command":"./plugin","restart":{"policy":"sometimes"}}}`}.Get(ID)))
+}
+
+func Test_Plugin_RPC(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	c := service.NewContainer(logger)
+	c.Register(rpc.ID, &rpc.Service{})
+	c.Register(ID, &Service{})
+
+	assert.NoError(t, c.Init(rrtest.Config{
+		rpc.ID: `{"enable":true,"listen":"tcp://:5009"}`,
+		ID:     pluginConfig("echo", `{}`),
+	}))
+
+	go func() { assert.NoError(t, c.Serve()) }()
+	defer c.Stop()
+
+	<-c.Ready()
+
+	svc, _ := c.Get(ID)
+	p := svc.(*Service).plugins[0]
+	waitServing(t, p)
+
+	rs, _ := c.Get(rpc.ID)
+	client, err := rs.(*rpc.Service).Client()
+	assert.NoError(t, err)
+
+	var echo map[string]int
+	assert.NoError(t, client.Call("echo.Echo", map[string]int{"value": 1}, &echo))
+	assert.Equal(t, map[string]int{"value": 1}, echo)
+
+	err = client.Call("echo.Fail", true, &echo)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "plugin error")
+	_ = client.Close()
+
+	client, err = rs.(*rpc.Service).Client()
+	assert.NoError(t, err)
+	defer client.Close()
+
+	err = client.Call("echo.Unknown", true, &echo)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "can't find method echo.Unknown")
+}
+
+func Test_Plugin_Restart(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	c := service.NewContainer(logger)
+	c.Register(ID, &Service{})
+	assert.NoError(t, c.Init(rrtest.Config{ID: pluginConfig("echo", `{}`)}))
+
+	go func() { assert.NoError(t, c.Serve()) }()
+	defer c.Stop()
+
+	svc, _ := c.Get(ID)
+	p := svc.(*Service).plugins[0]
+	waitServing(t, p)
+
+	pid := p.pid()
+
+	// crash is isolated from the container
+	_, err := p.invoke("Crash", nil)
+	assert.Error(t, err)
+
+	deadline := time.Now().Add(time.Second * 10)
+	for p.pid() == pid || p.pid() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("plugin has not been restarted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	out, err := p.invoke("Echo", []byte(`"hello"`))
+	assert.NoError(t, err)
+	assert.Equal(t, `"hello"`, string(out))
+
+	_, st := c.Get(ID)
+	assert.Equal(t, service.StatusServing, st)
+}
+
+func Test_Plugin_Disabled(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	s := &Service{}
+	ok, err := s.Init(&Config{}, logger, nil, nil)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	cfg := &Config{}
+	assert.NoError(t, cfg.Hydrate(rrtest.Config{ID: pluginConfig("echo", `{"disabled":true}`)}.Get(ID)))
+
+	ok, err = s.Init(cfg, logger, nil, nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	done := make(chan interface{})
+	go func() {
+		s.plugins[0].run()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 10):
+		t.Error("disabled plugin must not be restarted")
+	}
+
+	s.Stop()
+}
+
+func Test_Plugin_Middleware(t *testing.T) {
+	h := rrtest.NewHTTP(rrtest.HTTPWorker(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		_, _ = w.Write(append([]byte("hello"), b...))
+	})))
+
+	s := &Service{}
+	h.Container.Register(ID, s)
+	h.Start(t, rrtest.Config{
+		"http": `{"workers":{"pool":{"numWorkers": 1}}}`,
+		ID:     pluginConfig("guard", `{}`),
+	})
+
+	waitServing(t, s.plugins[0])
+
+	r, err := http.Get(h.URL("/"))
+	assert.NoError(t, err)
+	b, _ := ioutil.ReadAll(r.Body)
+	_ = r.Body.Close()
+
+	assert.Equal(t, 200, r.StatusCode)
+	assert.Equal(t, "guard", r.Header.Get("X-Plugin"))
+	assert.Equal(t, "hello", string(b))
+
+	for mode, status := range map[string]int{"block": 403, "fail": 200} {
+		req, _ := http.NewRequest("GET", h.URL("/"), nil)
+		req.Header.Set("X-Plugin", mode)
+
+		r, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		_ = r.Body.Close()
+
+		assert.Equal(t, status, r.StatusCode, mode)
+	}
+
+	// body is passed to the plugin and to the next handler
+	for mode, body := range map[string]string{"echo": "data", "": "hellodata"} {
+		req, _ := http.NewRequest("POST", h.URL("/"), strings.NewReader("data"))
+		req.Header.Set("X-Plugin", mode)
+
+		r, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		b, _ := ioutil.ReadAll(r.Body)
+		_ = r.Body.Close()
+
+		assert.Equal(t, body, string(b), mode)
+	}
+}
+
+func Test_Plugin_ServeAgain(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	cfg := &Config{}
+	assert.NoError(t, cfg.Hydrate(rrtest.Config{ID: pluginConfig("echo", `{}`)}.Get(ID)))
+
+	s := &Service{}
+	ok, err := s.Init(cfg, logger, nil, nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	for i := 0; i < 2; i++ {
+		done := make(chan error)
+		go func() { done <- s.Serve() }()
+
+		waitServing(t, s.plugins[0])
+		s.Stop()
+		assert.NoError(t, <-done)
+	}
+}
+
+// pid returns pid of the serving plugin process, 0 if plugin is not serving.
+func (p *plugin) pid() int {
+	if client, _ := p.state(); client == nil {
+		return 0
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.cmd.Process.Pid
+}
+
+// waitServing waits until plugin is serving.
+func waitServing(t *testing.T, p *plugin) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second * 10)
+	for {
+		if client, _ := p.state(); client != nil {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("plugin is not serving")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,183 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spiral/roadrunner/service"
+	rrhttp "github.com/spiral/roadrunner/service/http"
+)
+
+const (
+	// TokenBucket allows bursts up to the bucket size, bucket is refilled with limit tokens per period.
+	TokenBucket = "token-bucket"
+
+	// SlidingWindow allows limit requests within any period, counters of the current and previous windows are
+	// used to approximate the rate.
+	SlidingWindow = "sliding-window"
+
+	// KeyIP limits requests by client ip, proxy headers are used when remote address is trusted.
+	KeyIP = "ip"
+
+	// prefixes of the keys taken from the request
+	keyHeader    = "header:"
+	keyCookie    = "cookie:"
+	keyAttribute = "attribute:"
+)
+
+// Config configures rate limits of the http requests.
+type Config struct {
+	// Rules are matched in order of declaration, request is limited by the first matching rule. Requests not
+	// matching any rule are not limited, service is disabled when no rules are defined.
+	Rules []*Rule
+
+	// Interval defines how often idle buckets are removed, defaults to 1 minute.
+	Interval time.Duration
+}
+
+// Rule limits matching requests.
+type Rule struct {
+	// Name identifies the rule in the rpc calls, defaults to "rule<index>".
+	Name string
+
+	// Route matches requests limited by the rule, all requests are matched when omitted.
+	Route *rrhttp.RouteConfig
+
+	// Algorithm is token-bucket (default) or sliding-window.
+	Algorithm string
+
+	// Limit is the number of requests allowed within the period.
+	Limit int64
+
+	// Period of the limit in seconds, defaults to 60.
+	Period time.Duration
+
+	// Burst is the token bucket size, defaults to limit.
+	Burst int64
+
+	// Key selects the bucket of the request: ip (default), header:<name>, cookie:<name> or attribute:<name> (PSR-7
+	// request attribute). Requests without the key value are limited by client ip.
+	Key string
+}
+
+// Hydrate must populate Config values using given Config source. Must return error if Config is not valid.
+func (c *Config) Hydrate(cfg service.Config) error {
+	c.initDefaults()
+	if err := cfg.Unmarshal(c); err != nil {
+		return err
+	}
+
+	// interval is defined in seconds
+	if c.Interval < time.Microsecond {
+		c.Interval = time.Second * time.Duration(c.Interval.Nanoseconds())
+	}
+
+	for i, r := range c.Rules {
+		if r == nil {
+			return fmt.Errorf("rule #%v is empty", i)
+		}
+
+		r.initDefaults(i)
+		if err := r.Valid(); err != nil {
+			return fmt.Errorf("rule `%s`: %s", r.Name, err)
+		}
+
+		if r.Route != nil {
+			if err := r.Route.Compile(); err != nil {
+				return fmt.Errorf("rule `%s`: %s", r.Name, err)
+			}
+		}
+	}
+
+	return c.Valid()
+}
+
+// initDefaults sets missing values to their default values.
+func (c *Config) initDefaults() {
+	c.Interval = time.Minute
+}
+
+// Valid validates the configuration.
+func (c *Config) Valid() error {
+	if c.Interval <= 0 {
+		return errors.New("interval must be positive")
+	}
+
+	names := make(map[string]bool, len(c.Rules))
+	for _, r := range c.Rules {
+		if names[r.Name] {
+			return fmt.Errorf("duplicate rule `%s`", r.Name)
+		}
+		names[r.Name] = true
+	}
+
+	return nil
+}
+
+// initDefaults sets missing rule values to their defaults.
+func (r *Rule) initDefaults(index int) {
+	if r.Name == "" {
+		r.Name = fmt.Sprintf("rule%v", index)
+	}
+
+	if r.Algorithm == "" {
+		r.Algorithm = TokenBucket
+	}
+
+	if r.Key == "" {
+		r.Key = KeyIP
+	}
+
+	if r.Period == 0 {
+		r.Period = time.Minute
+	}
+
+	// period is defined in seconds
+	if r.Period < time.Microsecond {
+		r.Period = time.Second * time.Duration(r.Period.Nanoseconds())
+	}
+
+	if r.Burst == 0 {
+		r.Burst = r.Limit
+	}
+}
+
+// Valid validates the rule.
+func (r *Rule) Valid() error {
+	switch r.Algorithm {
+	case TokenBucket, SlidingWindow:
+	default:
+		return fmt.Errorf("invalid algorithm `%s`", r.Algorithm)
+	}
+
+	if r.Limit <= 0 {
+		return errors.New("limit must be positive")
+	}
+
+	if r.Period <= 0 {
+		return errors.New("period must be positive")
+	}
+
+	if r.Burst < 0 {
+		return errors.New("burst must not be negative")
+	}
+
+	if r.Key != KeyIP && keyName(r.Key) == "" {
+		return fmt.Errorf("invalid key `%s`", r.Key)
+	}
+
+	return nil
+}
+
+// keyName returns name of the header, cookie or attribute of the key, empty string if key is malformed.
+func keyName(key string) string {
+	for _, prefix := range []string{keyHeader, keyCookie, keyAttribute} {
+		if strings.HasPrefix(key, prefix) {
+			return key[len(prefix):]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spiral/roadrunner/rrtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Config_Hydrate(t *testing.T) {
+	cfg := &Config{}
+	assert.NoError(t, cfg.Hydrate(rrtest.Config{ID: `{"rules": [{"limit": 10}, {"name": "api", "limit": 5, "period": 1, "burst": 10}]}`}.Get(ID)))
+
+	assert.Equal(t, time.Minute, cfg.Interval)
+
+	r := cfg.Rules[0]
+	assert.Equal(t, "rule0", r.Name)
+	assert.Equal(t, TokenBucket, r.Algorithm)
+	assert.Equal(t, KeyIP, r.Key)
+	assert.Equal(t, time.Minute, r.Period)
+	assert.Equal(t, int64(10), r.Burst)
+
+	r = cfg.Rules[1]
+	assert.Equal(t, "api", r.Name)
+	assert.Equal(t, time.Second, r.Period)
+	assert.Equal(t, int64(10), r.Burst)
+}
+
+func Test_Config_Hydrate_Error(t *testing.T) {
+	for _, c := range []string{
+		`{"rules": [{"limit": 0}]}`,
+		`{"rules": [{"limit": 1, "algorithm": "leaky"}]}`,
+		`{"rules": [{"limit": 1, "key": "query:id"}]}`,
+		`{"rules": [{"limit": 1, "key": "header:"}]}`,
+		`{"rules": [{"limit": 1, "route": {"regex": "["}}]}`,
+		`{"rules": [{"name": "a", "limit": 1}, {"name": "a", "limit": 1}]}`,
+		`{"rules": [null]}`,
+	} {
+		assert.Error(t, (&Config{}).Hydrate(rrtest.Config{ID: c}.Get(ID)), c)
+	}
+}
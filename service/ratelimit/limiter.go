@@ -0,0 +1,202 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// limits of the rule applied to the limiter, limits can be changed at runtime.
+type limits struct {
+	limit  int64
+	burst  int64
+	period time.Duration
+}
+
+// State describes bucket of the request key.
+type State struct {
+	// Limit is the number of requests allowed within the period (token bucket size for token-bucket).
+	Limit int64
+
+	// Remaining is the number of requests which can be served immediately.
+	Remaining int64
+
+	// Reset is the time until limit is fully restored.
+	Reset time.Duration
+
+	// RetryAfter is the time until the next request can be served, zero if request can be served immediately.
+	RetryAfter time.Duration
+}
+
+// limiter tracks requests of the single key.
+type limiter interface {
+	// take consumes one request, returns false if limit is exceeded.
+	take(l limits, now time.Time) (bool, State)
+
+	// state returns current state of the limiter without consuming the request.
+	state(l limits, now time.Time) State
+
+	// idle returns true if limiter is fully restored and can be removed.
+	idle(l limits, now time.Time) bool
+}
+
+// newLimiter creates limiter of the given algorithm.
+func newLimiter(algorithm string, l limits, now time.Time) limiter {
+	if algorithm == SlidingWindow {
+		return &slidingWindow{start: now}
+	}
+
+	return &tokenBucket{tokens: float64(l.burst), last: now}
+}
+
+// tokenBucket is refilled with limit tokens per period up to the burst size, every request takes one token.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take consumes one token.
+func (b *tokenBucket) take(l limits, now time.Time) (bool, State) {
+	b.refill(l, now)
+	if b.tokens < 1 {
+		return false, b.state(l, now)
+	}
+
+	b.tokens--
+	return true, b.state(l, now)
+}
+
+// state returns current state of the bucket.
+func (b *tokenBucket) state(l limits, now time.Time) State {
+	b.refill(l, now)
+
+	s := State{Limit: l.burst, Remaining: int64(b.tokens)}
+	s.Reset = b.fill(l, float64(l.burst))
+	if b.tokens < 1 {
+		s.RetryAfter = b.fill(l, 1)
+	}
+
+	return s
+}
+
+// idle returns true if bucket is full.
+func (b *tokenBucket) idle(l limits, now time.Time) bool {
+	b.refill(l, now)
+	return b.tokens >= float64(l.burst)
+}
+
+// refill adds tokens accumulated since the last refill.
+func (b *tokenBucket) refill(l limits, now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += rate(l) * elapsed.Seconds()
+		b.last = now
+	}
+
+	// burst might be reduced at runtime
+	b.tokens = math.Min(b.tokens, float64(l.burst))
+}
+
+// fill returns time until bucket contains given number of tokens.
+func (b *tokenBucket) fill(l limits, tokens float64) time.Duration {
+	if b.tokens >= tokens {
+		return 0
+	}
+
+	return time.Duration((tokens - b.tokens) / rate(l) * float64(time.Second))
+}
+
+// rate returns number of tokens added per second.
+func rate(l limits) float64 {
+	return float64(l.limit) / l.period.Seconds()
+}
+
+// slidingWindow counts requests of the current and previous fixed windows, rate is estimated assuming requests
+// of the previous window were evenly distributed.
+type slidingWindow struct {
+	start    time.Time
+	current  int64
+	previous int64
+}
+
+// take counts the request if estimated rate is below the limit.
+func (w *slidingWindow) take(l limits, now time.Time) (bool, State) {
+	w.advance(l, now)
+	if w.estimate(l, now) >= float64(l.limit) {
+		return false, w.state(l, now)
+	}
+
+	w.current++
+	return true, w.state(l, now)
+}
+
+// state returns current state of the window.
+func (w *slidingWindow) state(l limits, now time.Time) State {
+	w.advance(l, now)
+
+	s := State{Limit: l.limit}
+	if remaining := float64(l.limit) - w.estimate(l, now); remaining > 0 {
+		s.Remaining = int64(remaining)
+	}
+
+	// requests leave the sliding window at the end of the window following their own
+	switch {
+	case w.current != 0:
+		s.Reset = w.start.Add(2 * l.period).Sub(now)
+	case w.previous != 0:
+		s.Reset = w.start.Add(l.period).Sub(now)
+	}
+
+	if s.Remaining == 0 {
+		s.RetryAfter = w.retry(l, now)
+	}
+
+	return s
+}
+
+// idle returns true if no requests were made within the last two windows.
+func (w *slidingWindow) idle(l limits, now time.Time) bool {
+	w.advance(l, now)
+	return w.current == 0 && w.previous == 0
+}
+
+// advance moves the window to the given moment.
+func (w *slidingWindow) advance(l limits, now time.Time) {
+	passed := now.Sub(w.start) / l.period
+	switch {
+	case passed <= 0:
+		return
+	case passed == 1:
+		w.previous, w.current = w.current, 0
+	default:
+		w.previous, w.current = 0, 0
+	}
+
+	w.start = w.start.Add(passed * l.period)
+}
+
+// elapsed returns fraction of the current window elapsed at the given moment.
+func (w *slidingWindow) elapsed(l limits, now time.Time) float64 {
+	return float64(now.Sub(w.start)) / float64(l.period)
+}
+
+// estimate returns number of requests made within the period preceding the given moment.
+func (w *slidingWindow) estimate(l limits, now time.Time) float64 {
+	return float64(w.previous)*(1-w.elapsed(l, now)) + float64(w.current)
+}
+
+// retry returns time until estimated rate falls below the limit.
+func (w *slidingWindow) retry(l limits, now time.Time) time.Duration {
+	limit := float64(l.limit)
+
+	// requests of the current window must leave the next window: current * (1 - elapsed) < limit
+	at := 2 - limit/float64(w.current)
+	if w.current < l.limit {
+		// requests of the previous window must leave the window: previous * (1 - elapsed) < limit - current
+		at = 1 - (limit-float64(w.current))/float64(w.previous)
+	}
+
+	if retry := time.Duration((at - w.elapsed(l, now)) * float64(l.period)); retry > 0 {
+		return retry
+	}
+
+	return 0
+}
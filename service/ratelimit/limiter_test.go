@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TokenBucket(t *testing.T) {
+	l := limits{limit: 2, burst: 4, period: time.Second}
+	now := time.Now()
+	b := newLimiter(TokenBucket, l, now)
+
+	for i := 3; i >= 0; i-- {
+		ok, s := b.take(l, now)
+		assert.True(t, ok)
+		assert.Equal(t, int64(i), s.Remaining)
+		assert.Equal(t, int64(4), s.Limit)
+	}
+
+	ok, s := b.take(l, now)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, s.RetryAfter)
+	assert.Equal(t, 2*time.Second, s.Reset)
+
+	// 2 tokens per second
+	now = now.Add(time.Second)
+	assert.Equal(t, int64(2), b.state(l, now).Remaining)
+	assert.False(t, b.idle(l, now))
+
+	ok, _ = b.take(l, now)
+	assert.True(t, ok)
+
+	assert.True(t, b.idle(l, now.Add(2*time.Second)))
+}
+
+func Test_TokenBucket_SetLimit(t *testing.T) {
+	l := limits{limit: 10, burst: 10, period: time.Second}
+	now := time.Now()
+	b := newLimiter(TokenBucket, l, now)
+
+	l.burst = 1
+	ok, s := b.take(l, now)
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), s.Remaining)
+
+	ok, _ = b.take(l, now)
+	assert.False(t, ok)
+}
+
+func Test_SlidingWindow(t *testing.T) {
+	l := limits{limit: 4, period: time.Minute}
+	start := time.Now()
+	w := newLimiter(SlidingWindow, l, start)
+
+	for i := 3; i >= 0; i-- {
+		ok, s := w.take(l, start)
+		assert.True(t, ok)
+		assert.Equal(t, int64(i), s.Remaining)
+	}
+
+	ok, s := w.take(l, start.Add(30*time.Second))
+	assert.False(t, ok)
+	assert.Equal(t, 90*time.Second, s.Reset)
+
+	// requests of the current window must leave the next one: 4 * (1 - elapsed) < 4
+	assert.Equal(t, 30*time.Second, s.RetryAfter)
+
+	// two thirds of the previous window are within the sliding window
+	now := start.Add(80 * time.Second)
+	s = w.state(l, now)
+	assert.Equal(t, int64(1), s.Remaining)
+	assert.Equal(t, 40*time.Second, s.Reset)
+
+	ok, _ = w.take(l, now)
+	assert.True(t, ok)
+	ok, _ = w.take(l, now)
+	assert.True(t, ok)
+
+	ok, s = w.take(l, now)
+	assert.False(t, ok)
+
+	// previous window requests must leave the window: 4 * (1 - elapsed) < 4 - 2
+	assert.InDelta(t, float64(10*time.Second), float64(s.RetryAfter), float64(time.Millisecond))
+	assert.False(t, w.idle(l, now))
+	assert.True(t, w.idle(l, now.Add(2*time.Minute)))
+}
@@ -0,0 +1,61 @@
+package ratelimit
+
+import "time"
+
+type rpcServer struct{ svc *Service }
+
+// Bucket identifies bucket of the rule.
+type Bucket struct {
+	// Rule name.
+	Rule string
+
+	// Key of the bucket, empty key refers to all buckets of the rule.
+	Key string
+}
+
+// Limit changes limits of the rule, zero values keep current limits.
+type Limit struct {
+	// Rule name.
+	Rule string
+
+	// Limit is the number of requests allowed within the period.
+	Limit int64
+
+	// Period of the limit in seconds.
+	Period int64
+
+	// Burst is the token bucket size.
+	Burst int64
+}
+
+// Inspect returns state of the bucket without consuming requests.
+func (rpc *rpcServer) Inspect(b Bucket, s *State) (err error) {
+	*s, err = rpc.svc.Inspect(b.Rule, b.Key)
+	return err
+}
+
+// Buckets returns states of all active buckets of the rule by key.
+func (rpc *rpcServer) Buckets(rule string, s *map[string]State) (err error) {
+	*s, err = rpc.svc.Buckets(rule)
+	return err
+}
+
+// Reset removes bucket, all buckets of the rule are removed when key is empty.
+func (rpc *rpcServer) Reset(b Bucket, ok *bool) error {
+	if err := rpc.svc.Reset(b.Rule, b.Key); err != nil {
+		return err
+	}
+
+	*ok = true
+	return nil
+}
+
+// SetLimit changes limits of the rule at runtime.
+func (rpc *rpcServer) SetLimit(l Limit, ok *bool) error {
+	if err := rpc.svc.SetLimit(l.Rule, l.Limit, time.Duration(l.Period)*time.Second, l.Burst); err != nil {
+		return err
+	}
+
+	*ok = true
+	return nil
+}
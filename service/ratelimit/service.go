@@ -0,0 +1,335 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spiral/roadrunner/service"
+	rrhttp "github.com/spiral/roadrunner/service/http"
+	"github.com/spiral/roadrunner/service/http/attributes"
+	"github.com/spiral/roadrunner/service/rpc"
+)
+
+// ID contains default service name.
+const ID = "ratelimit"
+
+// rule tracks buckets of the configured rule.
+type rule struct {
+	mu      sync.Mutex
+	cfg     *Rule
+	limits  limits
+	buckets map[string]limiter
+}
+
+// Service limits rate of the http requests, requests exceeding the limit are responded with 429 status.
+type Service struct {
+	mu    sync.Mutex
+	cfg   *Config
+	rules []*rule
+	log   *logrus.Logger
+	http  *rrhttp.Service
+
+	stop chan struct{}
+}
+
+// Init must return configure service and return true if service hasStatus enabled. Must return error in case of
+// misconfiguration. Services must not be used without proper configuration pushed first.
+func (s *Service) Init(cfg *Config, h *rrhttp.Service, r *rpc.Service, log *logrus.Logger) (bool, error) {
+	if h == nil || len(cfg.Rules) == 0 {
+		return false, nil
+	}
+
+	if r != nil {
+		if err := r.Register(ID, &rpcServer{s}); err != nil {
+			return false, err
+		}
+	}
+
+	s.cfg = cfg
+	s.rules = newRules(cfg)
+	s.log = log
+	s.http = h
+
+	h.AddMiddleware(s.middleware)
+
+	return true, nil
+}
+
+// Serve removes idle buckets until service is stopped.
+func (s *Service) Serve() error {
+	s.mu.Lock()
+	stop := make(chan struct{})
+	s.stop = stop
+	interval := s.cfg.Interval
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			for _, r := range s.config() {
+				r.cleanup(now)
+			}
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+// Stop stops the service.
+func (s *Service) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+// Reconfigure replaces rate limit rules, all buckets are reset.
+func (s *Service) Reconfigure(cfg service.HydrateConfig) error {
+	c, ok := cfg.(*Config)
+	if !ok {
+		return fmt.Errorf("invalid config type %T", cfg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cfg, s.rules = c, newRules(c)
+	return nil
+}
+
+// Inspect returns state of the bucket of the given rule and key without consuming requests.
+func (s *Service) Inspect(name, key string) (State, error) {
+	r, err := s.rule(name)
+	if err != nil {
+		return State{}, err
+	}
+
+	return r.inspect(key, time.Now()), nil
+}
+
+// Buckets returns states of all active buckets of the rule by key.
+func (s *Service) Buckets(name string) (map[string]State, error) {
+	r, err := s.rule(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.states(time.Now()), nil
+}
+
+// Reset removes bucket of the given key, all buckets of the rule are removed when key is empty.
+func (s *Service) Reset(name, key string) error {
+	r, err := s.rule(name)
+	if err != nil {
+		return err
+	}
+
+	r.reset(key)
+	return nil
+}
+
+// SetLimit changes limits of the rule, zero values keep current limits. Buckets are preserved.
+func (s *Service) SetLimit(name string, limit int64, period time.Duration, burst int64) error {
+	r, err := s.rule(name)
+	if err != nil {
+		return err
+	}
+
+	if limit < 0 || period < 0 || burst < 0 {
+		return errors.New("limits must not be negative")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if limit != 0 {
+		r.limits.limit = limit
+	}
+
+	if period != 0 {
+		r.limits.period = period
+	}
+
+	if burst != 0 {
+		r.limits.burst = burst
+	}
+
+	return nil
+}
+
+// config returns current rules.
+func (s *Service) config() []*rule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rules
+}
+
+// rule returns rule by name.
+func (s *Service) rule(name string) (*rule, error) {
+	for _, r := range s.config() {
+		if r.cfg.Name == name {
+			return r, nil
+		}
+	}
+
+	return nil, fmt.Errorf("undefined rule `%s`", name)
+}
+
+// middleware limits requests of the first matching rule.
+func (s *Service) middleware(f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, rl := range s.config() {
+			if rl.cfg.Route != nil && !rl.cfg.Route.Match(r) {
+				continue
+			}
+
+			ok, state := rl.take(s.key(rl.cfg, r), time.Now())
+			writeHeaders(w, state)
+
+			if !ok {
+				retry := seconds(state.RetryAfter)
+				if retry < 1 {
+					retry = 1
+				}
+
+				w.Header().Set("Retry-After", strconv.FormatInt(retry, 10))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+
+			break
+		}
+
+		f(w, r)
+	}
+}
+
+// key returns bucket key of the request, client ip is used when request has no key value.
+func (s *Service) key(cfg *Rule, r *http.Request) string {
+	name := keyName(cfg.Key)
+
+	var key string
+	switch {
+	case strings.HasPrefix(cfg.Key, keyHeader):
+		key = r.Header.Get(name)
+	case strings.HasPrefix(cfg.Key, keyCookie):
+		if c, err := r.Cookie(name); err == nil {
+			key = c.Value
+		}
+	case strings.HasPrefix(cfg.Key, keyAttribute):
+		if v := attributes.Get(r, name); v != nil {
+			key = fmt.Sprint(v)
+		}
+	}
+
+	if key == "" {
+		return s.http.ClientIP(r)
+	}
+
+	return key
+}
+
+// newRules creates rules of the configuration.
+func newRules(cfg *Config) []*rule {
+	rules := make([]*rule, 0, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		rules = append(rules, &rule{
+			cfg:     r,
+			limits:  limits{limit: r.Limit, burst: r.Burst, period: r.Period},
+			buckets: make(map[string]limiter),
+		})
+	}
+
+	return rules
+}
+
+// take consumes request from the bucket of the key.
+func (r *rule) take(key string, now time.Time) (bool, State) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.buckets[key]
+	if !ok {
+		b = newLimiter(r.cfg.Algorithm, r.limits, now)
+		r.buckets[key] = b
+	}
+
+	return b.take(r.limits, now)
+}
+
+// inspect returns state of the bucket of the key, unknown keys have full limit.
+func (r *rule) inspect(key string, now time.Time) State {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.buckets[key]
+	if !ok {
+		b = newLimiter(r.cfg.Algorithm, r.limits, now)
+	}
+
+	return b.state(r.limits, now)
+}
+
+// states returns states of all buckets by key.
+func (r *rule) states(now time.Time) map[string]State {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	states := make(map[string]State, len(r.buckets))
+	for key, b := range r.buckets {
+		states[key] = b.state(r.limits, now)
+	}
+
+	return states
+}
+
+// reset removes bucket of the key or all buckets when key is empty.
+func (r *rule) reset(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if key == "" {
+		r.buckets = make(map[string]limiter)
+		return
+	}
+
+	delete(r.buckets, key)
+}
+
+// cleanup removes fully restored buckets.
+func (r *rule) cleanup(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, b := range r.buckets {
+		if b.idle(r.limits, now) {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// writeHeaders sends RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers.
+func writeHeaders(w http.ResponseWriter, state State) {
+	h := w.Header()
+	h.Set("RateLimit-Limit", strconv.FormatInt(state.Limit, 10))
+	h.Set("RateLimit-Remaining", strconv.FormatInt(state.Remaining, 10))
+	h.Set("RateLimit-Reset", strconv.FormatInt(seconds(state.Reset), 10))
+}
+
+// seconds rounds duration up to seconds.
+func seconds(d time.Duration) int64 {
+	return int64((d + time.Second - 1) / time.Second)
+}
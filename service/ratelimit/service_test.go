@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/spiral/roadrunner/rrtest"
+	"github.com/spiral/roadrunner/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func startLimited(t *testing.T, cfg string) (*rrtest.HTTP, *Service) {
+	svc := &Service{}
+	h := rrtest.StartHTTP(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}), ID, svc, rrtest.Config{ID: cfg})
+
+	return h, svc
+}
+
+func Test_Service_Disabled(t *testing.T) {
+	for _, cfg := range []rrtest.Config{nil, {ID: `{"rules": []}`}} {
+		h := rrtest.StartHTTP(t, http.NotFoundHandler(), ID, &Service{}, cfg)
+
+		_, st := h.Container.Get(ID)
+		assert.Equal(t, service.StatusInactive, st)
+	}
+}
+
+func Test_Service_Limit(t *testing.T) {
+	h, _ := startLimited(t, `{"rules": [{"limit": 2, "period": 60}]}`)
+
+	rsp, _ := h.Get(t, "/", nil)
+	assert.Equal(t, http.StatusOK, rsp.StatusCode)
+	assert.Equal(t, "2", rsp.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rsp.Header.Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", rsp.Header.Get("RateLimit-Reset"))
+
+	h.Get(t, "/", nil)
+
+	rsp, _ = h.Get(t, "/", nil)
+	assert.Equal(t, http.StatusTooManyRequests, rsp.StatusCode)
+	assert.Equal(t, "0", rsp.Header.Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", rsp.Header.Get("Retry-After"))
+
+	// trusted proxy resolves another client
+	rsp, _ = h.Get(t, "/", map[string]string{"X-Real-Ip": "10.0.0.1"})
+	assert.Equal(t, http.StatusOK, rsp.StatusCode)
+}
+
+func Test_Service_Routes(t *testing.T) {
+	h, _ := startLimited(t, `{"rules": [
+		{"name": "api", "route": {"path": "/api"}, "limit": 1, "key": "header:X-Api-Key", "algorithm": "sliding-window"},
+		{"name": "login", "route": {"path": "/login", "methods": ["POST"]}, "limit": 1}
+	]}`)
+
+	rsp, _ := h.Get(t, "/api", map[string]string{"X-Api-Key": "a"})
+	assert.Equal(t, http.StatusOK, rsp.StatusCode)
+
+	rsp, _ = h.Get(t, "/api", map[string]string{"X-Api-Key": "a"})
+	assert.Equal(t, http.StatusTooManyRequests, rsp.StatusCode)
+
+	rsp, _ = h.Get(t, "/api", map[string]string{"X-Api-Key": "b"})
+	assert.Equal(t, http.StatusOK, rsp.StatusCode)
+
+	// requests not matching any rule are not limited
+	for i := 0; i < 3; i++ {
+		rsp, _ := h.Get(t, "/login", nil)
+		assert.Equal(t, http.StatusOK, rsp.StatusCode)
+		assert.Equal(t, "", rsp.Header.Get("RateLimit-Limit"))
+	}
+}
+
+func Test_Service_Key(t *testing.T) {
+	svc := &Service{}
+
+	r, _ := http.NewRequest("GET", "http://localhost/", nil)
+	r.Header.Set("X-Key", "header")
+	r.AddCookie(&http.Cookie{Name: "session", Value: "cookie"})
+
+	assert.Equal(t, "header", svc.key(&Rule{Key: "header:X-Key"}, r))
+	assert.Equal(t, "cookie", svc.key(&Rule{Key: "cookie:session"}, r))
+}
+
+func Test_Service_RPC(t *testing.T) {
+	h, svc := startLimited(t, `{"rules": [{"name": "all", "limit": 1, "period": 60}]}`)
+	rpc := &rpcServer{svc}
+
+	h.Get(t, "/", nil)
+	rsp, _ := h.Get(t, "/", nil)
+	assert.Equal(t, http.StatusTooManyRequests, rsp.StatusCode)
+
+	buckets := make(map[string]State)
+	assert.NoError(t, rpc.Buckets("all", &buckets))
+	assert.Contains(t, buckets, "127.0.0.1")
+
+	s := State{}
+	assert.NoError(t, rpc.Inspect(Bucket{Rule: "all", Key: "127.0.0.1"}, &s))
+	assert.Equal(t, int64(0), s.Remaining)
+	assert.InDelta(t, float64(60*time.Second), float64(s.RetryAfter), float64(time.Second))
+
+	ok := false
+	assert.NoError(t, rpc.Reset(Bucket{Rule: "all", Key: "127.0.0.1"}, &ok))
+	assert.True(t, ok)
+
+	rsp, _ = h.Get(t, "/", nil)
+	assert.Equal(t, http.StatusOK, rsp.StatusCode)
+
+	// burst is raised, bucket is refilled over time
+	assert.NoError(t, rpc.SetLimit(Limit{Rule: "all", Limit: 60, Burst: 3}, &ok))
+	assert.NoError(t, rpc.Reset(Bucket{Rule: "all"}, &ok))
+	for i := 0; i < 3; i++ {
+		rsp, _ = h.Get(t, "/", nil)
+		assert.Equal(t, http.StatusOK, rsp.StatusCode)
+	}
+
+	assert.Error(t, rpc.Reset(Bucket{Rule: "missing"}, &ok))
+	assert.Error(t, rpc.SetLimit(Limit{Rule: "all", Limit: -1}, &ok))
+}
@@ -17,17 +17,8 @@ var testServiceName = "test"
 // scenario
 // Create walker instance, init with default config, check that Watcher found all files from config
 func Test_Correct_Watcher_Init(t *testing.T) {
-	tempDir, err := ioutil.TempDir(".", "")
-	defer func() {
-		err = freeResources(tempDir)
-		if err != nil {
-			t.Fatal(err)
-		}
-	}()
-	if err != nil {
-		t.Fatal(err)
-	}
-	err = ioutil.WriteFile(filepath.Join(tempDir, "file.txt"),
+	tempDir := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(tempDir, "file.txt"),
 		[]byte{}, 0755)
 	if err != nil {
 		t.Fatal(err)
@@ -58,21 +49,13 @@ func Test_Correct_Watcher_Init(t *testing.T) {
 // Start poll events
 // change file and see, if event had come to handler
 func Test_Get_FileEvent(t *testing.T) {
-	tempDir, err := ioutil.TempDir(".", "")
+	tempDir := t.TempDir()
 	c := make(chan struct{})
-	defer func(name string) {
-		err = freeResources(name)
-		if err != nil {
-			c <- struct{}{}
-			t.Fatal(err)
-		}
+	defer func() {
 		c <- struct{}{}
-	}(tempDir)
+	}()
 
-	if err != nil {
-		t.Fatal(err)
-	}
-	err = ioutil.WriteFile(filepath.Join(tempDir, "file1.txt"),
+	err := ioutil.WriteFile(filepath.Join(tempDir, "file1.txt"),
 		[]byte{}, 0755)
 	if err != nil {
 		t.Fatal(err)
@@ -110,7 +93,7 @@ func Test_Get_FileEvent(t *testing.T) {
 		t.Fatal("incorrect directories len")
 	}
 
-	go limitTime(time.Second * 10, t.Name(), c)
+	go limitTime(time.Second*10, t.Name(), c)
 
 	go func() {
 		go func() {
@@ -144,21 +127,13 @@ func Test_Get_FileEvent(t *testing.T) {
 // Start poll events
 // change file with txt extension, and see, if event had not come to handler because it was filtered
 func Test_FileExtensionFilter(t *testing.T) {
-	tempDir, err := ioutil.TempDir(".", "")
+	tempDir := t.TempDir()
 	c := make(chan struct{})
-	defer func(name string) {
-		err = freeResources(name)
-		if err != nil {
-			c <- struct{}{}
-			t.Fatal(err)
-		}
+	defer func() {
 		c <- struct{}{}
-	}(tempDir)
+	}()
 
-	if err != nil {
-		t.Fatal(err)
-	}
-	err = ioutil.WriteFile(filepath.Join(tempDir, "file1.aaa"),
+	err := ioutil.WriteFile(filepath.Join(tempDir, "file1.aaa"),
 		[]byte{}, 0755)
 	if err != nil {
 		t.Fatal(err)
@@ -203,18 +178,14 @@ func Test_FileExtensionFilter(t *testing.T) {
 		t.Fatalf("incorrect directories len, len is: %d", dirLen)
 	}
 
-	go limitTime(time.Second * 5, t.Name(), c)
+	go limitTime(time.Second*5, t.Name(), c)
 
 	go func() {
-		go func() {
-			err2 := ioutil.WriteFile(filepath.Join(tempDir, "file3.txt"),
-				[]byte{1, 1, 1}, 0755)
-			if err2 != nil {
-				panic(err2)
-			}
-
-			runtime.Goexit()
-		}()
+		err2 := ioutil.WriteFile(filepath.Join(tempDir, "file3.txt"),
+			[]byte{1, 1, 1}, 0755)
+		if err2 != nil {
+			panic(err2)
+		}
 
 		go func() {
 			for e := range w.Event {
@@ -238,13 +209,7 @@ func Test_FileExtensionFilter(t *testing.T) {
 // make files with aaa, bbb and txt extensions, filter txt
 // change not filtered file, handle event
 func Test_Recursive_Support(t *testing.T) {
-	tempDir, err := ioutil.TempDir(".", "")
-	defer func() {
-		err = freeResources(tempDir)
-		if err != nil {
-			t.Fatal(err)
-		}
-	}()
+	tempDir := t.TempDir()
 
 	nestedDir, err := ioutil.TempDir(tempDir, "nested")
 	if err != nil {
@@ -355,16 +320,11 @@ func Test_Wrong_Dir(t *testing.T) {
 }
 
 func Test_Filter_Directory(t *testing.T) {
-	tempDir, err := ioutil.TempDir(".", "")
+	tempDir := t.TempDir()
 	c := make(chan struct{})
-	defer func(name string) {
-		err = freeResources(name)
-		if err != nil {
-			c <- struct{}{}
-			t.Fatal(err)
-		}
+	defer func() {
 		c <- struct{}{}
-	}(tempDir)
+	}()
 
 	go limitTime(time.Second*10, t.Name(), c)
 
@@ -459,14 +419,9 @@ func Test_Filter_Directory(t *testing.T) {
 // copy files from nested dir to not ignored
 // should fire an event
 func Test_Copy_Directory(t *testing.T) {
-	tempDir, err := ioutil.TempDir(".", "")
+	tempDir := t.TempDir()
 	c := make(chan struct{})
 	defer func() {
-		err = freeResources(tempDir)
-		if err != nil {
-			c <- struct{}{}
-			t.Fatal(err)
-		}
 		c <- struct{}{}
 	}()
 
@@ -667,7 +622,3 @@ func copyDir(src string, dst string) (err error) {
 
 	return
 }
-
-func freeResources(path string) error {
-	return os.RemoveAll(path)
-}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// RestartNever - service is never restarted, service failure stops the container.
+	RestartNever = "never"
+
+	// RestartOnFailure - service is restarted when it's Serve method returns error.
+	RestartOnFailure = "on-failure"
+
+	// RestartAlways - service is restarted whenever it's Serve method returns, unless service has been stopped
+	// by the container.
+	RestartAlways = "always"
+)
+
+// RestartPolicy defines how container handles completion of the service.
+type RestartPolicy struct {
+	// Policy defines when service must be restarted (never, on-failure, always). Defaults to never.
+	Policy string
+
+	// MaxRestarts defines how many times service can be restarted, container stops once the limit is reached.
+	// 0 - unlimited.
+	MaxRestarts int
+
+	// Backoff defines delay before the first restart, delay is doubled with each restart.
+	Backoff time.Duration
+
+	// MaxBackoff limits restart delay.
+	MaxBackoff time.Duration
+}
+
+// RestartEvent describes service restart.
+type RestartEvent struct {
+	// Service name.
+	Service string
+
+	// Restarts contains number of service restarts including the current one.
+	Restarts int
+
+	// Delay before the restart.
+	Delay time.Duration
+
+	// Error returned by the service, nil if service completed without error.
+	Error error
+}
+
+// InitDefaults sets missing values to their default values.
+func (p *RestartPolicy) InitDefaults() {
+	if p.Policy == "" {
+		p.Policy = RestartNever
+	}
+
+	if p.Backoff == 0 {
+		p.Backoff = time.Second
+	}
+
+	if p.MaxBackoff == 0 {
+		p.MaxBackoff = time.Second * 30
+	}
+
+	// durations are defined in seconds
+	if p.Backoff < time.Microsecond {
+		p.Backoff = time.Second * time.Duration(p.Backoff.Nanoseconds())
+	}
+
+	if p.MaxBackoff < time.Microsecond {
+		p.MaxBackoff = time.Second * time.Duration(p.MaxBackoff.Nanoseconds())
+	}
+}
+
+// Valid returns error if policy is not valid.
+func (p *RestartPolicy) Valid() error {
+	switch p.Policy {
+	case RestartNever, RestartOnFailure, RestartAlways:
+	default:
+		return fmt.Errorf("invalid restart policy `%s` (never, on-failure, always)", p.Policy)
+	}
+
+	if p.MaxRestarts < 0 {
+		return fmt.Errorf("invalid number of restarts (%v)", p.MaxRestarts)
+	}
+
+	return nil
+}
+
+// Next returns delay before the next restart or false if service must not be restarted.
+func (p *RestartPolicy) Next(restarts int, err error) (time.Duration, bool) {
+	if p.Policy == RestartNever || (p.Policy == RestartOnFailure && err == nil) {
+		return 0, false
+	}
+
+	if p.MaxRestarts != 0 && restarts >= p.MaxRestarts {
+		return 0, false
+	}
+
+	delay := p.Backoff
+	for i := 0; i < restarts && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	return delay, true
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRestartPolicy_InitDefaults(t *testing.T) {
+	p := &RestartPolicy{MaxBackoff: 10}
+	p.InitDefaults()
+
+	assert.Equal(t, RestartNever, p.Policy)
+	assert.Equal(t, time.Second, p.Backoff)
+	assert.Equal(t, time.Second*10, p.MaxBackoff)
+	assert.NoError(t, p.Valid())
+}
+
+func TestRestartPolicy_Valid(t *testing.T) {
+	assert.Error(t, (&RestartPolicy{Policy: "sometimes"}).Valid())
+	assert.Error(t, (&RestartPolicy{Policy: RestartAlways, MaxRestarts: -1}).Valid())
+}
+
+func TestRestartPolicy_Next(t *testing.T) {
+	err := errors.New("failure")
+
+	never := &RestartPolicy{}
+	never.InitDefaults()
+
+	_, ok := never.Next(0, err)
+	assert.False(t, ok)
+
+	onFailure := &RestartPolicy{Policy: RestartOnFailure, MaxRestarts: 5, MaxBackoff: 5}
+	onFailure.InitDefaults()
+
+	_, ok = onFailure.Next(0, nil)
+	assert.False(t, ok)
+
+	delays := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, delay := range delays {
+		d, ok := onFailure.Next(i, err)
+		assert.True(t, ok)
+		assert.Equal(t, delay, d)
+	}
+
+	_, ok = onFailure.Next(5, err)
+	assert.False(t, ok)
+
+	always := &RestartPolicy{Policy: RestartAlways}
+	always.InitDefaults()
+
+	_, ok = always.Next(100, nil)
+	assert.True(t, ok)
+}
@@ -0,0 +1,107 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/rpc"
+	"strings"
+	"sync"
+)
+
+// proxyMethod contains name of the method which receives all calls of the proxied service.
+const proxyMethod = "Call"
+
+// Proxy handles calls of the methods which are not known at compile time (for example methods of external
+// plugins). Proxy receives method name without service prefix and raw JSON params, must return JSON response.
+type Proxy func(method string, params []byte) ([]byte, error)
+
+// ProxyCall contains proxied method name and it's raw params.
+type ProxyCall struct {
+	// Method name without service prefix.
+	Method string
+
+	// Params contains JSON encoded method params.
+	Params []byte
+}
+
+// proxyService exposes proxy as net/rpc service.
+type proxyService struct {
+	proxy Proxy
+}
+
+// Call invokes proxy.
+func (p *proxyService) Call(in ProxyCall, out *json.RawMessage) error {
+	data, err := p.proxy(in.Method, in.Params)
+	if err != nil {
+		return err
+	}
+
+	if len(data) == 0 {
+		data = []byte("null")
+	}
+
+	*out = data
+	return nil
+}
+
+// proxyCodec routes calls of proxied services to proxy method, original method name is restored in response.
+type proxyCodec struct {
+	rpc.ServerCodec
+	s *Service
+
+	// method of the proxied request being read
+	method string
+
+	// original method names of proxied requests by sequence
+	mu      sync.Mutex
+	methods map[uint64]string
+}
+
+// newProxyCodec wraps server codec.
+func newProxyCodec(codec rpc.ServerCodec, s *Service) *proxyCodec {
+	return &proxyCodec{ServerCodec: codec, s: s, methods: make(map[uint64]string)}
+}
+
+// ReadRequestHeader reads request header and routes calls of proxied services.
+func (c *proxyCodec) ReadRequestHeader(r *rpc.Request) error {
+	c.method = ""
+	if err := c.ServerCodec.ReadRequestHeader(r); err != nil {
+		return err
+	}
+
+	dot := strings.LastIndex(r.ServiceMethod, ".")
+	if dot == -1 || !c.s.proxied(r.ServiceMethod[:dot]) {
+		return nil
+	}
+
+	c.method = r.ServiceMethod[dot+1:]
+
+	c.mu.Lock()
+	c.methods[r.Seq] = r.ServiceMethod
+	c.mu.Unlock()
+
+	r.ServiceMethod = r.ServiceMethod[:dot+1] + proxyMethod
+	return nil
+}
+
+// ReadRequestBody reads raw params of the proxied requests.
+func (c *proxyCodec) ReadRequestBody(body interface{}) error {
+	call, ok := body.(*ProxyCall)
+	if c.method == "" || !ok {
+		return c.ServerCodec.ReadRequestBody(body)
+	}
+
+	call.Method = c.method
+	return c.ServerCodec.ReadRequestBody(&call.Params)
+}
+
+// WriteResponse restores original method name of the proxied requests.
+func (c *proxyCodec) WriteResponse(r *rpc.Response, body interface{}) error {
+	c.mu.Lock()
+	if method, ok := c.methods[r.Seq]; ok {
+		r.ServiceMethod = method
+		delete(c.methods, r.Seq)
+	}
+	c.mu.Unlock()
+
+	return c.ServerCodec.WriteResponse(r, body)
+}
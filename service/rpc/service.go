@@ -19,6 +19,12 @@ type Service struct {
 	rpc     *rpc.Server
 	mu      sync.Mutex
 	serving bool
+
+	// names of the proxied services
+	proxies map[string]bool
+
+	// reloads server configuration
+	reload func() ([]service.ReconfigureResult, error)
 }
 
 // Init rpc service. Must return true if service is enabled.
@@ -34,7 +40,7 @@ func (s *Service) Init(cfg *Config, c service.Container, env env.Environment) (b
 		env.SetEnv("RR_RPC", cfg.Listen)
 	}
 
-	if err := s.Register("system", &systemService{c}); err != nil {
+	if err := s.Register("system", &systemService{c: c, s: s}); err != nil {
 		return false, err
 	}
 
@@ -69,7 +75,7 @@ func (s *Service) Serve() error {
 					continue
 				}
 
-				go s.rpc.ServeCodec(goridge.NewCodec(conn))
+				go s.rpc.ServeCodec(newProxyCodec(goridge.NewCodec(conn), s))
 			}
 		}
 	}()
@@ -109,6 +115,32 @@ func (s *Service) Register(name string, svc interface{}) error {
 	return s.rpc.RegisterName(name, svc)
 }
 
+// RegisterProxy publishes service which methods are handled by the given proxy. Method names are resolved at
+// the moment of the call, making possible to expose methods of the external processes.
+func (s *Service) RegisterProxy(name string, proxy Proxy) error {
+	if err := s.Register(name, &proxyService{proxy: proxy}); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.proxies == nil {
+		s.proxies = make(map[string]bool)
+	}
+	s.proxies[name] = true
+
+	return nil
+}
+
+// OnReload sets function used by system.Reload method to reload server configuration.
+func (s *Service) OnReload(reload func() ([]service.ReconfigureResult, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reload = reload
+}
+
 // Client creates new RPC client.
 func (s *Service) Client() (*rpc.Client, error) {
 	if s.cfg == nil {
@@ -122,3 +154,19 @@ func (s *Service) Client() (*rpc.Client, error) {
 
 	return rpc.NewClientWithCodec(goridge.NewClientCodec(conn)), nil
 }
+
+// reloader returns function used to reload server configuration, nil if reload is not supported.
+func (s *Service) reloader() func() ([]service.ReconfigureResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.reload
+}
+
+// proxied returns true if service methods are handled by proxy.
+func (s *Service) proxied(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.proxies[name]
+}
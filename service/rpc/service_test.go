@@ -1,6 +1,8 @@
 package rpc
 
 import (
+	"errors"
+	"fmt"
 	"github.com/spiral/roadrunner/service"
 	"github.com/spiral/roadrunner/service/env"
 	"github.com/stretchr/testify/assert"
@@ -94,3 +96,44 @@ func TestSetEnv(t *testing.T) {
 	v, _ := e.GetEnv()
 	assert.Equal(t, "tcp://localhost:9018", v["RR_RPC"])
 }
+
+func Test_Serve_Proxy(t *testing.T) {
+	s := &Service{}
+	ok, err := s.Init(&Config{Enable: true, Listen: "tcp://localhost:9019"}, service.NewContainer(nil), nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	defer s.Stop()
+
+	assert.NoError(t, s.Register("test", &testService{}))
+	assert.NoError(t, s.RegisterProxy("proxy", func(method string, params []byte) ([]byte, error) {
+		if method == "Fail" {
+			return nil, errors.New("proxy error")
+		}
+
+		return []byte(fmt.Sprintf(`{"method":"%s","params":%s}`, method, params)), nil
+	}))
+
+	go func() { assert.NoError(t, s.Serve()) }()
+	time.Sleep(time.Second)
+
+	client, err := s.Client()
+	assert.NoError(t, err)
+	defer client.Close()
+
+	var resp struct {
+		Method string
+		Params []int
+	}
+	assert.NoError(t, client.Call("proxy.Sum", []int{1, 2}, &resp))
+	assert.Equal(t, "Sum", resp.Method)
+	assert.Equal(t, []int{1, 2}, resp.Params)
+
+	var echo string
+	assert.NoError(t, client.Call("test.Echo", "hello world", &echo))
+	assert.Equal(t, "hello world", echo)
+
+	err = client.Call("proxy.Fail", true, &resp)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "proxy error")
+}
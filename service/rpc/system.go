@@ -1,10 +1,50 @@
 package rpc
 
-import "github.com/spiral/roadrunner/service"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spiral/roadrunner/service"
+)
+
+// ServiceStatus describes state of the service registered in the container.
+type ServiceStatus struct {
+	// Name of the service.
+	Name string `json:"name"`
+
+	// Status of the service (disabled, enabled, serving, stopping, stopped, failed, restarting).
+	Status string `json:"status"`
+
+	// Healthy is false when service has failed, stopped or waits to be restarted.
+	Healthy bool `json:"healthy"`
+
+	// Uptime of the service in seconds, 0 if service is not serving.
+	Uptime int64 `json:"uptime"`
+
+	// Restarts contains number of service restarts.
+	Restarts int `json:"restarts"`
+
+	// Error contains last service error, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// ReloadResult describes config changes applied to the service.
+type ReloadResult struct {
+	// Service name.
+	Service string `json:"service"`
+
+	// RestartRequired is true when service must be restarted to apply config changes.
+	RestartRequired bool `json:"restartRequired"`
+
+	// Error contains reconfiguration error, if any.
+	Error string `json:"error,omitempty"`
+}
 
 // systemService service controls rr server.
 type systemService struct {
 	c service.Container
+	s *Service
 }
 
 // Detach the underlying c.
@@ -16,3 +56,94 @@ func (s *systemService) Stop(stop bool, r *string) error {
 
 	return nil
 }
+
+// Upgrade requests zero-downtime upgrade of the server binary, upgrade is performed in background.
+func (s *systemService) Upgrade(upgrade bool, r *string) error {
+	if upgrade {
+		if err := requestUpgrade(); err != nil {
+			return err
+		}
+	}
+	*r = "OK"
+
+	return nil
+}
+
+// Reload reloads server configuration and applies it to the services which config has changed.
+func (s *systemService) Reload(reload bool, r *[]ReloadResult) error {
+	fn := s.s.reloader()
+	if fn == nil {
+		return errors.New("config reload is not supported")
+	}
+
+	results, err := fn()
+	if err != nil {
+		return err
+	}
+
+	*r = make([]ReloadResult, 0, len(results))
+	for _, res := range results {
+		rr := ReloadResult{Service: res.Service, RestartRequired: res.RestartRequired}
+		if res.Error != nil {
+			rr.Error = res.Error.Error()
+		}
+		*r = append(*r, rr)
+	}
+
+	return nil
+}
+
+// Services returns status of all registered services.
+func (s *systemService) Services(list bool, r *[]ServiceStatus) error {
+	states := s.c.States()
+
+	*r = make([]ServiceStatus, 0, len(states))
+	for _, st := range states {
+		*r = append(*r, serviceStatus(st))
+	}
+
+	return nil
+}
+
+// Status returns status of the given service.
+func (s *systemService) Status(name string, r *ServiceStatus) error {
+	for _, st := range s.c.States() {
+		if st.Name == name {
+			*r = serviceStatus(st)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("undefined service `%s`", name)
+}
+
+// serviceStatus converts container state into service status.
+func serviceStatus(st service.State) ServiceStatus {
+	r := ServiceStatus{Name: st.Name, Restarts: st.Restarts, Healthy: true}
+	if st.Error != nil {
+		r.Error = st.Error.Error()
+	}
+
+	switch st.Status {
+	case service.StatusInactive:
+		r.Status = "disabled"
+	case service.StatusOK:
+		r.Status = "enabled"
+	case service.StatusServing:
+		r.Status = "serving"
+		r.Uptime = int64(time.Since(st.Started) / time.Second)
+	case service.StatusStopping:
+		r.Status, r.Healthy = "stopping", false
+	case service.StatusStopped:
+		r.Status, r.Healthy = "stopped", false
+		if st.Error != nil {
+			r.Status = "failed"
+		}
+	case service.StatusRestarting:
+		r.Status, r.Healthy = "restarting", false
+	default:
+		r.Status, r.Healthy = "undefined", false
+	}
+
+	return r
+}
@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spiral/roadrunner/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_System_Services(t *testing.T) {
+	c := service.NewContainer(nil)
+	c.Register("test", &testService{})
+
+	s := &systemService{c: c, s: &Service{}}
+
+	var list []ServiceStatus
+	assert.NoError(t, s.Services(true, &list))
+	assert.Equal(t, []ServiceStatus{{Name: "test", Status: "disabled", Healthy: true}}, list)
+
+	var st ServiceStatus
+	assert.NoError(t, s.Status("test", &st))
+	assert.Equal(t, "disabled", st.Status)
+
+	assert.Error(t, s.Status("undefined", &st))
+}
+
+func Test_System_ServiceStatus(t *testing.T) {
+	st := serviceStatus(service.State{
+		Name:     "http",
+		Status:   service.StatusServing,
+		Started:  time.Now().Add(-time.Minute),
+		Restarts: 2,
+		Error:    errors.New("failure"),
+	})
+
+	assert.Equal(t, "serving", st.Status)
+	assert.True(t, st.Healthy)
+	assert.Equal(t, int64(60), st.Uptime)
+	assert.Equal(t, 2, st.Restarts)
+	assert.Equal(t, "failure", st.Error)
+
+	st = serviceStatus(service.State{Name: "http", Status: service.StatusStopped, Error: errors.New("failure")})
+	assert.Equal(t, "failed", st.Status)
+	assert.False(t, st.Healthy)
+	assert.Equal(t, int64(0), st.Uptime)
+
+	st = serviceStatus(service.State{Name: "http", Status: service.StatusRestarting})
+	assert.Equal(t, "restarting", st.Status)
+	assert.False(t, st.Healthy)
+}
+
+func Test_System_Reload(t *testing.T) {
+	rpc := &Service{}
+	s := &systemService{c: service.NewContainer(nil), s: rpc}
+
+	var r []ReloadResult
+	assert.Error(t, s.Reload(true, &r))
+
+	rpc.OnReload(func() ([]service.ReconfigureResult, error) {
+		return []service.ReconfigureResult{
+			{Service: "http"},
+			{Service: "rpc", RestartRequired: true},
+			{Service: "static", Error: errors.New("failure")},
+		}, nil
+	})
+
+	assert.NoError(t, s.Reload(true, &r))
+	assert.Equal(t, []ReloadResult{
+		{Service: "http"},
+		{Service: "rpc", RestartRequired: true},
+		{Service: "static", Error: "failure"},
+	}, r)
+
+	rpc.OnReload(func() ([]service.ReconfigureResult, error) {
+		return nil, errors.New("invalid config")
+	})
+
+	assert.Error(t, s.Reload(true, &r))
+}
@@ -0,0 +1,13 @@
+// +build !windows
+
+package rpc
+
+import (
+	"os"
+	"syscall"
+)
+
+// requestUpgrade sends SIGUSR2 to the current process, the signal is handled by serve command.
+func requestUpgrade() error {
+	return syscall.Kill(os.Getpid(), syscall.SIGUSR2)
+}
@@ -0,0 +1,10 @@
+// +build windows
+
+package rpc
+
+import "errors"
+
+// requestUpgrade is not supported on windows.
+func requestUpgrade() error {
+	return errors.New("binary upgrade is not supported on windows")
+}
@@ -0,0 +1,22 @@
+package service
+
+import "time"
+
+// StartupConfig defines container startup options.
+type StartupConfig struct {
+	// Timeout defines for how long container waits for all services to become ready. Container is stopped
+	// if any of the services is not ready within given timeout. Defaults to 60 seconds.
+	Timeout time.Duration
+}
+
+// InitDefaults sets missing values to their default values.
+func (cfg *StartupConfig) InitDefaults() {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = time.Minute
+	}
+
+	// durations are defined in seconds
+	if cfg.Timeout < time.Microsecond {
+		cfg.Timeout = time.Second * time.Duration(cfg.Timeout.Nanoseconds())
+	}
+}
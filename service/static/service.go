@@ -1,9 +1,12 @@
 package static
 
 import (
+	"fmt"
+	"github.com/spiral/roadrunner/service"
 	rrhttp "github.com/spiral/roadrunner/service/http"
 	"net/http"
 	"path"
+	"sync"
 )
 
 // ID contains default service name.
@@ -12,6 +15,7 @@ const ID = "static"
 // Service serves static files. Potentially convert into middleware?
 type Service struct {
 	// server configuration (location, forbidden files and etc)
+	mu  sync.Mutex
 	cfg *Config
 
 	// root is initiated http directory
@@ -32,38 +36,64 @@ func (s *Service) Init(cfg *Config, r *rrhttp.Service) (bool, error) {
 	return true, nil
 }
 
+// Reconfigure replaces service configuration, requests in progress are served using previous configuration.
+func (s *Service) Reconfigure(cfg service.HydrateConfig) error {
+	c, ok := cfg.(*Config)
+	if !ok {
+		return fmt.Errorf("invalid config type %T", cfg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cfg = c
+	s.root = http.Dir(c.Dir)
+
+	return nil
+}
+
+// config returns current service configuration and root directory.
+func (s *Service) config() (*Config, http.Dir) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cfg, s.root
+}
+
 // middleware must return true if request/response pair is handled within the middleware.
 func (s *Service) middleware(f http.HandlerFunc) http.HandlerFunc {
 	// Define the http.HandlerFunc
 	return func(w http.ResponseWriter, r *http.Request) {
-		if s.cfg.Request != nil {
-			for k, v := range s.cfg.Request {
+		cfg, root := s.config()
+
+		if cfg.Request != nil {
+			for k, v := range cfg.Request {
 				r.Header.Add(k, v)
 			}
 		}
 
-		if s.cfg.Response != nil {
-			for k, v := range s.cfg.Response {
+		if cfg.Response != nil {
+			for k, v := range cfg.Response {
 				w.Header().Set(k, v)
 			}
 		}
 
-		if !s.handleStatic(w, r) {
+		if !s.handleStatic(cfg, root, w, r) {
 			f(w, r)
 		}
 	}
 }
 
-func (s *Service) handleStatic(w http.ResponseWriter, r *http.Request) bool {
+func (s *Service) handleStatic(cfg *Config, root http.Dir, w http.ResponseWriter, r *http.Request) bool {
 	fPath := path.Clean(r.URL.Path)
 
-	if s.cfg.AlwaysForbid(fPath) {
+	if cfg.AlwaysForbid(fPath) {
 		return false
 	}
 
-	f, err := s.root.Open(fPath)
+	f, err := root.Open(fPath)
 	if err != nil {
-		if s.cfg.AlwaysServe(fPath) {
+		if cfg.AlwaysServe(fPath) {
 			w.WriteHeader(404)
 			return true
 		}
@@ -11,6 +11,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -529,3 +530,20 @@ func all(fn string) string {
 
 	return b.String()
 }
+
+func Test_Reconfigure(t *testing.T) {
+	s := &Service{cfg: &Config{Dir: "../../tests"}, root: http.Dir("../../tests")}
+	h := s.middleware(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest("GET", "/sample.txt", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.NoError(t, s.Reconfigure(&Config{Dir: "../../tests", Forbid: []string{".txt"}}))
+
+	w = httptest.NewRecorder()
+	h(w, httptest.NewRequest("GET", "/sample.txt", nil))
+	assert.Equal(t, http.StatusTeapot, w.Code)
+}
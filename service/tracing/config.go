@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spiral/roadrunner/service"
+)
+
+const (
+	// ExporterGRPC sends spans to the OpenTelemetry collector using OTLP over gRPC.
+	ExporterGRPC = "otlp-grpc"
+
+	// ExporterHTTP sends spans to the OpenTelemetry collector using OTLP over HTTP.
+	ExporterHTTP = "otlp-http"
+
+	// ExporterStdout writes spans to the stdout as json, intended for debugging.
+	ExporterStdout = "stdout"
+
+	// ExporterFile writes spans to the file as json, intended for debugging.
+	ExporterFile = "file"
+)
+
+// Config configures tracing of the http requests.
+type Config struct {
+	// Exporter defines where spans are sent: otlp-grpc (default), otlp-http, stdout or file.
+	Exporter string
+
+	// Endpoint is host:port of the OpenTelemetry collector, defaults to localhost:4317 for otlp-grpc and
+	// localhost:4318 for otlp-http.
+	Endpoint string
+
+	// Insecure disables TLS of the collector connection.
+	Insecure bool
+
+	// Headers are sent to the collector with every export request, i.e. authorization tokens.
+	Headers map[string]string
+
+	// File is the path of the file exporter output, spans are appended to the file.
+	File string
+
+	// ServiceName is reported as service.name resource attribute, defaults to "roadrunner".
+	ServiceName string
+
+	// Sample defines fraction of the traced requests which have no sampled parent (0.1 - every 10th request in
+	// average), requests with sampled parent are always traced. Defaults to 1.
+	Sample float64
+}
+
+// Hydrate must populate Config values using given Config source. Must return error if Config is not valid.
+func (c *Config) Hydrate(cfg service.Config) error {
+	c.initDefaults()
+	if err := cfg.Unmarshal(c); err != nil {
+		return err
+	}
+
+	if c.Endpoint == "" {
+		switch c.Exporter {
+		case ExporterGRPC:
+			c.Endpoint = "localhost:4317"
+		case ExporterHTTP:
+			c.Endpoint = "localhost:4318"
+		}
+	}
+
+	return c.Valid()
+}
+
+// initDefaults sets missing values to their default values, tracing is opt-in and requires the config section.
+func (c *Config) initDefaults() {
+	c.Exporter = ExporterGRPC
+	c.ServiceName = "roadrunner"
+	c.Sample = 1
+}
+
+// Valid validates the configuration.
+func (c *Config) Valid() error {
+	switch c.Exporter {
+	case ExporterGRPC, ExporterHTTP, ExporterStdout:
+	case ExporterFile:
+		if c.File == "" {
+			return errors.New("trace file is missing")
+		}
+	default:
+		return fmt.Errorf("invalid trace exporter `%s`", c.Exporter)
+	}
+
+	if c.ServiceName == "" {
+		return errors.New("service name is missing")
+	}
+
+	if c.Sample < 0 || c.Sample > 1 {
+		return fmt.Errorf("invalid sample rate %v, must be within [0, 1]", c.Sample)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/spiral/roadrunner/rrtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func hydrate(cfg string) (*Config, error) {
+	c := &Config{}
+	return c, c.Hydrate(rrtest.Config{ID: cfg}.Get(ID))
+}
+
+func Test_Config_Hydrate(t *testing.T) {
+	cfg, err := hydrate(`{}`)
+	assert.NoError(t, err)
+
+	assert.Equal(t, ExporterGRPC, cfg.Exporter)
+	assert.Equal(t, "localhost:4317", cfg.Endpoint)
+	assert.Equal(t, "roadrunner", cfg.ServiceName)
+	assert.Equal(t, float64(1), cfg.Sample)
+
+	cfg, err = hydrate(`{"exporter": "otlp-http", "headers": {"Authorization": "token"}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:4318", cfg.Endpoint)
+	assert.Equal(t, "token", cfg.Headers["Authorization"])
+}
+
+func Test_Config_Hydrate_Error(t *testing.T) {
+	for _, cfg := range []string{
+		`{"exporter": "zipkin"}`,
+		`{"exporter": "file"}`,
+		`{"sample": 2}`,
+		`{"serviceName": ""}`,
+	} {
+		_, err := hydrate(cfg)
+		assert.Error(t, err, cfg)
+	}
+}
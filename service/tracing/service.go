@@ -0,0 +1,171 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	rrhttp "github.com/spiral/roadrunner/service/http"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ID contains default service name.
+const ID = "tracing"
+
+// ShutdownTimeout limits time given to the exporter to send remaining spans on stop.
+const ShutdownTimeout = 5 * time.Second
+
+// Service traces requests of the http service using OpenTelemetry. Incoming W3C trace context is continued,
+// trace context of the request is passed to the workers using traceparent and tracestate headers.
+type Service struct {
+	mu  sync.Mutex
+	cfg *Config
+	log *logrus.Logger
+	tp  *sdktrace.TracerProvider
+
+	stop chan struct{}
+}
+
+// Init must return configure service and return true if service hasStatus enabled. Must return error in case of
+// misconfiguration. Services must not be used without proper configuration pushed first.
+func (s *Service) Init(cfg *Config, h *rrhttp.Service, log *logrus.Logger) (bool, error) {
+	if h == nil {
+		return false, nil
+	}
+
+	s.cfg = cfg
+	s.log = log
+
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		s.log.Errorf("[%s]: %s", ID, err)
+	}))
+
+	h.SetTracerProvider(&provider{s: s})
+	h.AddMiddleware(s.middleware)
+
+	return true, nil
+}
+
+// Serve creates tracer provider and exporter and waits until service is stopped. Provider is shut down on stop,
+// every serve starts with the new one.
+func (s *Service) Serve() error {
+	tp, err := newProvider(s.cfg)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	stop := make(chan struct{})
+	s.tp, s.stop = tp, stop
+	s.mu.Unlock()
+
+	<-stop
+	return nil
+}
+
+// Stop sends remaining spans to the exporter, spans of the requests completed after the stop are dropped.
+func (s *Service) Stop() {
+	s.mu.Lock()
+	if s.stop == nil {
+		s.mu.Unlock()
+		return
+	}
+
+	tp := s.tp
+	close(s.stop)
+	s.tp, s.stop = nil, nil
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+
+	if err := tp.Shutdown(ctx); err != nil {
+		s.log.Errorf("[%s]: %s", ID, err)
+	}
+}
+
+// tracer returns tracer of the serving provider, no-op tracer when service is not serving.
+func (s *Service) tracer(name string) trace.Tracer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tp == nil {
+		return trace.NewNoopTracerProvider().Tracer(name)
+	}
+
+	return s.tp.Tracer(name)
+}
+
+// middleware starts server span of every request, span continues the trace of the incoming traceparent header.
+func (s *Service) middleware(f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := s.tracer(rrhttp.TracerName).Start(
+			ctx,
+			"HTTP "+r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(s.cfg.ServiceName, "", r)...),
+			trace.WithAttributes(attribute.String("rr.request_id", rrhttp.RequestID(r))),
+		)
+		defer span.End()
+
+		rw := rrhttp.WrapWriter(w)
+		f(rw.Writer(), r.WithContext(ctx))
+
+		span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(rw.Status)...)
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(rw.Status, trace.SpanKindServer))
+	}
+}
+
+// newProvider creates tracer provider exporting spans to the configured exporter.
+func newProvider(cfg *Config) (*sdktrace.TracerProvider, error) {
+	exporter, err := newExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// debugging exporters write spans as soon as they end, collectors receive spans in batches
+	processor := sdktrace.WithBatcher(exporter)
+	if cfg.Exporter == ExporterStdout || cfg.Exporter == ExporterFile {
+		processor = sdktrace.WithSyncer(exporter)
+	}
+
+	return sdktrace.NewTracerProvider(
+		processor,
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Sample))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(cfg.ServiceName),
+		)),
+	), nil
+}
+
+// provider passes spans of the http service to the provider of the current serve, http service keeps the
+// tracer while tracing service is restarted.
+type provider struct {
+	s *Service
+}
+
+// Tracer returns tracer delegating to the serving provider.
+func (p *provider) Tracer(name string, _ ...trace.TracerOption) trace.Tracer {
+	return &tracer{s: p.s, name: name}
+}
+
+// tracer starts spans using tracer of the serving provider.
+type tracer struct {
+	s    *Service
+	name string
+}
+
+// Start creates a span and a context containing it, span is not recorded when service is not serving.
+func (t *tracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return t.s.tracer(t.name).Start(ctx, name, opts...)
+}
@@ -0,0 +1,173 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	json "github.com/json-iterator/go"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiral/roadrunner/rrtest"
+	"github.com/spiral/roadrunner/service"
+	rrhttp "github.com/spiral/roadrunner/service/http"
+	"github.com/stretchr/testify/assert"
+)
+
+const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// span contains exported span values used by the tests.
+type span struct {
+	Name        string
+	SpanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	Parent struct {
+		TraceID string
+		SpanID  string
+	}
+}
+
+func startTraced(t *testing.T, cfg string) *rrtest.HTTP {
+	return rrtest.StartHTTP(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("traceparent")))
+	}), ID, &Service{}, rrtest.Config{ID: cfg})
+}
+
+func tempFile(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "rr")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	return filepath.Join(dir, "traces.json")
+}
+
+// spans reads exported spans by name, waits for the server span to be exported.
+func spans(t *testing.T, file string) map[string]span {
+	result := make(map[string]span)
+	assert.Eventually(t, func() bool {
+		f, err := os.Open(file)
+		if err != nil {
+			return false
+		}
+		defer f.Close()
+
+		s := bufio.NewScanner(f)
+		s.Buffer(nil, 1024*1024)
+		for s.Scan() {
+			sp := span{}
+			if json.Unmarshal(s.Bytes(), &sp) == nil {
+				result[sp.Name] = sp
+			}
+		}
+
+		_, ok := result["HTTP GET"]
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	return result
+}
+
+func Test_Service_Disabled(t *testing.T) {
+	h := rrtest.StartHTTP(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("traceparent")))
+	}), ID, &Service{}, nil)
+
+	_, st := h.Container.Get(ID)
+	assert.Equal(t, service.StatusInactive, st)
+
+	_, body := h.Get(t, "/", nil)
+	assert.Equal(t, "", body)
+}
+
+func Test_Service_Spans(t *testing.T) {
+	file := tempFile(t)
+	h := startTraced(t, `{"exporter": "file", "file": "`+file+`"}`)
+
+	_, body := h.Get(t, "/", map[string]string{"traceparent": traceParent})
+
+	s := spans(t, file)
+	for _, name := range []string{"rr.http.parse", "rr.http.uploads", "rr.http.payload", "rr.pool.exec", "rr.pool.allocate", "rr.worker.exec", "rr.http.write"} {
+		assert.Contains(t, s, name)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", s[name].SpanContext.TraceID)
+	}
+
+	server := s["HTTP GET"]
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID)
+
+	assert.Equal(t, server.SpanContext.SpanID, s["rr.pool.exec"].Parent.SpanID)
+	assert.Equal(t, s["rr.pool.exec"].SpanContext.SpanID, s["rr.pool.allocate"].Parent.SpanID)
+	assert.Equal(t, s["rr.pool.exec"].SpanContext.SpanID, s["rr.worker.exec"].Parent.SpanID)
+
+	// worker receives trace context of the server span
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+server.SpanContext.SpanID+"-01", body)
+}
+
+func Test_Service_NewTrace(t *testing.T) {
+	file := tempFile(t)
+	h := startTraced(t, `{"exporter": "file", "file": "`+file+`"}`)
+
+	_, body := h.Get(t, "/", nil)
+
+	server := spans(t, file)["HTTP GET"]
+	assert.Equal(t, "0000000000000000", server.Parent.SpanID)
+	assert.True(t, strings.HasPrefix(body, "00-"+server.SpanContext.TraceID+"-"))
+}
+
+func Test_Service_NotSampled(t *testing.T) {
+	file := tempFile(t)
+	h := startTraced(t, `{"exporter": "file", "file": "`+file+`", "sample": 0}`)
+
+	_, body := h.Get(t, "/", nil)
+
+	// trace context is passed even when request is not sampled
+	assert.Regexp(t, `^00-[0-9a-f]{32}-[0-9a-f]{16}-00$`, body)
+
+	time.Sleep(50 * time.Millisecond)
+	data, err := ioutil.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func Test_Service_ServeAgain(t *testing.T) {
+	file := tempFile(t)
+	logger, _ := test.NewNullLogger()
+
+	cfg := &Config{}
+	assert.NoError(t, cfg.Hydrate(rrtest.Config{ID: `{"exporter": "file", "file": "` + file + `"}`}.Get(ID)))
+
+	s := &Service{}
+	ok, err := s.Init(cfg, &rrhttp.Service{}, logger)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	tr := (&provider{s: s}).Tracer(rrhttp.TracerName)
+	for i := 0; i < 2; i++ {
+		done := make(chan error)
+		go func() { done <- s.Serve() }()
+
+		assert.Eventually(t, func() bool {
+			_, span := tr.Start(context.Background(), "HTTP GET")
+			defer span.End()
+
+			return span.IsRecording()
+		}, time.Second, 10*time.Millisecond)
+
+		s.Stop()
+		assert.NoError(t, <-done)
+
+		_, span := tr.Start(context.Background(), "HTTP GET")
+		assert.False(t, span.IsRecording())
+	}
+
+	data, err := ioutil.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), `"Name":"HTTP GET"`))
+}
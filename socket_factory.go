@@ -23,6 +23,9 @@ type SocketFactory struct {
 
 	// sockets which are waiting for process association
 	relays map[int]chan *goridge.SocketRelay
+
+	// factory which took over the listener, new connections are passed to it
+	next *SocketFactory
 }
 
 // NewSocketFactory returns SocketFactory attached to a given socket lsn.
@@ -59,7 +62,7 @@ func (f *SocketFactory) SpawnWorker(cmd *exec.Cmd) (w *Worker, err error) {
 		}(w)
 
 		if wErr := w.Wait(); wErr != nil {
-			if _, ok := wErr.(*exec.ExitError); ok {
+			if exit, ok := wErr.(*WorkerExit); ok && exit.output == "" {
 				err = errors.Wrap(wErr, err.Error())
 			} else {
 				err = wErr
@@ -75,11 +78,43 @@ func (f *SocketFactory) SpawnWorker(cmd *exec.Cmd) (w *Worker, err error) {
 	return w, nil
 }
 
-// Close socket factory and underlying socket connection.
+// Close socket factory and underlying socket connection. Listener is not closed if it has been
+// handed over to another factory.
 func (f *SocketFactory) Close() error {
+	f.mu.Lock()
+	next := f.next
+	f.mu.Unlock()
+
+	if next != nil {
+		return nil
+	}
+
 	return f.ls.Close()
 }
 
+// handoff creates factory which takes over the socket listener. Workers which are being connected by the
+// current factory still receive their relays, all other connections are passed to the new factory.
+func (f *SocketFactory) handoff(tout time.Duration) *SocketFactory {
+	next := &SocketFactory{
+		ls:     f.ls,
+		tout:   tout,
+		relays: make(map[int]chan *goridge.SocketRelay),
+	}
+
+	f.mu.Lock()
+	f.next = next
+	f.mu.Unlock()
+
+	return next
+}
+
+// revoke returns listener back to the factory after failed handoff.
+func (f *SocketFactory) revoke() {
+	f.mu.Lock()
+	f.next = nil
+	f.mu.Unlock()
+}
+
 // listens for incoming socket connections
 func (f *SocketFactory) listen() {
 	for {
@@ -90,7 +125,7 @@ func (f *SocketFactory) listen() {
 
 		rl := goridge.NewSocketRelay(conn)
 		if pid, err := fetchPID(rl); err == nil {
-			f.relayChan(pid) <- rl
+			f.dispatch(pid) <- rl
 		}
 	}
 }
@@ -116,6 +151,21 @@ func (f *SocketFactory) findRelay(w *Worker, tout time.Duration) (*goridge.Socke
 	}
 }
 
+// dispatch returns relay chan of the factory which is waiting for the given Pid, connections of unknown
+// workers are passed to the factory which took over the listener.
+func (f *SocketFactory) dispatch(pid int) chan *goridge.SocketRelay {
+	f.mu.Lock()
+	_, waiting := f.relays[pid]
+	next := f.next
+	f.mu.Unlock()
+
+	if waiting || next == nil {
+		return f.relayChan(pid)
+	}
+
+	return next.dispatch(pid)
+}
+
 // chan to store relay associated with specific Pid
 func (f *SocketFactory) relayChan(pid int) chan *goridge.SocketRelay {
 	f.mu.Lock()
@@ -1,6 +1,8 @@
 package roadrunner
 
 import (
+	json "github.com/json-iterator/go"
+	"github.com/spiral/goridge/v2"
 	"github.com/stretchr/testify/assert"
 	"net"
 	"os/exec"
@@ -535,3 +537,48 @@ func Benchmark_Unix_Worker_ExecEcho(b *testing.B) {
 		}
 	}
 }
+
+func Test_Tcp_Handoff(t *testing.T) {
+	ls, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+
+	f := NewSocketFactory(ls, time.Second)
+	defer f.Close()
+
+	// previous factory is connecting worker 100
+	waiting := f.relayChan(100)
+
+	nf := f.handoff(time.Second)
+	defer nf.Close()
+
+	connect := func(pid int) {
+		conn, err := net.Dial("tcp", ls.Addr().String())
+		assert.NoError(t, err)
+
+		rl := goridge.NewSocketRelay(conn)
+		_, _, err = rl.Receive()
+		assert.NoError(t, err)
+
+		data, _ := json.Marshal(pidCommand{Pid: pid})
+		assert.NoError(t, rl.Send(data, goridge.PayloadControl))
+	}
+
+	go connect(100)
+	select {
+	case <-waiting:
+	case <-time.After(time.Second):
+		t.Fatal("relay was not passed to the previous factory")
+	}
+
+	go connect(200)
+	select {
+	case <-nf.relayChan(200):
+	case <-time.After(time.Second):
+		t.Fatal("relay was not passed to the new factory")
+	}
+
+	// listener is owned by the new factory
+	assert.NoError(t, f.Close())
+	_, err = net.Dial("tcp", ls.Addr().String())
+	assert.NoError(t, err)
+}
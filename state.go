@@ -3,6 +3,7 @@ package roadrunner
 import (
 	"fmt"
 	"sync/atomic"
+	"time"
 )
 
 // State represents worker status and updated time.
@@ -45,6 +46,7 @@ const (
 type state struct {
 	value    int64
 	numExecs int64
+	lastTime int64
 }
 
 func newState(value int64) *state {
@@ -95,4 +97,15 @@ func (s *state) set(value int64) {
 // register new execution atomically
 func (s *state) registerExec() {
 	atomic.AddInt64(&s.numExecs, 1)
+	atomic.StoreInt64(&s.lastTime, time.Now().UnixNano())
+}
+
+// time of the last registered execution, zero if none
+func (s *state) lastExec() time.Time {
+	last := atomic.LoadInt64(&s.lastTime)
+	if last == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, last)
 }
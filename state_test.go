@@ -24,3 +24,12 @@ func Test_IsActive(t *testing.T) {
 	assert.False(t, newState(StateStopped).IsActive())
 	assert.False(t, newState(StateErrored).IsActive())
 }
+
+func Test_RegisterExec(t *testing.T) {
+	st := newState(StateReady)
+	assert.True(t, st.lastExec().IsZero())
+
+	st.registerExec()
+	assert.Equal(t, int64(1), st.NumExecs())
+	assert.False(t, st.lastExec().IsZero())
+}
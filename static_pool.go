@@ -17,7 +17,8 @@ const (
 
 // StaticPool controls worker creation, destruction and task routing. Pool uses fixed amount of workers.
 type StaticPool struct {
-	// pool behaviour
+	// pool behaviour, number of workers, job limit and timeouts can be changed at runtime
+	muc sync.RWMutex
 	cfg Config
 
 	// worker command creator
@@ -27,12 +28,17 @@ type StaticPool struct {
 	factory Factory
 
 	// active task executions
-	tmu   sync.Mutex
-	tasks sync.WaitGroup
+	tmu    sync.Mutex
+	tasks  sync.WaitGroup
+	active int64
 
-	// workers circular allocation buf
+	// workers circular allocation buf, replaced with larger buf when pool grows
+	muf  sync.RWMutex
 	free chan *Worker
 
+	// serializes pool resizing
+	mur sync.Mutex
+
 	// number of workers expected to be dead in a buf.
 	numDead int64
 
@@ -42,12 +48,19 @@ type StaticPool struct {
 	// all registered workers
 	workers []*Worker
 
+	// number of alive and spawning workers, protected by muw
+	size int64
+
 	// invalid declares set of workers to be removed from the pool.
 	remove sync.Map
 
 	// pool is being destroyed
 	inDestroy int32
 	destroy   chan interface{}
+	dsOnce    sync.Once
+
+	// active executions are being aborted
+	inAbort int32
 
 	// lsn is optional callback to handle worker create/destruct/error events.
 	mul sync.Mutex
@@ -78,6 +91,7 @@ func NewPool(cmd func() *exec.Cmd, factory Factory, cfg Config) (*StaticPool, er
 			return nil, err
 		}
 
+		p.size++
 		p.free <- w
 	}
 
@@ -98,11 +112,69 @@ func (p *StaticPool) Listen(l func(event int, ctx interface{})) {
 	p.muw.Unlock()
 }
 
-// Config returns associated pool configuration. Immutable.
+// Config returns associated pool configuration.
 func (p *StaticPool) Config() Config {
+	p.muc.RLock()
+	defer p.muc.RUnlock()
+
 	return p.cfg
 }
 
+// Reconfigure applies number of workers, job limit and timeouts to the running pool without
+// re-creating it's workers.
+func (p *StaticPool) Reconfigure(cfg Config) error {
+	if err := cfg.Valid(); err != nil {
+		return errors.Wrap(err, "config")
+	}
+
+	p.muc.Lock()
+	p.cfg.MaxJobs = cfg.MaxJobs
+	p.cfg.AllocateTimeout = cfg.AllocateTimeout
+	p.cfg.DestroyTimeout = cfg.DestroyTimeout
+	p.cfg.DrainTimeout = cfg.DrainTimeout
+	p.muc.Unlock()
+
+	return p.Resize(cfg.NumWorkers)
+}
+
+// Resize changes number of pool workers in place. New workers are spawned immediately, retired
+// workers are stopped as soon as they complete their active executions.
+func (p *StaticPool) Resize(n int64) error {
+	if n <= 0 {
+		return fmt.Errorf("invalid number of workers (%v)", n)
+	}
+
+	p.mur.Lock()
+	defer p.mur.Unlock()
+
+	if p.destroyed() {
+		return ErrPoolStopped
+	}
+
+	p.muw.Lock()
+	p.muc.Lock()
+	p.cfg.NumWorkers = n
+	p.muc.Unlock()
+
+	delta := n - p.size
+	if delta > 0 {
+		p.size += delta
+	}
+	p.muw.Unlock()
+
+	switch {
+	case delta > 0:
+		if err := p.grow(n, delta); err != nil {
+			return err
+		}
+	case delta < 0:
+		p.shrink(-delta)
+	}
+
+	p.throw(EventPoolResize, n)
+	return nil
+}
+
 // Workers returns worker list associated with the pool.
 func (p *StaticPool) Workers() (workers []*Worker) {
 	p.muw.RLock()
@@ -131,18 +203,42 @@ func (p *StaticPool) Remove(w *Worker, err error) bool {
 // Exec one task with given payload and context, returns result or error.
 func (p *StaticPool) Exec(rqs *Payload) (rsp *Payload, err error) {
 	p.tmu.Lock()
+	if p.destroyed() {
+		p.tmu.Unlock()
+		return nil, ErrPoolStopped
+	}
+
 	p.tasks.Add(1)
+	atomic.AddInt64(&p.active, 1)
 	p.tmu.Unlock()
 
-	defer p.tasks.Done()
+	defer func() {
+		atomic.AddInt64(&p.active, -1)
+		p.tasks.Done()
+	}()
+
+	if rqs.Timings != nil {
+		rqs.Timings.Allocate = time.Now()
+	}
 
 	w, err := p.allocateWorker()
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to allocate worker")
 	}
 
+	if rqs.Timings != nil {
+		rqs.Timings.Exec = time.Now()
+		if w.Pid != nil {
+			rqs.Timings.Pid = *w.Pid
+		}
+	}
+
 	rsp, err = w.Exec(rqs)
 
+	if rqs.Timings != nil {
+		rqs.Timings.Done = time.Now()
+	}
+
 	if err != nil {
 		// soft job errors are allowed
 		if _, jobError := err.(JobError); jobError {
@@ -150,7 +246,16 @@ func (p *StaticPool) Exec(rqs *Payload) (rsp *Payload, err error) {
 			return nil, err
 		}
 
+		if so, ok := err.(StrayOutputError); ok {
+			p.throw(EventWorkerStrayOutput, so)
+		}
+
 		p.discardWorker(w, err)
+
+		if atomic.LoadInt32(&p.inAbort) != 0 {
+			return nil, ErrExecAborted
+		}
+
 		return nil, err
 	}
 
@@ -164,15 +269,16 @@ func (p *StaticPool) Exec(rqs *Payload) (rsp *Payload, err error) {
 	return rsp, nil
 }
 
-// Destroy all underlying workers (but let them to complete the task).
+// Destroy all underlying workers (but let them to complete the task within drain timeout).
 func (p *StaticPool) Destroy() {
-	atomic.AddInt32(&p.inDestroy, 1)
-
+	// no new executions are accepted since this moment
 	p.tmu.Lock()
-	p.tasks.Wait()
-	close(p.destroy)
+	atomic.AddInt32(&p.inDestroy, 1)
 	p.tmu.Unlock()
 
+	p.drain()
+	p.stop()
+
 	var wg sync.WaitGroup
 	for _, w := range p.Workers() {
 		wg.Add(1)
@@ -186,14 +292,150 @@ func (p *StaticPool) Destroy() {
 	wg.Wait()
 }
 
+// drain waits for active executions to complete. Executions which did not complete within drain
+// timeout are aborted by killing their workers, executions waiting for a free worker are rejected.
+func (p *StaticPool) drain() {
+	start := time.Now()
+	event := DrainEvent{Drained: atomic.LoadInt64(&p.active)}
+
+	done := make(chan interface{})
+	go func() {
+		p.tasks.Wait()
+		close(done)
+	}()
+
+	if timeout := p.Config().DrainTimeout; timeout != 0 {
+		timer := time.NewTimer(timeout)
+		select {
+		case <-done:
+			timer.Stop()
+		case <-timer.C:
+			atomic.AddInt32(&p.inAbort, 1)
+			event.Aborted = atomic.LoadInt64(&p.active)
+			event.Drained -= event.Aborted
+
+			p.stop()
+			for _, w := range p.Workers() {
+				if w.State().Value() == StateWorking {
+					go p.abortWorker(w)
+				}
+			}
+		}
+	}
+
+	<-done
+
+	event.Elapsed = time.Since(start)
+	p.throw(EventPoolDrain, event)
+}
+
+// abortWorker kills worker with active execution.
+func (p *StaticPool) abortWorker(w *Worker) {
+	if err := w.Kill(); err != nil {
+		p.throw(EventWorkerError, WorkerError{Worker: w, Caused: err})
+	}
+
+	p.throw(EventWorkerKill, w)
+}
+
+// stop rejects all executions waiting for a free worker.
+func (p *StaticPool) stop() {
+	p.dsOnce.Do(func() {
+		close(p.destroy)
+	})
+}
+
+// grow spawns given number of workers, the allocation buf is replaced if it can't fit n workers.
+func (p *StaticPool) grow(n, delta int64) error {
+	p.muf.Lock()
+	if int64(cap(p.free)) < n {
+		free := make(chan *Worker, n)
+		for drained := false; !drained; {
+			select {
+			case w := <-p.free:
+				free <- w
+			default:
+				drained = true
+			}
+		}
+
+		// release allocations waiting on the previous buf
+		close(p.free)
+		p.free = free
+	}
+	p.muf.Unlock()
+
+	for i := int64(0); i < delta; i++ {
+		w, err := p.createWorker()
+		if err != nil {
+			p.muw.Lock()
+			p.size -= delta - i
+			p.muc.Lock()
+			p.cfg.NumWorkers -= delta - i
+			p.muc.Unlock()
+			p.muw.Unlock()
+
+			return errors.Wrap(err, "unable to grow the pool")
+		}
+
+		p.push(w)
+	}
+
+	return nil
+}
+
+// shrink retires given number of workers, idle workers are retired first.
+func (p *StaticPool) shrink(delta int64) {
+	for _, state := range []int64{StateReady, StateWorking} {
+		for _, w := range p.Workers() {
+			if delta == 0 {
+				break
+			}
+
+			if w.State().Value() == state && p.Remove(w, nil) {
+				delta--
+			}
+		}
+	}
+
+	// retired idle workers are waiting in the allocation buf
+	free := p.freeBuf()
+	for i := len(free); i > 0; i-- {
+		select {
+		case w, ok := <-free:
+			if !ok {
+				return
+			}
+
+			if err, remove := p.remove.Load(w); remove {
+				p.discardWorker(w, err)
+				continue
+			}
+
+			p.push(w)
+		default:
+			return
+		}
+	}
+}
+
 // finds free worker in a given time interval. Skips dead workers.
 func (p *StaticPool) allocateWorker() (w *Worker, err error) {
+	var ok bool
+
 	// TODO loop counts upward, but its variable is bounded downward.
 	for i := atomic.LoadInt64(&p.numDead); i >= 0; i++ {
+		free, cfg := p.freeBuf(), p.Config()
+
 		// this loop is required to skip issues with dead workers still being in a ring
 		// (we know how many workers).
 		select {
-		case w = <-p.free:
+		case w, ok = <-free:
+			if !ok {
+				// pool has been resized
+				continue
+			}
+
 			if w.State().Value() != StateReady {
 				// found expected dead worker
 				atomic.AddInt64(&p.numDead, ^int64(0))
@@ -210,18 +452,22 @@ func (p *StaticPool) allocateWorker() (w *Worker, err error) {
 
 			return w, nil
 		case <-p.destroy:
-			return nil, fmt.Errorf("pool has been stopped")
+			return nil, ErrPoolStopped
 		default:
 			// enable timeout handler
 		}
 
-		timeout := time.NewTimer(p.cfg.AllocateTimeout)
+		timeout := time.NewTimer(cfg.AllocateTimeout)
 		select {
 		case <-timeout.C:
-			return nil, fmt.Errorf("worker timeout (%s)", p.cfg.AllocateTimeout)
-		case w = <-p.free:
+			return nil, AllocateTimeoutError{Timeout: cfg.AllocateTimeout}
+		case w, ok = <-free:
 			timeout.Stop()
 
+			if !ok {
+				continue
+			}
+
 			if w.State().Value() != StateReady {
 				atomic.AddInt64(&p.numDead, ^int64(0))
 				continue
@@ -239,17 +485,17 @@ func (p *StaticPool) allocateWorker() (w *Worker, err error) {
 		case <-p.destroy:
 			timeout.Stop()
 
-			return nil, fmt.Errorf("pool has been stopped")
+			return nil, ErrPoolStopped
 		}
 	}
 
-	return nil, fmt.Errorf("all workers are dead (%v)", p.cfg.NumWorkers)
+	return nil, fmt.Errorf("all workers are dead (%v)", p.Config().NumWorkers)
 }
 
 // release releases or replaces the worker.
 func (p *StaticPool) release(w *Worker) {
-	if p.cfg.MaxJobs != 0 && w.State().NumExecs() >= p.cfg.MaxJobs {
-		p.discardWorker(w, p.cfg.MaxJobs)
+	if maxJobs := p.Config().MaxJobs; maxJobs != 0 && w.State().NumExecs() >= maxJobs {
+		p.discardWorker(w, maxJobs)
 		return
 	}
 
@@ -258,7 +504,22 @@ func (p *StaticPool) release(w *Worker) {
 		return
 	}
 
+	p.push(w)
+}
+
+// push returns worker into the allocation buf.
+func (p *StaticPool) push(w *Worker) {
+	p.muf.RLock()
 	p.free <- w
+	p.muf.RUnlock()
+}
+
+// freeBuf returns current allocation buf.
+func (p *StaticPool) freeBuf() chan *Worker {
+	p.muf.RLock()
+	defer p.muf.RUnlock()
+
+	return p.free
 }
 
 // creates new worker using associated factory. automatically
@@ -306,7 +567,7 @@ func (p *StaticPool) destroyWorker(w *Worker, caused interface{}) {
 		// worker is dead
 		p.throw(EventWorkerDestruct, w)
 
-	case <-time.NewTimer(p.cfg.DestroyTimeout).C:
+	case <-time.NewTimer(p.Config().DestroyTimeout).C:
 		// failed to stop process in given time
 		if err := w.Kill(); err != nil {
 			p.throw(EventWorkerError, WorkerError{Worker: w, Caused: err})
@@ -330,6 +591,13 @@ func (p *StaticPool) watchWorker(w *Worker) {
 			break
 		}
 	}
+
+	// retired workers are not replaced
+	p.size--
+	replace := !p.destroyed() && p.size < p.Config().NumWorkers
+	if replace {
+		p.size++
+	}
 	p.muw.Unlock()
 
 	// registering a dead worker
@@ -340,13 +608,17 @@ func (p *StaticPool) watchWorker(w *Worker) {
 		p.throw(EventWorkerError, WorkerError{Worker: w, Caused: err})
 	}
 
-	if !p.destroyed() {
+	if replace {
 		nw, err := p.createWorker()
 		if err == nil {
-			p.free <- nw
+			p.push(nw)
 			return
 		}
 
+		p.muw.Lock()
+		p.size--
+		p.muw.Unlock()
+
 		// possible situation when major error causes all PHP scripts to die (for example dead DB)
 		if len(p.Workers()) == 0 {
 			p.throw(EventPoolError, err)
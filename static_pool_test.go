@@ -1,6 +1,7 @@
 package roadrunner
 
 import (
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"log"
 	"os/exec"
@@ -494,3 +495,220 @@ func Benchmark_Pool_Echo_Replaced(b *testing.B) {
 		}
 	}
 }
+
+func Test_StaticPool_Drain(t *testing.T) {
+	p, err := NewPool(
+		func() *exec.Cmd { return exec.Command("go", "worker") },
+		NewInProcessFactory(func(rqs *Payload) (*Payload, error) {
+			time.Sleep(100 * time.Millisecond)
+			return echoFunc(rqs)
+		}),
+		Config{
+			NumWorkers:      1,
+			AllocateTimeout: time.Second,
+			DestroyTimeout:  time.Second,
+			DrainTimeout:    time.Second,
+		},
+	)
+	assert.NoError(t, err)
+
+	events := make(chan DrainEvent, 1)
+	p.Listen(func(event int, ctx interface{}) {
+		if event == EventPoolDrain {
+			events <- ctx.(DrainEvent)
+		}
+	})
+
+	done := make(chan interface{})
+	go func() {
+		defer close(done)
+		res, err := p.Exec(&Payload{Body: []byte("hello")})
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", res.String())
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	p.Destroy()
+	<-done
+
+	e := <-events
+	assert.Equal(t, int64(1), e.Drained)
+	assert.Equal(t, int64(0), e.Aborted)
+
+	_, err = p.Exec(&Payload{Body: []byte("hello")})
+	assert.Equal(t, ErrPoolStopped, err)
+}
+
+func Test_StaticPool_DrainTimeout(t *testing.T) {
+	block := make(chan interface{})
+	defer close(block)
+
+	p, err := NewPool(
+		func() *exec.Cmd { return exec.Command("go", "worker") },
+		NewInProcessFactory(func(rqs *Payload) (*Payload, error) {
+			<-block
+			return echoFunc(rqs)
+		}),
+		Config{
+			NumWorkers:      1,
+			AllocateTimeout: time.Minute,
+			DestroyTimeout:  time.Second,
+			DrainTimeout:    100 * time.Millisecond,
+		},
+	)
+	assert.NoError(t, err)
+
+	events := make(chan DrainEvent, 1)
+	p.Listen(func(event int, ctx interface{}) {
+		if event == EventPoolDrain {
+			events <- ctx.(DrainEvent)
+		}
+	})
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := p.Exec(&Payload{Body: []byte("hello")})
+			errs <- errors.Cause(err)
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	start := time.Now()
+	p.Destroy()
+	assert.True(t, time.Since(start) < time.Second)
+
+	e := <-events
+	assert.Equal(t, int64(0), e.Drained)
+	assert.Equal(t, int64(2), e.Aborted)
+
+	assert.ElementsMatch(t, []error{ErrExecAborted, ErrPoolStopped}, []error{<-errs, <-errs})
+}
+
+func Test_StaticPool_Resize(t *testing.T) {
+	p, err := NewPool(
+		func() *exec.Cmd { return exec.Command("go", "worker") },
+		NewInProcessFactory(echoFunc),
+		Config{
+			NumWorkers:      2,
+			AllocateTimeout: time.Second,
+			DestroyTimeout:  time.Second,
+		},
+	)
+	assert.NoError(t, err)
+	defer p.Destroy()
+
+	workers := p.Workers()
+
+	assert.NoError(t, p.Resize(5))
+	assert.Len(t, p.Workers(), 5)
+	assert.Equal(t, int64(5), p.Config().NumWorkers)
+	assert.Same(t, workers[0], p.Workers()[0])
+	assert.Same(t, workers[1], p.Workers()[1])
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := p.Exec(&Payload{Body: []byte("hello")})
+			assert.NoError(t, err)
+			assert.Equal(t, "hello", res.String())
+		}()
+	}
+	wg.Wait()
+
+	assert.NoError(t, p.Resize(1))
+	assert.Equal(t, int64(1), p.Config().NumWorkers)
+
+	for i := 0; i < 100 && len(p.Workers()) != 1; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Len(t, p.Workers(), 1)
+
+	res, err := p.Exec(&Payload{Body: []byte("hello")})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", res.String())
+
+	assert.Error(t, p.Resize(0))
+}
+
+func Test_StaticPool_Resize_Busy(t *testing.T) {
+	block := make(chan interface{})
+
+	p, err := NewPool(
+		func() *exec.Cmd { return exec.Command("go", "worker") },
+		NewInProcessFactory(func(rqs *Payload) (*Payload, error) {
+			<-block
+			return echoFunc(rqs)
+		}),
+		Config{
+			NumWorkers:      2,
+			AllocateTimeout: time.Second,
+			DestroyTimeout:  time.Second,
+		},
+	)
+	assert.NoError(t, err)
+	defer p.Destroy()
+
+	done := make(chan interface{})
+	go func() {
+		defer close(done)
+		res, err := p.Exec(&Payload{Body: []byte("hello")})
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", res.String())
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	// idle worker is retired first
+	assert.NoError(t, p.Resize(1))
+	for i := 0; i < 100 && len(p.Workers()) != 1; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Len(t, p.Workers(), 1)
+	assert.Equal(t, StateWorking, p.Workers()[0].State().Value())
+
+	close(block)
+	<-done
+}
+
+func Test_StaticPool_Reconfigure(t *testing.T) {
+	p, err := NewPool(
+		func() *exec.Cmd { return exec.Command("go", "worker") },
+		NewInProcessFactory(echoFunc),
+		Config{
+			NumWorkers:      1,
+			AllocateTimeout: time.Second,
+			DestroyTimeout:  time.Second,
+		},
+	)
+	assert.NoError(t, err)
+	defer p.Destroy()
+
+	assert.Error(t, p.Reconfigure(Config{NumWorkers: 1}))
+
+	assert.NoError(t, p.Reconfigure(Config{
+		NumWorkers:      1,
+		MaxJobs:         1,
+		AllocateTimeout: 2 * time.Second,
+		DestroyTimeout:  time.Second,
+	}))
+	assert.Equal(t, int64(1), p.Config().MaxJobs)
+	assert.Equal(t, 2*time.Second, p.Config().AllocateTimeout)
+
+	w := p.Workers()[0]
+	_, err = p.Exec(&Payload{Body: []byte("hello")})
+	assert.NoError(t, err)
+
+	// worker reached the job limit
+	for i := 0; i < 100; i++ {
+		if ws := p.Workers(); len(ws) == 1 && ws[0] != w {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Len(t, p.Workers(), 1)
+	assert.NotSame(t, w, p.Workers()[0])
+}
@@ -0,0 +1,331 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ListenFDsEnv contains listeners passed by the parent process during the binary upgrade in a form of
+// "tcp://0.0.0.0:8080=3;unix://rr.sock=4" where each address is paired with an inherited file descriptor.
+const ListenFDsEnv = "RR_LISTEN_FDS"
+
+// listeners holds active and inherited listeners.
+var listeners = struct {
+	mu        sync.Mutex
+	active    map[string]*listener
+	inherited map[string]*os.File
+}{
+	active:    make(map[string]*listener),
+	inherited: make(map[string]*os.File),
+}
+
+// listener tracks socket listener, so it can be passed to the upgraded process.
+type listener struct {
+	net.Listener
+	address string
+
+	// relay listener does not accept connections while it's exported to the upgraded process
+	relay bool
+
+	mu     sync.Mutex
+	resume chan struct{}
+	closed chan struct{}
+
+	// socket file removed on close, nil if file must be kept
+	socket os.FileInfo
+}
+
+// Accept waits for the next connection, paused listener waits until it's resumed.
+func (l *listener) Accept() (net.Conn, error) {
+	for {
+		l.mu.Lock()
+		resume := l.resume
+		l.mu.Unlock()
+
+		if resume != nil {
+			select {
+			case <-resume:
+			case <-l.closed:
+				return nil, errors.New("use of closed network connection")
+			}
+		}
+
+		conn, err := l.Listener.Accept()
+		if ne, ok := err.(net.Error); ok && ne.Timeout() && l.relay {
+			// accept has been interrupted by pause
+			continue
+		}
+
+		return conn, err
+	}
+}
+
+// Close closes the listener and stops tracking it.
+func (l *listener) Close() error {
+	listeners.mu.Lock()
+	if listeners.active[l.address] == l {
+		delete(listeners.active, l.address)
+	}
+	listeners.mu.Unlock()
+
+	l.mu.Lock()
+	select {
+	case <-l.closed:
+	default:
+		close(l.closed)
+	}
+	socket := l.socket
+	l.socket = nil
+	l.mu.Unlock()
+
+	err := l.Listener.Close()
+
+	// socket file might be replaced by another process (for example by the upgraded server)
+	if socket != nil {
+		path := l.Listener.Addr().String()
+		if info, serr := os.Stat(path); serr == nil && os.SameFile(info, socket) {
+			_ = os.Remove(path)
+		}
+	}
+
+	return err
+}
+
+// pause stops accepting connections, pending Accept is interrupted.
+func (l *listener) pause() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.resume != nil {
+		return
+	}
+
+	l.resume = make(chan struct{})
+	if dl, ok := l.Listener.(interface{ SetDeadline(time.Time) error }); ok {
+		_ = dl.SetDeadline(time.Unix(1, 0))
+	}
+}
+
+// unpause resumes accepting connections.
+func (l *listener) unpause() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.resume == nil {
+		return
+	}
+
+	if dl, ok := l.Listener.(interface{ SetDeadline(time.Time) error }); ok {
+		_ = dl.SetDeadline(time.Time{})
+	}
+
+	close(l.resume)
+	l.resume = nil
+}
+
+// Listen creates socket listener on given network address or adopts listener inherited from
+// the parent process.
+func Listen(network, address string) (net.Listener, error) {
+	dsn := network + "://" + address
+	if ln, err := inherit(dsn); ln != nil || err != nil {
+		return ln, err
+	}
+
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return track(dsn, ln), nil
+}
+
+// CreateRelayListener creates listener of the worker relay connections based on DSN definition or adopts
+// listener inherited from the parent process. Relay connections must be accepted by the process which has
+// started the worker, so exported relay listener stops accepting connections until ResumeListeners is called.
+func CreateRelayListener(address string) (net.Listener, error) {
+	dsn := strings.Split(address, "://")
+	if len(dsn) != 2 {
+		return nil, errors.New("invalid relay DSN (pipes, tcp://:6001, unix://rr.sock)")
+	}
+
+	// socket file is owned by the inherited listener
+	ln, err := inherit(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if ln == nil {
+		if dsn[0] == "unix" && fileExists(dsn[1]) {
+			if err := os.Remove(dsn[1]); err != nil {
+				return nil, err
+			}
+		}
+
+		l, err := net.Listen(dsn[0], dsn[1])
+		if err != nil {
+			return nil, err
+		}
+
+		ln = track(address, l)
+	}
+
+	listeners.mu.Lock()
+	ln.(*listener).relay = true
+	listeners.mu.Unlock()
+
+	return ln, nil
+}
+
+// ExportListeners returns files of all active listeners and the value of ListenFDsEnv variable to be
+// passed to the child process. File descriptors are numbered in the ExtraFiles order (starting from 3).
+// Relay listeners are paused once exported.
+func ExportListeners() (files []*os.File, env string, err error) {
+	listeners.mu.Lock()
+	defer listeners.mu.Unlock()
+
+	addresses := make([]string, 0, len(listeners.active))
+	for address := range listeners.active {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	fds := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		fl, ok := listeners.active[address].Listener.(interface{ File() (*os.File, error) })
+		if !ok {
+			err = fmt.Errorf("unable to export listener `%s`", address)
+			break
+		}
+
+		f, ferr := fl.File()
+		if ferr != nil {
+			err = fmt.Errorf("unable to export listener `%s`: %v", address, ferr)
+			break
+		}
+
+		files = append(files, f)
+		fds = append(fds, fmt.Sprintf("%s=%v", address, 2+len(files)))
+	}
+
+	if err != nil {
+		for _, f := range files {
+			_ = f.Close()
+		}
+
+		return nil, "", err
+	}
+
+	for _, l := range listeners.active {
+		if l.relay {
+			l.pause()
+		}
+	}
+
+	return files, ListenFDsEnv + "=" + strings.Join(fds, ";"), nil
+}
+
+// ResumeListeners resumes relay listeners paused by ExportListeners, must be called if upgrade has failed.
+func ResumeListeners() {
+	listeners.mu.Lock()
+	defer listeners.mu.Unlock()
+
+	for _, l := range listeners.active {
+		l.unpause()
+	}
+}
+
+// DetachListeners makes sure that closing of the active unix listeners won't remove socket files
+// which are used by the upgraded process.
+func DetachListeners() {
+	listeners.mu.Lock()
+	defer listeners.mu.Unlock()
+
+	for _, l := range listeners.active {
+		l.mu.Lock()
+		l.socket = nil
+		l.mu.Unlock()
+	}
+}
+
+// CloseInherited closes inherited file descriptors which were not adopted by any of the listeners.
+func CloseInherited() {
+	listeners.mu.Lock()
+	defer listeners.mu.Unlock()
+
+	for address, f := range listeners.inherited {
+		_ = f.Close()
+		delete(listeners.inherited, address)
+	}
+}
+
+// inherit returns listener inherited from the parent process, nil if listener is not available.
+func inherit(address string) (net.Listener, error) {
+	listeners.mu.Lock()
+	f, ok := listeners.inherited[address]
+	delete(listeners.inherited, address)
+	listeners.mu.Unlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	defer f.Close()
+
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to adopt inherited listener `%s`: %v", address, err)
+	}
+
+	return track(address, ln), nil
+}
+
+// track registers active listener, socket file of the unix listener is removed on close only if it has not
+// been replaced since the listener was created.
+func track(address string, ln net.Listener) net.Listener {
+	l := &listener{Listener: ln, address: address, closed: make(chan struct{})}
+
+	if ul, ok := ln.(*net.UnixListener); ok {
+		ul.SetUnlinkOnClose(false)
+		if info, err := os.Stat(ul.Addr().String()); err == nil {
+			l.socket = info
+		}
+	}
+
+	listeners.mu.Lock()
+	listeners.active[address] = l
+	listeners.mu.Unlock()
+
+	return l
+}
+
+// init parses ListenFDsEnv, the variable is removed from the environment and inherited descriptors
+// are closed on exec, so they won't be passed to the workers.
+func init() {
+	value := os.Getenv(ListenFDsEnv)
+	if value == "" {
+		return
+	}
+	_ = os.Unsetenv(ListenFDsEnv)
+
+	for _, pair := range strings.Split(value, ";") {
+		i := strings.LastIndex(pair, "=")
+		if i == -1 {
+			continue
+		}
+
+		fd, err := strconv.Atoi(pair[i+1:])
+		if err != nil || fd < 3 {
+			continue
+		}
+
+		closeOnExec(fd)
+		listeners.inherited[pair[:i]] = os.NewFile(uintptr(fd), pair[:i])
+	}
+}
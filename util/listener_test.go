@@ -0,0 +1,184 @@
+// +build linux darwin freebsd
+
+package util
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListen_Export(t *testing.T) {
+	l, err := Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	files, env, err := ExportListeners()
+	assert.NoError(t, err)
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+
+	assert.Len(t, files, 1)
+	assert.Equal(t, ListenFDsEnv+"=tcp://127.0.0.1:0=3", env)
+
+	assert.NoError(t, l.Close())
+
+	files2, env, err := ExportListeners()
+	assert.NoError(t, err)
+	assert.Len(t, files2, 0)
+	assert.Equal(t, ListenFDsEnv+"=", env)
+}
+
+func TestListen_Inherit(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+
+	f, err := ln.(*net.TCPListener).File()
+	assert.NoError(t, err)
+
+	address := ln.Addr().String()
+	listeners.mu.Lock()
+	listeners.inherited["tcp://"+address] = f
+	listeners.mu.Unlock()
+
+	l, err := Listen("tcp", address)
+	assert.NoError(t, err)
+	defer l.Close()
+
+	// same socket
+	assert.Equal(t, address, l.Addr().String())
+
+	go func() {
+		conn, err := net.Dial("tcp", address)
+		if assert.NoError(t, err) {
+			_ = conn.Close()
+		}
+	}()
+
+	conn, err := l.Accept()
+	assert.NoError(t, err)
+	_ = conn.Close()
+}
+
+func TestCreateRelayListener_Pause(t *testing.T) {
+	l, err := CreateRelayListener("tcp://127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+
+	address := l.Addr().String()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				close(accepted)
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	files, _, err := ExportListeners()
+	assert.NoError(t, err)
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+
+	// exported relay listener does not accept connections
+	conn, err := net.Dial("tcp", address)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	select {
+	case <-accepted:
+		t.Fatal("connection accepted by paused listener")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	ResumeListeners()
+
+	select {
+	case c := <-accepted:
+		assert.NotNil(t, c)
+		_ = c.Close()
+	case <-time.After(time.Second):
+		t.Fatal("connection is not accepted after resume")
+	}
+
+	// closing of the listener stops accept
+	ln, _ := CreateRelayListener("tcp://127.0.0.1:0")
+	files2, _, err := ExportListeners()
+	assert.NoError(t, err)
+	for _, f := range files2 {
+		_ = f.Close()
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := ln.Accept()
+		errc <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, ln.Close())
+	assert.Error(t, <-errc)
+	ResumeListeners()
+}
+
+func TestDetachListeners(t *testing.T) {
+	l, err := CreateListener("unix://detach.sock")
+	assert.NoError(t, err)
+
+	DetachListeners()
+	assert.NoError(t, l.Close())
+
+	// socket file is kept for the upgraded process
+	_, err = os.Stat("detach.sock")
+	assert.NoError(t, err)
+	assert.NoError(t, os.Remove("detach.sock"))
+}
+
+func TestCreateRelayListener_Replaced(t *testing.T) {
+	l, err := CreateRelayListener("unix://relay.sock")
+	assert.NoError(t, err)
+
+	// socket file replaced by another process is not removed on close
+	assert.NoError(t, os.Remove("relay.sock"))
+	assert.NoError(t, ioutil.WriteFile("relay.sock", nil, 0644))
+	assert.NoError(t, l.Close())
+
+	_, err = os.Stat("relay.sock")
+	assert.NoError(t, err)
+	assert.NoError(t, os.Remove("relay.sock"))
+
+	l, err = CreateRelayListener("unix://relay.sock")
+	assert.NoError(t, err)
+	assert.NoError(t, l.Close())
+
+	_, err = os.Stat("relay.sock")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCloseInherited(t *testing.T) {
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	defer r.Close()
+
+	listeners.mu.Lock()
+	listeners.inherited["tcp://:1"] = w
+	listeners.mu.Unlock()
+
+	CloseInherited()
+
+	_, err = w.Write([]byte("test"))
+	assert.True(t, err != nil && strings.Contains(err.Error(), "closed"))
+}
@@ -12,7 +12,8 @@ import (
 	"syscall"
 )
 
-// CreateListener crates socket listener based on DSN definition.
+// CreateListener crates socket listener based on DSN definition or adopts listener inherited from
+// the parent process.
 func CreateListener(address string) (net.Listener, error) {
 	dsn := strings.Split(address, "://")
 	if len(dsn) != 2 {
@@ -23,6 +24,11 @@ func CreateListener(address string) (net.Listener, error) {
 		return nil, errors.New("invalid Protocol (tcp://:6001, unix://file.sock)")
 	}
 
+	// socket file is owned by the inherited listener
+	if ln, err := inherit(address); ln != nil || err != nil {
+		return ln, err
+	}
+
 	if dsn[0] == "unix" && fileExists(dsn[1]) {
 		err := syscall.Unlink(dsn[1])
 		if err != nil {
@@ -37,12 +43,23 @@ func CreateListener(address string) (net.Listener, error) {
 		Backlog:     0,
 	}
 
+	var (
+		ln  net.Listener
+		err error
+	)
+
 	// tcp4 is currently supported
 	if dsn[0] == "tcp" {
-		return cfg.NewListener("tcp4", dsn[1])
+		ln, err = cfg.NewListener("tcp4", dsn[1])
+	} else {
+		ln, err = net.Listen(dsn[0], dsn[1])
 	}
 
-	return net.Listen(dsn[0], dsn[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return track(address, ln), nil
 }
 
 // fileExists checks if a file exists and is not a directory before we
@@ -54,3 +71,8 @@ func fileExists(filename string) bool {
 	}
 	return !info.IsDir()
 }
+
+// closeOnExec prevents file descriptor from being inherited by child processes.
+func closeOnExec(fd int) {
+	syscall.CloseOnExec(fd)
+}
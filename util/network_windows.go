@@ -11,7 +11,8 @@ import (
 	"syscall"
 )
 
-// CreateListener crates socket listener based on DSN definition.
+// CreateListener crates socket listener based on DSN definition or adopts listener inherited from
+// the parent process.
 func CreateListener(address string) (net.Listener, error) {
 	dsn := strings.Split(address, "://")
 	if len(dsn) != 2 {
@@ -22,6 +23,11 @@ func CreateListener(address string) (net.Listener, error) {
 		return nil, errors.New("invalid Protocol (tcp://:6001, unix://file.sock)")
 	}
 
+	// socket file is owned by the inherited listener
+	if ln, err := inherit(address); ln != nil || err != nil {
+		return ln, err
+	}
+
 	if dsn[0] == "unix" && fileExists(dsn[1]) {
 		err := syscall.Unlink(dsn[1])
 		if err != nil {
@@ -29,7 +35,12 @@ func CreateListener(address string) (net.Listener, error) {
 		}
 	}
 
-	return net.Listen(dsn[0], dsn[1])
+	ln, err := net.Listen(dsn[0], dsn[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return track(address, ln), nil
 }
 
 // fileExists checks if a file exists and is not a directory before we
@@ -41,3 +52,6 @@ func fileExists(filename string) bool {
 	}
 	return !info.IsDir()
 }
+
+// closeOnExec does nothing, inherited listeners are not supported on windows.
+func closeOnExec(fd int) {}
@@ -0,0 +1,192 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxProxyV1Length is the maximum length of the PROXY protocol v1 header including CRLF.
+const maxProxyV1Length = 107
+
+// proxyV2Signature starts every PROXY protocol v2 header.
+var proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// errNoProxyHeader returned when connection does not start with PROXY protocol header.
+var errNoProxyHeader = errors.New("missing PROXY protocol header")
+
+// ProxyListener wraps listener to accept connections which start with PROXY protocol (v1 or v2) header, remote
+// address of the connection is replaced with the client address provided by the header. Header must be received
+// within given timeout (0 to wait indefinitely), connections without valid header are closed.
+func ProxyListener(ln net.Listener, timeout time.Duration) net.Listener {
+	return &proxyListener{Listener: ln, timeout: timeout}
+}
+
+type proxyListener struct {
+	net.Listener
+	timeout time.Duration
+}
+
+// Accept waits for and returns the next connection, PROXY header is read on first use of the connection.
+func (l *proxyListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proxyConn{Conn: conn, r: bufio.NewReader(conn), timeout: l.timeout}, nil
+}
+
+// proxyConn reads PROXY header prior to any connection data.
+type proxyConn struct {
+	net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+
+	once   sync.Once
+	remote net.Addr
+	err    error
+}
+
+// Read reads connection data following the PROXY header.
+func (c *proxyConn) Read(b []byte) (int, error) {
+	if err := c.readHeader(); err != nil {
+		return 0, err
+	}
+
+	return c.r.Read(b)
+}
+
+// RemoteAddr returns client address provided by the PROXY header, address of the connection peer is returned
+// when header does not contain client address.
+func (c *proxyConn) RemoteAddr() net.Addr {
+	if c.readHeader() != nil || c.remote == nil {
+		return c.Conn.RemoteAddr()
+	}
+
+	return c.remote
+}
+
+// readHeader reads PROXY header once, connection is closed if header is not valid.
+func (c *proxyConn) readHeader() error {
+	c.once.Do(func() {
+		if c.timeout != 0 {
+			_ = c.Conn.SetReadDeadline(time.Now().Add(c.timeout))
+		}
+
+		c.remote, c.err = readProxyHeader(c.r)
+
+		if c.timeout != 0 {
+			_ = c.Conn.SetReadDeadline(time.Time{})
+		}
+
+		if c.err != nil {
+			_ = c.Conn.Close()
+		}
+	})
+
+	return c.err
+}
+
+// readProxyHeader reads PROXY header of any supported version, returns nil address for LOCAL and UNKNOWN
+// connections.
+func readProxyHeader(r *bufio.Reader) (net.Addr, error) {
+	sig, err := r.Peek(len(proxyV2Signature))
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bytes.Equal(sig, proxyV2Signature):
+		return readProxyV2(r)
+	case bytes.HasPrefix(sig, []byte("PROXY ")):
+		return readProxyV1(r)
+	default:
+		return nil, errNoProxyHeader
+	}
+}
+
+// readProxyV1 reads human readable header: "PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n".
+func readProxyV1(r *bufio.Reader) (net.Addr, error) {
+	line := make([]byte, 0, maxProxyV1Length)
+	for !bytes.HasSuffix(line, []byte("\r\n")) {
+		if len(line) == maxProxyV1Length {
+			return nil, errors.New("PROXY header is too long")
+		}
+
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, b)
+	}
+
+	fields := strings.Split(strings.TrimSuffix(string(line), "\r\n"), " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, fmt.Errorf("malformed PROXY header `%s`", strings.TrimSpace(string(line)))
+	}
+
+	ip := net.ParseIP(fields[2])
+	if ip == nil {
+		return nil, fmt.Errorf("invalid PROXY source address `%s`", fields[2])
+	}
+
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROXY source port `%s`", fields[4])
+	}
+
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+// readProxyV2 reads binary header, only TCP over IPv4 and IPv6 addresses are used.
+func readProxyV2(r *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, len(proxyV2Signature)+4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	verCmd, family := header[12], header[13]
+	if verCmd>>4 != 2 {
+		return nil, fmt.Errorf("unsupported PROXY protocol version %v", verCmd>>4)
+	}
+
+	data := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	// LOCAL command, connection established by the proxy itself
+	if verCmd&0xF == 0 {
+		return nil, nil
+	}
+
+	switch family {
+	case 0x11: // TCP over IPv4
+		if len(data) < 12 {
+			return nil, errors.New("malformed PROXY header")
+		}
+
+		return &net.TCPAddr{IP: net.IP(data[0:4]), Port: int(binary.BigEndian.Uint16(data[8:10]))}, nil
+	case 0x21: // TCP over IPv6
+		if len(data) < 36 {
+			return nil, errors.New("malformed PROXY header")
+		}
+
+		return &net.TCPAddr{IP: net.IP(data[0:16]), Port: int(binary.BigEndian.Uint16(data[32:34]))}, nil
+	default:
+		return nil, nil
+	}
+}
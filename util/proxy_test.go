@@ -0,0 +1,126 @@
+package util
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadProxyHeader_V1(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\nGET / HTTP/1.1\r\n"))
+
+	addr, err := readProxyHeader(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.0.1:56324", addr.String())
+
+	rest, _ := ioutil.ReadAll(r)
+	assert.Equal(t, "GET / HTTP/1.1\r\n", string(rest))
+
+	addr, err = readProxyHeader(bufio.NewReader(strings.NewReader("PROXY TCP6 ::1 ::1 1000 443\r\n")))
+	assert.NoError(t, err)
+	assert.Equal(t, "[::1]:1000", addr.String())
+
+	addr, err = readProxyHeader(bufio.NewReader(strings.NewReader("PROXY UNKNOWN\r\n")))
+	assert.NoError(t, err)
+	assert.Nil(t, addr)
+}
+
+func TestReadProxyHeader_V1_Error(t *testing.T) {
+	for _, h := range []string{
+		"GET / HTTP/1.1\r\n",
+		"PROXY TCP4 invalid 192.168.0.11 56324 443\r\n",
+		"PROXY TCP4 192.168.0.1 192.168.0.11 port 443\r\n",
+		"PROXY UDP4 192.168.0.1 192.168.0.11 56324 443\r\n",
+		"PROXY TCP4 " + strings.Repeat("1", 120) + "\r\n",
+	} {
+		_, err := readProxyHeader(bufio.NewReader(strings.NewReader(h)))
+		assert.Error(t, err, h)
+	}
+}
+
+func TestReadProxyHeader_V2(t *testing.T) {
+	header := append([]byte{}, proxyV2Signature...)
+	header = append(header, 0x21, 0x11, 0x00, 0x0C)
+	header = append(header, 10, 0, 0, 1, 10, 0, 0, 2, 0x1F, 0x90, 0x01, 0xBB)
+	header = append(header, []byte("data")...)
+
+	r := bufio.NewReader(strings.NewReader(string(header)))
+	addr, err := readProxyHeader(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1:8080", addr.String())
+
+	rest, _ := ioutil.ReadAll(r)
+	assert.Equal(t, "data", string(rest))
+
+	// LOCAL command
+	header = append([]byte{}, proxyV2Signature...)
+	header = append(header, 0x20, 0x00, 0x00, 0x00)
+
+	addr, err = readProxyHeader(bufio.NewReader(strings.NewReader(string(header))))
+	assert.NoError(t, err)
+	assert.Nil(t, addr)
+
+	// unsupported version
+	header = append([]byte{}, proxyV2Signature...)
+	header = append(header, 0x11, 0x00, 0x00, 0x00)
+
+	_, err = readProxyHeader(bufio.NewReader(strings.NewReader(string(header))))
+	assert.Error(t, err)
+}
+
+func TestProxyListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	pl := ProxyListener(ln, time.Second)
+	defer pl.Close()
+
+	go func() {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = conn.Write([]byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\nhello"))
+	}()
+
+	conn, err := pl.Accept()
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	assert.Equal(t, "192.168.0.1:56324", conn.RemoteAddr().String())
+
+	data, err := ioutil.ReadAll(conn)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestProxyListener_NoHeader(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	pl := ProxyListener(ln, time.Second)
+	defer pl.Close()
+
+	go func() {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = conn.Write([]byte("GET / HTTP/1.1\r\n\r\n"))
+	}()
+
+	conn, err := pl.Accept()
+	assert.NoError(t, err)
+
+	_, err = conn.Read(make([]byte, 10))
+	assert.Error(t, err)
+}
@@ -2,11 +2,11 @@ package roadrunner
 
 import (
 	"fmt"
-	"os"
 	"os/exec"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -33,6 +33,9 @@ type Worker struct {
 	// stdErr direction will be handled by worker to aggregate error message.
 	cmd *exec.Cmd
 
+	// proc controls underlying process, by default system process created using cmd.
+	proc process
+
 	// err aggregates stderr output from underlying process. Value can be
 	// receive only once command is completed and all pipes are closed.
 	err *errBuffer
@@ -40,8 +43,14 @@ type Worker struct {
 	// channel is being closed once command is complete.
 	waitDone chan interface{}
 
-	// contains information about resulted process state.
-	endState *os.ProcessState
+	// contains process completion error, nil when process exited successfully.
+	endErr error
+
+	// describes worker termination (*WorkerExit), available once Wait is complete.
+	exit atomic.Value
+
+	// tag of the payload currently executed by the worker.
+	tag atomic.Value
 
 	// ensures than only one execution can be run at once.
 	mu sync.Mutex
@@ -64,6 +73,9 @@ func newWorker(cmd *exec.Cmd) (*Worker, error) {
 		state:    newState(StateInactive),
 	}
 
+	w.proc = &osProcess{cmd: cmd}
+	w.err.tag = w.Tag
+
 	// piping all stderr to command errBuffer
 	w.cmd.Stderr = w.err
 
@@ -92,8 +104,8 @@ func (w *Worker) String() string {
 }
 
 // Wait must be called once for each worker, call will be released once worker is
-// complete and will return process error (if any), if stderr is presented it's value
-// will be wrapped as WorkerError. Method will return error code if php process fails
+// complete and will return process error (if any) as *WorkerExit, if stderr is presented
+// it's value is used as error message. Method will return error code if php process fails
 // to find or start the script.
 func (w *Worker) Wait() error {
 	<-w.waitDone
@@ -102,7 +114,10 @@ func (w *Worker) Wait() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	if w.endState.Success() {
+	exit := newWorkerExit(w)
+	w.exit.Store(exit)
+
+	if exit.Cause == ExitClean {
 		w.state.set(StateStopped)
 		return nil
 	}
@@ -113,12 +128,13 @@ func (w *Worker) Wait() error {
 		w.state.set(StateStopped)
 	}
 
-	if w.err.Len() != 0 {
-		return errors.New(w.err.String())
-	}
+	return exit
+}
 
-	// generic process error
-	return &exec.ExitError{ProcessState: w.endState}
+// Exit returns description of worker termination, nil until Wait is complete.
+func (w *Worker) Exit() *WorkerExit {
+	exit, _ := w.exit.Load().(*WorkerExit)
+	return exit
 }
 
 // Stop sends soft termination command to the worker and waits for process completion.
@@ -146,13 +162,19 @@ func (w *Worker) Kill() error {
 		return nil
 	default:
 		w.state.set(StateStopping)
-		err := w.cmd.Process.Signal(os.Kill)
+		err := w.proc.kill()
 
 		<-w.waitDone
 		return err
 	}
 }
 
+// Tag returns tag of the payload currently executed by the worker, empty if worker is idle or payload has no tag.
+func (w *Worker) Tag() string {
+	tag, _ := w.tag.Load().(string)
+	return tag
+}
+
 // Exec sends payload to worker, executes it and returns result or
 // error. Make sure to handle worker.Wait() to gather worker level
 // errors. Method might return JobError indicating issue with payload.
@@ -170,8 +192,10 @@ func (w *Worker) Exec(rqs *Payload) (rsp *Payload, err error) {
 	}
 
 	w.state.set(StateWorking)
+	w.tag.Store(rqs.Tag)
 
 	rsp, err = w.execPayload(rqs)
+	w.tag.Store("")
 	if err != nil {
 		if _, ok := err.(JobError); !ok {
 			w.state.set(StateErrored)
@@ -192,16 +216,17 @@ func (w *Worker) markInvalid() {
 }
 
 func (w *Worker) start() error {
-	if err := w.cmd.Start(); err != nil {
+	if err := w.proc.start(); err != nil {
 		close(w.waitDone)
 		return err
 	}
 
-	w.Pid = &w.cmd.Process.Pid
+	pid := w.proc.pid()
+	w.Pid = &pid
 
 	// wait for process to complete
 	go func() {
-		w.endState, _ = w.cmd.Process.Wait()
+		w.endErr = w.proc.wait()
 		if w.waitDone != nil {
 			close(w.waitDone)
 			w.mu.Lock()
@@ -238,21 +263,34 @@ func (w *Worker) execPayload(rqs *Payload) (rsp *Payload, err error) {
 	rsp = new(Payload)
 
 	if rsp.Context, pr, err = w.rl.Receive(); err != nil {
-		return nil, errors.Wrap(err, "worker error")
+		return nil, w.relayError(err)
 	}
 
 	if !pr.HasFlag(goridge.PayloadControl) {
-		return nil, fmt.Errorf("malformed worker response")
+		return nil, ErrMalformedResponse
 	}
 
 	if pr.HasFlag(goridge.PayloadError) {
 		return nil, JobError(rsp.Context)
 	}
 
+	if w.Pid != nil {
+		rsp.pid = *w.Pid
+	}
+
 	// add streaming support :)
 	if rsp.Body, _, err = w.rl.Receive(); err != nil {
-		return nil, errors.Wrap(err, "worker error")
+		return nil, w.relayError(err)
 	}
 
 	return rsp, nil
 }
+
+// relayError wraps relay receive error, non protocol output is reported as StrayOutputError.
+func (w *Worker) relayError(err error) error {
+	if so, ok := err.(strayOutput); ok {
+		return StrayOutputError{Worker: w, Pid: *w.Pid, Output: so}
+	}
+
+	return errors.Wrap(err, "worker error")
+}
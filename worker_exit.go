@@ -0,0 +1,138 @@
+package roadrunner
+
+import (
+	"fmt"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// ExitCause classifies worker termination.
+type ExitCause int
+
+const (
+	// ExitClean - worker exited with zero code.
+	ExitClean ExitCause = iota
+
+	// ExitFatal - worker exited with non zero code (fatal error, uncaught exception, exit call).
+	ExitFatal
+
+	// ExitSignal - worker was terminated by signal.
+	ExitSignal
+
+	// ExitOOM - worker was killed by SIGKILL which was not sent by the server, most likely by
+	// the kernel OOM killer.
+	ExitOOM
+)
+
+// String returns cause name.
+func (c ExitCause) String() string {
+	switch c {
+	case ExitClean:
+		return "clean"
+	case ExitFatal:
+		return "fatal"
+	case ExitSignal:
+		return "signal"
+	case ExitOOM:
+		return "oom"
+	}
+
+	return "undefined"
+}
+
+// WorkerExit describes worker termination. Returned by Worker.Wait as error when worker
+// did not exit cleanly and available using Worker.Exit after the worker is complete.
+type WorkerExit struct {
+	// Pid of the worker process.
+	Pid int
+
+	// Cause classifies termination reason.
+	Cause ExitCause
+
+	// ExitCode contains process exit code, -1 when process was terminated by signal.
+	ExitCode int
+
+	// Signal which terminated the process, 0 if none.
+	Signal syscall.Signal
+
+	// CoreDump is true when process dumped core.
+	CoreDump bool
+
+	// Runtime defines for how long worker has been alive.
+	Runtime time.Duration
+
+	// NumExecs contains number of worker executions.
+	NumExecs int64
+
+	// LastExec contains time of the last worker execution, zero if worker never executed.
+	LastExec time.Time
+
+	// Stderr contains last lines written by the worker into stderr (see ExitStderrLines).
+	Stderr []string
+
+	// stderr output which was not passed to the listener yet.
+	output string
+}
+
+// Error returns unread stderr output or process status.
+func (e *WorkerExit) Error() string {
+	if e.output != "" {
+		return e.output
+	}
+
+	if e.Signal != 0 {
+		return fmt.Sprintf("signal: %s", e.Signal)
+	}
+
+	return fmt.Sprintf("exit status %v", e.ExitCode)
+}
+
+// newWorkerExit describes termination of the complete worker.
+func newWorkerExit(w *Worker) *WorkerExit {
+	e := &WorkerExit{
+		Pid:      *w.Pid,
+		Runtime:  time.Since(w.Created),
+		NumExecs: w.state.NumExecs(),
+		LastExec: w.state.lastExec(),
+		Stderr:   w.err.Tail(),
+		output:   w.err.String(),
+	}
+
+	switch err := w.endErr.(type) {
+	case nil:
+		e.Cause = ExitClean
+		return e
+	case *exec.ExitError:
+		if ws, ok := err.Sys().(syscall.WaitStatus); ok {
+			e.ExitCode, e.Signal, e.CoreDump = ws.ExitStatus(), signal(ws), ws.CoreDump()
+		} else {
+			e.ExitCode = err.ExitCode()
+		}
+	case *exitError:
+		e.ExitCode, e.Signal = err.code, err.signal
+	default:
+		e.ExitCode = -1
+	}
+
+	e.Cause = ExitFatal
+	if e.Signal != 0 {
+		e.ExitCode, e.Cause = -1, ExitSignal
+
+		// killed, but not by the server
+		if e.Signal == syscall.SIGKILL && w.state.Value() != StateStopping {
+			e.Cause = ExitOOM
+		}
+	}
+
+	return e
+}
+
+// signal returns signal which terminated the process, if any.
+func signal(ws syscall.WaitStatus) syscall.Signal {
+	if ws.Signaled() {
+		return ws.Signal()
+	}
+
+	return 0
+}
@@ -0,0 +1,129 @@
+package roadrunner
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WorkerExit_Clean(t *testing.T) {
+	w, err := NewInProcessFactory(echoFunc).SpawnWorker(exec.Command("go", "worker"))
+	assert.NoError(t, err)
+	assert.Nil(t, w.Exit())
+
+	_, err = w.Exec(&Payload{Body: []byte("hello")})
+	assert.NoError(t, err)
+
+	go func() {
+		assert.NoError(t, w.Stop())
+	}()
+	assert.NoError(t, w.Wait())
+
+	exit := w.Exit()
+	assert.NotNil(t, exit)
+	assert.Equal(t, ExitClean, exit.Cause)
+	assert.Equal(t, 0, exit.ExitCode)
+	assert.Equal(t, int64(1), exit.NumExecs)
+	assert.False(t, exit.LastExec.IsZero())
+	assert.True(t, exit.Runtime > 0)
+}
+
+func Test_WorkerExit_Panic(t *testing.T) {
+	w, err := NewInProcessFactory(func(rqs *Payload) (*Payload, error) {
+		panic("broken worker")
+	}).SpawnWorker(exec.Command("go", "worker"))
+	assert.NoError(t, err)
+
+	_, err = w.Exec(&Payload{Body: []byte("hello")})
+	assert.Error(t, err)
+
+	err = w.Wait()
+	assert.IsType(t, &WorkerExit{}, err)
+
+	exit := err.(*WorkerExit)
+	assert.Equal(t, w.Exit(), exit)
+	assert.Equal(t, ExitFatal, exit.Cause)
+	assert.Equal(t, 2, exit.ExitCode)
+	assert.Len(t, exit.Stderr, ExitStderrLines)
+	assert.Contains(t, exit.Error(), "panic: broken worker")
+}
+
+func Test_WorkerExit_Kill(t *testing.T) {
+	w, err := NewInProcessFactory(echoFunc).SpawnWorker(exec.Command("go", "worker"))
+	assert.NoError(t, err)
+
+	go func() {
+		assert.NoError(t, w.Kill())
+	}()
+
+	err = w.Wait()
+	assert.Error(t, err)
+	assert.Equal(t, "signal: killed", err.Error())
+
+	exit := w.Exit()
+	assert.Equal(t, ExitSignal, exit.Cause)
+	assert.Equal(t, syscall.SIGKILL, exit.Signal)
+	assert.Equal(t, -1, exit.ExitCode)
+}
+
+func Test_WorkerExit_OOM(t *testing.T) {
+	w, err := NewInProcessFactory(echoFunc).SpawnWorker(exec.Command("go", "worker"))
+	assert.NoError(t, err)
+
+	// killed outside of the server
+	assert.NoError(t, w.proc.kill())
+
+	assert.Error(t, w.Wait())
+	assert.Equal(t, ExitOOM, w.Exit().Cause)
+	assert.Equal(t, StateErrored, w.State().Value())
+}
+
+func Test_WorkerExit_Process(t *testing.T) {
+	w, err := newWorker(exec.Command("sh", "-c", "echo 'fatal error' >&2; sleep 0.1; exit 255"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.start())
+
+	err = w.Wait()
+	assert.IsType(t, &WorkerExit{}, err)
+	assert.Equal(t, "fatal error\n", err.Error())
+
+	exit := w.Exit()
+	assert.Equal(t, ExitFatal, exit.Cause)
+	assert.Equal(t, 255, exit.ExitCode)
+	assert.Equal(t, []string{"fatal error"}, exit.Stderr)
+	assert.Equal(t, *w.Pid, exit.Pid)
+}
+
+func Test_WorkerExit_ProcessSignal(t *testing.T) {
+	w, err := newWorker(exec.Command("sh", "-c", "kill -TERM $$"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.start())
+
+	err = w.Wait()
+	assert.Error(t, err)
+	assert.Equal(t, "signal: terminated", err.Error())
+
+	exit := w.Exit()
+	assert.Equal(t, ExitSignal, exit.Cause)
+	assert.Equal(t, syscall.SIGTERM, exit.Signal)
+	assert.Equal(t, -1, exit.ExitCode)
+}
+
+func Test_WorkerExit_ProcessOOM(t *testing.T) {
+	w, err := newWorker(exec.Command("sh", "-c", "kill -KILL $$"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.start())
+
+	assert.Error(t, w.Wait())
+	assert.Equal(t, ExitOOM, w.Exit().Cause)
+}
+
+func Test_ExitCause_String(t *testing.T) {
+	assert.Equal(t, "clean", ExitClean.String())
+	assert.Equal(t, "fatal", ExitFatal.String())
+	assert.Equal(t, "signal", ExitSignal.String())
+	assert.Equal(t, "oom", ExitOOM.String())
+	assert.Equal(t, "undefined", ExitCause(100).String())
+}